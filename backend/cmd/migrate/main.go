@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chotamkz/career-track-backend/internal/config"
+	"github.com/chotamkz/career-track-backend/internal/db"
+	"github.com/chotamkz/career-track-backend/internal/util"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+const commandTimeout = 10 * time.Minute
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: migrate [-dir path] [-steps n] up|down|status|redo\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	logger := util.NewLogger("info")
+
+	if err := godotenv.Load(".env"); err != nil {
+		logger.Warn("WARNING: .env file not found; using system environment variables")
+	}
+	cfg := config.LoadConfig()
+	logger = util.NewLogger(cfg.LogLevel)
+
+	dir := flag.String("dir", cfg.MigrationsDir, "directory containing NNNN_name.up.sql/.down.sql files")
+	steps := flag.Int("steps", 1, "number of migrations to roll back with down")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	dbConn, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatalf("Failed to open DB connection: %v", err)
+	}
+	defer dbConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if err := dbConn.PingContext(ctx); err != nil {
+		logger.Fatalf("Failed to ping database: %v", err)
+	}
+
+	migrator := db.NewMigrator(dbConn, *dir, logger)
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logger.Fatalf("Migration up failed: %v", err)
+		}
+		logger.Infof("%d migration(s) applied", applied)
+	case "down":
+		rolledBack, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			logger.Fatalf("Migration down failed: %v", err)
+		}
+		logger.Infof("%d migration(s) rolled back", rolledBack)
+	case "redo":
+		if err := migrator.Redo(ctx); err != nil {
+			logger.Fatalf("Migration redo failed: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Fatalf("Migration status failed: %v", err)
+		}
+		printStatus(statuses)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func printStatus(statuses []db.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, st := range statuses {
+		state := "pending"
+		appliedAt := ""
+		if st.Applied {
+			state = "applied"
+			if st.Modified {
+				state = "applied (modified)"
+			}
+			appliedAt = st.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	w.Flush()
+}
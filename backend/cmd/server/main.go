@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"github.com/chotamkz/career-track-backend/internal/config"
 	"github.com/chotamkz/career-track-backend/internal/db"
+	"github.com/chotamkz/career-track-backend/internal/mail"
+	"github.com/chotamkz/career-track-backend/internal/storage"
 	httpDelivery "github.com/chotamkz/career-track-backend/internal/transport/http"
 	"github.com/chotamkz/career-track-backend/internal/util"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -23,14 +26,13 @@ const (
 	dbConnMaxLifetime = 5 * time.Minute
 	dbMaxOpenConns    = 5
 	dbMaxIdleConns    = 5
-	dbPingTimeout     = 30 * time.Second
+	// The lock pool has no open limit: each background job pins at most one
+	// of its connections, so it never holds more than there are jobs.
+	lockDBMaxIdleConns = 2
+	dbPingTimeout      = 30 * time.Second
 
-	schedulerPerPage     = 50
-	schedulerTotalPages  = 5
-	schedulerRefreshRate = 1 * time.Hour
-
-	shutdownTimeout = 30 * time.Second
-	migrationPath   = "./migrations/0001_create_tables.sql"
+	shutdownTimeout  = 30 * time.Second
+	migrationTimeout = 5 * time.Minute
 )
 
 func main() {
@@ -45,7 +47,7 @@ func main() {
 	logger = util.NewLogger(cfg.LogLevel)
 	logger.Info("Configuration loaded successfully")
 
-	dbConn, err := initializeDB(cfg.DatabaseURL, logger)
+	dbConn, err := initializeDB(cfg.DatabaseURL, cfg.MigrationsDir, logger)
 	if err != nil {
 		logger.Fatalf("Database initialization failed", "error", err)
 	}
@@ -55,32 +57,51 @@ func main() {
 		}
 	}()
 
-	//--parsing hh vacancies
-	/*	userRepo := postgres.NewUserRepo(dbConn, logger)
-		vacancyRepo := postgres.NewVacancyRepo(dbConn)
-		skillRepo := postgres.NewSkillRepo(dbConn)
-		vacancyUse := usecase.NewVacancyUsecase(vacancyRepo, skillRepo, cfg.MLServiceURL)
-
-		vacancyScheduler := scheduler.NewVacancyScheduler(
-			dbConn,
-			userRepo,
-			vacancyRepo,
-			vacancyUse,
-			logger,
-			schedulerPerPage,
-			schedulerTotalPages,
-			schedulerRefreshRate,
-		)
-		vacancyScheduler.Start()*/
-	//--parsing
-
-	server := httpDelivery.NewServer(cfg, dbConn, logger)
+	lockDB, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatalf("Lock database initialization failed", "error", err)
+	}
+	lockDB.SetConnMaxLifetime(dbConnMaxLifetime)
+	lockDB.SetMaxIdleConns(lockDBMaxIdleConns)
+	defer func() {
+		if err := lockDB.Close(); err != nil {
+			logger.Errorf("Error closing lock database connection", "error", err)
+		}
+	}()
+
+	fileStorage, err := storage.New(cfg.StorageDriver, cfg.StorageDir, storage.S3Config{
+		Endpoint:  cfg.S3Endpoint,
+		Region:    cfg.S3Region,
+		Bucket:    cfg.S3Bucket,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+		PathStyle: cfg.S3PathStyle,
+	})
+	if err != nil {
+		logger.Fatalf("File storage initialization failed", "error", err)
+	}
+
+	mailer, err := mail.New(cfg.MailDriver, mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	}, logger)
+	if err != nil {
+		logger.Fatalf("Mail sender initialization failed", "error", err)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+
+	server := httpDelivery.NewServer(jobsCtx, &jobs, cfg, dbConn, lockDB, fileStorage, mailer, logger)
 	go startHTTPServer(server, cfg.ServerAddress, logger)
 
-	gracefulShutdown(server, logger)
+	gracefulShutdown(server, stopJobs, &jobs, logger)
 }
 
-func initializeDB(databaseURL string, logger *util.Logger) (*sql.DB, error) {
+func initializeDB(databaseURL, migrationsDir string, logger *util.Logger) (*sql.DB, error) {
 	dbConn, err := sql.Open("postgres", databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open DB connection: %w", err)
@@ -100,11 +121,15 @@ func initializeDB(databaseURL string, logger *util.Logger) (*sql.DB, error) {
 	logger.Info("Successfully connected to the database")
 
 	//--Выполнение миграций
-	if err := db.Migrate(dbConn, migrationPath); err != nil {
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer migrateCancel()
+
+	applied, err := db.NewMigrator(dbConn, migrationsDir, logger).Up(migrateCtx)
+	if err != nil {
 		dbConn.Close()
 		return nil, fmt.Errorf("database migration failed: %w", err)
 	}
-	logger.Info("Database migration completed successfully")
+	logger.Infof("Database migration completed successfully, %d new migration(s) applied", applied)
 
 	return dbConn, nil
 }
@@ -116,13 +141,15 @@ func startHTTPServer(server *http.Server, address string, logger *util.Logger) {
 	}
 }
 
-func gracefulShutdown(server *http.Server, logger *util.Logger) {
+func gracefulShutdown(server *http.Server, stopJobs context.CancelFunc, jobs *sync.WaitGroup, logger *util.Logger) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	sig := <-quit
 
 	logger.Infof("Shutdown signal received", "signal", sig.String())
 
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
@@ -131,4 +158,16 @@ func gracefulShutdown(server *http.Server, logger *util.Logger) {
 	} else {
 		logger.Info("HTTP server shut down gracefully")
 	}
+
+	done := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		logger.Info("Background jobs stopped")
+	case <-ctx.Done():
+		logger.Warn("Timed out waiting for background jobs to stop")
+	}
 }
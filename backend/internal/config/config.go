@@ -3,14 +3,77 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
-	DatabaseURL   string
-	ServerAddress string
-	LogLevel      string
-	JWTSecret     string
-	MLServiceURL  string
+	DatabaseURL     string
+	ServerAddress   string
+	LogLevel        string
+	JWTSecret       string
+	MLServiceURL    string
+	MigrationsDir   string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	StorageDriver  string
+	StorageDir     string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3PathStyle    bool
+	ResumeMaxBytes int64
+
+	SalaryNetRatio    float64
+	CurrencyRatesFile string
+
+	VacancyTTL               time.Duration
+	VacancyArchiveAfter      time.Duration
+	VacancyLifecycleInterval time.Duration
+
+	AppURL              string
+	SavedSearchInterval time.Duration
+	MailDriver          string
+	MailFrom            string
+	SMTPHost            string
+	SMTPPort            int
+	SMTPUsername        string
+	SMTPPassword        string
+
+	BookmarkExpiryNotice  time.Duration
+	BookmarkAlertInterval time.Duration
+
+	HHSyncEnabled  bool
+	HHSyncPerPage  int
+	HHSyncInterval time.Duration
+	// HHSearchProfilesFile, when set, is imported into hh_search_profiles at
+	// startup; profiles are matched by name.
+	HHSearchProfilesFile string
+
+	HHBaseURL string
+	// HHUserAgent is required by HH, e.g. "CareerTrack/1.0 (admin@example.com)".
+	HHUserAgent     string
+	HHTimeout       time.Duration
+	HHRateLimit     float64
+	HHMaxRetries    int
+	HHDetailWorkers int
+	// VacancyFeedsFile, when set, lists JSON vacancy feeds to import besides HH.
+	VacancyFeedsFile string
+	// SyncStaleAfter is how long an imported vacancy may go unseen by the
+	// sync before its source is asked whether it still exists; zero disables
+	// re-checks.
+	SyncStaleAfter   time.Duration
+	SyncRecheckBatch int
+	// CompanyClaimTokenTTL is how long the token mailed to confirm a company
+	// claim stays valid.
+	CompanyClaimTokenTTL time.Duration
+	// BootstrapAdminEmail and BootstrapAdminPassword, when both set, create
+	// the first SUPER_ADMIN at startup if there is none yet.
+	BootstrapAdminEmail    string
+	BootstrapAdminPassword string
 }
 
 func LoadConfig() *Config {
@@ -22,13 +85,109 @@ func LoadConfig() *Config {
 	logLevel := getEnv("LOG_LEVEL", "INFO")
 	jwtSecret := getEnv("JWT_SECRET", "")
 	mlServiceURL := getEnv("ML_SERVICE_URL", "")
+	migrationsDir := getEnv("MIGRATIONS_DIR", "./migrations")
+	accessTokenTTL := getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL := getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	storageDriver := getEnv("STORAGE_DRIVER", "local")
+	storageDir := getEnv("STORAGE_DIR", "./uploads")
+	s3Endpoint := getEnv("S3_ENDPOINT", "")
+	s3Region := getEnv("S3_REGION", "us-east-1")
+	s3Bucket := getEnv("S3_BUCKET", "")
+	s3AccessKey := getEnv("S3_ACCESS_KEY", "")
+	s3SecretKey := getEnv("S3_SECRET_KEY", "")
+	s3PathStyle := getBoolEnv("S3_PATH_STYLE", true)
+	resumeMaxBytes := getInt64Env("RESUME_MAX_BYTES", 5<<20)
+	salaryNetRatio := getFloatEnv("SALARY_NET_RATIO", 0.81)
+	currencyRatesFile := getEnv("CURRENCY_RATES_FILE", "")
+	vacancyTTL := getDurationEnv("VACANCY_TTL", 30*24*time.Hour)
+	vacancyArchiveAfter := getDurationEnv("VACANCY_ARCHIVE_AFTER", 90*24*time.Hour)
+	vacancyLifecycleInterval := getDurationEnv("VACANCY_LIFECYCLE_INTERVAL", time.Minute)
+	appURL := getEnv("APP_URL", "http://localhost:3000")
+	savedSearchInterval := getDurationEnv("SAVED_SEARCH_INTERVAL", 15*time.Minute)
+	mailDriver := getEnv("MAIL_DRIVER", "log")
+	mailFrom := getEnv("MAIL_FROM", "")
+	smtpHost := getEnv("SMTP_HOST", "")
+	smtpPort := getInt64Env("SMTP_PORT", 587)
+	smtpUsername := getEnv("SMTP_USERNAME", "")
+	smtpPassword := getEnv("SMTP_PASSWORD", "")
+	bookmarkExpiryNotice := getDurationEnv("BOOKMARK_EXPIRY_NOTICE", 72*time.Hour)
+	bookmarkAlertInterval := getDurationEnv("BOOKMARK_ALERT_INTERVAL", 5*time.Minute)
+	hhSyncEnabled := getBoolEnv("HH_SYNC_ENABLED", false)
+	hhSyncPerPage := getInt64Env("HH_SYNC_PER_PAGE", 50)
+	hhSyncInterval := getDurationEnv("HH_SYNC_INTERVAL", time.Hour)
+	hhSearchProfilesFile := getEnv("HH_SEARCH_PROFILES_FILE", "")
+	hhBaseURL := getEnv("HH_BASE_URL", "https://api.hh.ru")
+	hhUserAgent := getEnv("HH_USER_AGENT", "")
+	hhTimeout := getDurationEnv("HH_TIMEOUT", 15*time.Second)
+	hhRateLimit := getFloatEnv("HH_RATE_LIMIT", 5)
+	hhMaxRetries := getInt64Env("HH_MAX_RETRIES", 4)
+	hhDetailWorkers := getInt64Env("HH_DETAIL_WORKERS", 4)
+	vacancyFeedsFile := getEnv("VACANCY_FEEDS_FILE", "")
+	syncStaleAfter := getDurationEnv("SYNC_STALE_AFTER", 24*time.Hour)
+	syncRecheckBatch := getInt64Env("SYNC_RECHECK_BATCH", 200)
+	companyClaimTokenTTL := getDurationEnv("COMPANY_CLAIM_TOKEN_TTL", 48*time.Hour)
+	bootstrapAdminEmail := getEnv("BOOTSTRAP_ADMIN_EMAIL", "")
+	bootstrapAdminPassword := getEnv("BOOTSTRAP_ADMIN_PASSWORD", "")
 
 	return &Config{
-		DatabaseURL:   databaseURL,
-		ServerAddress: serverAddress,
-		LogLevel:      logLevel,
-		JWTSecret:     jwtSecret,
-		MLServiceURL:  mlServiceURL,
+		DatabaseURL:     databaseURL,
+		ServerAddress:   serverAddress,
+		LogLevel:        logLevel,
+		JWTSecret:       jwtSecret,
+		MLServiceURL:    mlServiceURL,
+		MigrationsDir:   migrationsDir,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+		StorageDriver:   storageDriver,
+		StorageDir:      storageDir,
+		S3Endpoint:      s3Endpoint,
+		S3Region:        s3Region,
+		S3Bucket:        s3Bucket,
+		S3AccessKey:     s3AccessKey,
+		S3SecretKey:     s3SecretKey,
+		S3PathStyle:     s3PathStyle,
+		ResumeMaxBytes:  resumeMaxBytes,
+
+		SalaryNetRatio:    salaryNetRatio,
+		CurrencyRatesFile: currencyRatesFile,
+
+		VacancyTTL:               vacancyTTL,
+		VacancyArchiveAfter:      vacancyArchiveAfter,
+		VacancyLifecycleInterval: vacancyLifecycleInterval,
+
+		AppURL:              appURL,
+		SavedSearchInterval: savedSearchInterval,
+		MailDriver:          mailDriver,
+		MailFrom:            mailFrom,
+		SMTPHost:            smtpHost,
+		SMTPPort:            int(smtpPort),
+		SMTPUsername:        smtpUsername,
+		SMTPPassword:        smtpPassword,
+
+		BookmarkExpiryNotice:  bookmarkExpiryNotice,
+		BookmarkAlertInterval: bookmarkAlertInterval,
+
+		HHSyncEnabled:  hhSyncEnabled,
+		HHSyncPerPage:  int(hhSyncPerPage),
+		HHSyncInterval: hhSyncInterval,
+
+		HHSearchProfilesFile: hhSearchProfilesFile,
+
+		HHBaseURL:       hhBaseURL,
+		HHUserAgent:     hhUserAgent,
+		HHTimeout:       hhTimeout,
+		HHRateLimit:     hhRateLimit,
+		HHMaxRetries:    int(hhMaxRetries),
+		HHDetailWorkers: int(hhDetailWorkers),
+
+		VacancyFeedsFile: vacancyFeedsFile,
+		SyncStaleAfter:   syncStaleAfter,
+		SyncRecheckBatch: int(syncRecheckBatch),
+
+		CompanyClaimTokenTTL: companyClaimTokenTTL,
+
+		BootstrapAdminEmail:    bootstrapAdminEmail,
+		BootstrapAdminPassword: bootstrapAdminPassword,
 	}
 }
 
@@ -38,3 +197,55 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration %q for %s, using default %s", value, key, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean %q for %s, using default %t", value, key, defaultValue)
+		return defaultValue
+	}
+	return b
+}
+
+func getInt64Env(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("Invalid integer %q for %s, using default %d", value, key, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid number %q for %s, using default %g", value, key, defaultValue)
+		return defaultValue
+	}
+	return f
+}
@@ -0,0 +1,47 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"os"
+	"strings"
+)
+
+// RateProvider supplies currency rates against KZT. Implementations may read
+// a file, call a bank API, etc.
+type RateProvider interface {
+	Rates(ctx context.Context) ([]model.CurrencyRate, error)
+}
+
+// FileRateProvider reads rates from a JSON file of the form
+//
+//	{"rates": {"USD": 505.3, "EUR": 548.1}}
+//
+// where every value is the price of one unit in KZT.
+type FileRateProvider struct {
+	path string
+}
+
+func NewFileRateProvider(path string) *FileRateProvider {
+	return &FileRateProvider{path: path}
+}
+
+func (p *FileRateProvider) Rates(ctx context.Context) ([]model.CurrencyRate, error) {
+	raw, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+	var doc struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse rates file %s: %w", p.path, err)
+	}
+	rates := make([]model.CurrencyRate, 0, len(doc.Rates))
+	for code, rate := range doc.Rates {
+		rates = append(rates, model.CurrencyRate{Code: strings.ToUpper(code), RateToKZT: rate})
+	}
+	return rates, nil
+}
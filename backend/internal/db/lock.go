@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+)
+
+// Lock key spaces keep the advisory locks of different kinds apart.
+const (
+	syncLockSpace = "vacancy-sync:"
+	jobLockSpace  = "job:"
+)
+
+// SyncLockID is the pg_advisory_lock key held while a replica imports
+// vacancies from source.
+func SyncLockID(source string) int64 {
+	return lockID(syncLockSpace + source)
+}
+
+// JobLockID is the pg_advisory_lock key held while a replica runs the
+// periodic job called name.
+func JobLockID(name string) int64 {
+	return lockID(jobLockSpace + name)
+}
+
+func lockID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+// TryAdvisoryLock takes the session-level advisory lock key on a dedicated
+// connection without waiting. ok is false when another session holds it.
+// release unlocks and returns the connection; it must be called when ok.
+// The connection stays pinned until then, so dbConn should be a pool of its
+// own rather than the one the locked work queries.
+func TryAdvisoryLock(ctx context.Context, dbConn *sql.DB, key int64) (release func() error, ok bool, err error) {
+	conn, err := dbConn.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("try advisory lock %d: %w", key, err)
+	}
+	if !ok {
+		return nil, false, conn.Close()
+	}
+	release = func() error {
+		defer conn.Close()
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			return fmt.Errorf("release advisory lock %d: %w", key, err)
+		}
+		return nil
+	}
+	return release, true, nil
+}
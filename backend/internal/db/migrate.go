@@ -1,32 +1,345 @@
 package db
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"strings"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chotamkz/career-track-backend/internal/util"
 )
 
-func Migrate(db *sql.DB, migrationFile string) error {
-	content, err := ioutil.ReadFile(migrationFile)
+// migrationLockID is the pg_advisory_lock key shared by every replica, so only
+// one process applies or rolls back migrations at a time.
+const migrationLockID int64 = 7305118802213342001
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrNoMigrationsApplied = errors.New("no migrations have been applied")
+	ErrChecksumMismatch    = errors.New("applied migration checksum mismatch")
+)
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+}
+
+type appliedMigration struct {
+	version   int
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db     *sql.DB
+	dir    string
+	logger *util.Logger
+}
+
+func NewMigrator(db *sql.DB, dir string, logger *util.Logger) *Migrator {
+	return &Migrator{db: db, dir: dir, logger: logger}
+}
+
+// LoadMigrations reads NNNN_name.up.sql / NNNN_name.down.sql pairs from dir,
+// sorted by version. Every version must have an up file; down is optional
+// but required to roll the version back.
+func LoadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %v", err)
+		return nil, fmt.Errorf("read migrations dir: %w", err)
 	}
 
-	queries := strings.Split(string(content), ";")
-	for _, query := range queries {
-		trimmedQuery := strings.TrimSpace(query)
-		if trimmedQuery == "" {
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if m == nil {
 			continue
 		}
-		_, err := db.Exec(trimmedQuery)
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", e.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %04d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(content)
+			sum := sha256.Sum256(content)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in version order and returns how many
+// were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	migrations, err := LoadMigrations(m.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedMigrations(ctx, conn)
 		if err != nil {
-			log.Printf("failed executing query:\n%s\nerror: %v\n", trimmedQuery, err)
 			return err
 		}
+		if err := verifyChecksums(migrations, applied); err != nil {
+			return err
+		}
+		for _, mig := range migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.applyUp(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down rolls back the last `steps` applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps < 1 {
+		steps = 1
+	}
+	migrations, err := LoadMigrations(m.dir)
+	if err != nil {
+		return 0, err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
 	}
 
-	log.Println("Database migration completed successfully.")
+	count := 0
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return ErrNoMigrationsApplied
+		}
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, v := range versions {
+			if count == steps {
+				break
+			}
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("applied migration %04d not found in %s", v, m.dir)
+			}
+			if err := m.applyDown(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	if _, err := m.Down(ctx, 1); err != nil {
+		return err
+	}
+	_, err := m.Up(ctx)
+	return err
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(m.dir)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		st := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = a.appliedAt
+			st.Modified = a.checksum != mig.Checksum
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.logger.Errorf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	const q = `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INT PRIMARY KEY,
+            name TEXT NOT NULL,
+            checksum TEXT NOT NULL,
+            applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        )
+    `
+	if _, err := conn.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("appliedMigrations query: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("appliedMigrations scan: %w", err)
+		}
+		applied[a.version] = a
+	}
+	return applied, rows.Err()
+}
+
+func verifyChecksums(migrations []Migration, applied map[int]appliedMigration) error {
+	for _, mig := range migrations {
+		a, ok := applied[mig.Version]
+		if ok && a.checksum != mig.Checksum {
+			return fmt.Errorf("%w: %04d_%s was changed after it was applied", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applyUp(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+		return fmt.Errorf("apply %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`,
+		mig.Version, mig.Name, mig.Checksum,
+	); err != nil {
+		return fmt.Errorf("record %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	m.logger.Infof("Applied migration %04d_%s", mig.Version, mig.Name)
+	return nil
+}
+
+func (m *Migrator) applyDown(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	if mig.Down == "" {
+		return fmt.Errorf("migration %04d_%s has no down file", mig.Version, mig.Name)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+		return fmt.Errorf("roll back %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+		return fmt.Errorf("unrecord %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	m.logger.Infof("Rolled back migration %04d_%s", mig.Version, mig.Name)
 	return nil
 }
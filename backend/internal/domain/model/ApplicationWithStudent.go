@@ -7,4 +7,6 @@ type ApplicationWithStudent struct {
 	City         string      `json:"city"`
 	Phone        string      `json:"phone"`
 	StudentEmail string      `json:"email"`
+	ResumeURL    string      `json:"resumeUrl,omitempty"`
+	ResumeName   string      `json:"resumeName,omitempty"`
 }
@@ -11,4 +11,6 @@ type ApplicationForStudent struct {
 	SubmittedDate time.Time         `json:"submittedDate"`
 	Status        ApplicationStatus `json:"status"`
 	UpdatedDate   time.Time         `json:"updatedDate"`
+	// VacancyChangedAt is set when the offer changed after the student applied.
+	VacancyChangedAt *time.Time `json:"vacancyChangedAt,omitempty"`
 }
@@ -0,0 +1,16 @@
+package model
+
+type VacancyApplicationCount struct {
+	VacancyID    uint   `json:"vacancyId"`
+	VacancyTitle string `json:"vacancyTitle"`
+	CompanyName  string `json:"companyName"`
+	Count        int    `json:"count"`
+}
+
+type ApplicationStats struct {
+	Total        int                       `json:"total"`
+	ByStatus     map[ApplicationStatus]int `json:"byStatus"`
+	LastWeek     int                       `json:"lastWeek"`
+	LastMonth    int                       `json:"lastMonth"`
+	TopVacancies []VacancyApplicationCount `json:"topVacancies"`
+}
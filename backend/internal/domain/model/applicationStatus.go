@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// applicationTransitions lists, for every status, the statuses an employer
+// may move an application to next. ACCEPTED, REJECTED and WITHDRAWN are final.
+// WITHDRAWN is only reachable by the student, see CanWithdraw.
+var applicationTransitions = map[ApplicationStatus][]ApplicationStatus{
+	StatusApplied:            {StatusCVScreening, StatusInterviewScheduled, StatusRejected},
+	StatusCVScreening:        {StatusInterviewScheduled, StatusRejected},
+	StatusInterviewScheduled: {StatusInterviewCompleted, StatusRejected},
+	StatusInterviewCompleted: {StatusInterviewScheduled, StatusOfferExtended, StatusRejected},
+	StatusOfferExtended:      {StatusAccepted, StatusRejected},
+	StatusAccepted:           {},
+	StatusRejected:           {},
+	StatusWithdrawn:          {},
+}
+
+func (s ApplicationStatus) Valid() bool {
+	_, ok := applicationTransitions[s]
+	return ok
+}
+
+// NextStatuses returns the statuses reachable from s in one step.
+func (s ApplicationStatus) NextStatuses() []ApplicationStatus {
+	return applicationTransitions[s]
+}
+
+func (s ApplicationStatus) CanTransitionTo(next ApplicationStatus) bool {
+	for _, allowed := range applicationTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further status changes are possible.
+func (s ApplicationStatus) IsFinal() bool {
+	return len(applicationTransitions[s]) == 0
+}
+
+// CanWithdraw reports whether the student may still retract an application
+// in this status.
+func (s ApplicationStatus) CanWithdraw() bool {
+	return s.Valid() && !s.IsFinal()
+}
+
+// ApplicationCounts splits a vacancy's applications into those still in
+// play and those the students withdrew.
+type ApplicationCounts struct {
+	Active    int `json:"active"`
+	Withdrawn int `json:"withdrawn"`
+}
+
+// ApplicationStatusChange is one entry of an application's timeline. The
+// first entry of every application has no FromStatus.
+type ApplicationStatusChange struct {
+	ID            uint               `json:"id" db:"id"`
+	ApplicationID uint               `json:"applicationId" db:"application_id"`
+	FromStatus    *ApplicationStatus `json:"fromStatus" db:"from_status"`
+	ToStatus      ApplicationStatus  `json:"toStatus" db:"to_status"`
+	ChangedBy     *uint              `json:"changedBy,omitempty" db:"changed_by"`
+	ChangedByType UserType           `json:"changedByType,omitempty" db:"changed_by_type"`
+	Note          string             `json:"note,omitempty" db:"note"`
+	ChangedAt     time.Time          `json:"changedAt" db:"changed_at"`
+}
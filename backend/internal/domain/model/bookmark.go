@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Bookmarks is what a student has shortlisted, most recent first.
+type Bookmarks struct {
+	Vacancies  []Vacancy   `json:"vacancies"`
+	Hackathons []Hackathon `json:"hackathons"`
+}
+
+// VacancyBookmarkAlert is a bookmark whose vacancy is about to expire or has
+// been closed and whose owner has not been told yet.
+type VacancyBookmarkAlert struct {
+	StudentID  uint
+	VacancyID  uint
+	Title      string
+	Status     VacancyStatus
+	ExpiresAt  *time.Time
+	PostedDate time.Time
+}
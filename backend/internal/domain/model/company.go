@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Company is the public identity vacancies are posted under. Imported
+// companies carry their source and the source's id for them; a registered
+// employer's own company has neither. OwnerID is the employer account that
+// manages the company, nil for imported companies nobody has taken over.
+// Website is the company's own site, empty until its source reports one.
+type Company struct {
+	ID          uint      `json:"id" db:"id"`
+	Source      string    `json:"source,omitempty" db:"source"`
+	ExternalID  string    `json:"externalId,omitempty" db:"external_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Website     string    `json:"website,omitempty" db:"website"`
+	OwnerID     *uint     `json:"ownerId,omitempty" db:"owner_id"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+type CompanyClaimStatus string
+
+const (
+	// CompanyClaimPending claims wait for the employer to confirm the token
+	// mailed to the company address.
+	CompanyClaimPending CompanyClaimStatus = "PENDING"
+	// CompanyClaimVerified claims wait for an admin's review.
+	CompanyClaimVerified CompanyClaimStatus = "VERIFIED"
+	CompanyClaimApproved CompanyClaimStatus = "APPROVED"
+	CompanyClaimDenied   CompanyClaimStatus = "DENIED"
+)
+
+func (s CompanyClaimStatus) Valid() bool {
+	switch s {
+	case CompanyClaimPending, CompanyClaimVerified, CompanyClaimApproved, CompanyClaimDenied:
+		return true
+	}
+	return false
+}
+
+// CompanyClaim is an employer's request to take over an imported company.
+// Email is the company address the verification token was sent to.
+// DomainMatch reports whether Email is at the domain of CompanyWebsite; it
+// is false when the company's website is unknown.
+type CompanyClaim struct {
+	ID             uint               `json:"id" db:"id"`
+	CompanyID      uint               `json:"companyId" db:"company_id"`
+	CompanyName    string             `json:"companyName" db:"-"`
+	CompanyWebsite string             `json:"companyWebsite,omitempty" db:"-"`
+	DomainMatch    bool               `json:"domainMatch" db:"-"`
+	EmployerID     uint               `json:"employerId" db:"employer_id"`
+	Email          string             `json:"email" db:"email"`
+	Status         CompanyClaimStatus `json:"status" db:"status"`
+	TokenHash      string             `json:"-" db:"token_hash"`
+	TokenExpiresAt *time.Time         `json:"-" db:"token_expires_at"`
+	VerifiedAt     *time.Time         `json:"verifiedAt,omitempty" db:"verified_at"`
+	ReviewedBy     *uint              `json:"reviewedBy,omitempty" db:"reviewed_by"`
+	ReviewNote     string             `json:"reviewNote,omitempty" db:"review_note"`
+	ReviewedAt     *time.Time         `json:"reviewedAt,omitempty" db:"reviewed_at"`
+	CreatedAt      time.Time          `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time          `json:"updatedAt" db:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const BaseCurrency = "KZT"
+
+// CurrencyRate is the price of one unit of Code in KZT.
+type CurrencyRate struct {
+	Code      string    `json:"code" db:"code"`
+	RateToKZT float64   `json:"rateToKzt" db:"rate_to_kzt"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SalaryDisplay is a vacancy salary converted to the currency the client
+// asked for. Converted amounts are always net.
+type SalaryDisplay struct {
+	From     *float64 `json:"from,omitempty"`
+	To       *float64 `json:"to,omitempty"`
+	Currency string   `json:"currency"`
+}
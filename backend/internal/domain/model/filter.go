@@ -1,10 +1,48 @@
 package model
 
+import "strings"
+
+type VacancySort string
+
+const (
+	SortRelevance VacancySort = "relevance"
+	SortDate      VacancySort = "date"
+	SortSalary    VacancySort = "salary"
+)
+
+func (s VacancySort) Valid() bool {
+	switch s {
+	case "", SortRelevance, SortDate, SortSalary:
+		return true
+	}
+	return false
+}
+
 type VacancyFilter struct {
-	Keywords    string  `json:"keywords"`
-	Region      string  `json:"region"`
-	Experience  string  `json:"experience"`
-	SalaryFrom  float64 `json:"salary_from"`
-	Schedule    string  `json:"schedule"`
-	CompanyName string  `json:"company_name"`
+	Keywords    string      `json:"keywords"`
+	Region      string      `json:"region"`
+	Experience  string      `json:"experience"`
+	SalaryFrom  float64     `json:"salary_from"`
+	Schedule    string      `json:"schedule"`
+	CompanyName string      `json:"company_name"`
+	Sort        VacancySort `json:"sort"`
+	// SalaryMin and SalaryMax bound the salary fork, in net KZT once they
+	// reach the repository; Currency is the one they were given in and the
+	// one results are displayed in.
+	SalaryMin float64 `json:"salary_min"`
+	SalaryMax float64 `json:"salary_max"`
+	Currency  string  `json:"currency"`
+}
+
+// KeysetOrdered reports whether results come in (posted_date, id) order, the
+// only order cursor pagination supports. Relevance without keywords falls
+// back to date.
+func (f VacancyFilter) KeysetOrdered() bool {
+	return f.Sort == SortDate || (f.Sort != SortSalary && strings.TrimSpace(f.Keywords) == "")
+}
+
+type UserFilter struct {
+	Query    string   `json:"query"`
+	UserType UserType `json:"user_type"`
+	Banned   *bool    `json:"banned"`
 }
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// MaxHHSearchPeriodDays is the longest publication window HH.ru accepts.
+const MaxHHSearchPeriodDays = 30
+
+// HHSearchProfile is one HH.ru vacancy search run by the importer. Empty
+// Areas or ProfessionalRoles leave that parameter out of the query.
+// PeriodDays is how far back a first or full sync looks, MaxHHSearchPeriodDays
+// when zero; later syncs only fetch vacancies published since SyncedUntil.
+type HHSearchProfile struct {
+	ID                uint       `json:"id" db:"id"`
+	Name              string     `json:"name" db:"name"`
+	Areas             []string   `json:"areas" db:"areas"`
+	ProfessionalRoles []string   `json:"professionalRoles" db:"professional_roles"`
+	Text              string     `json:"text" db:"text"`
+	OnlyWithSalary    bool       `json:"onlyWithSalary" db:"only_with_salary"`
+	PeriodDays        int        `json:"periodDays" db:"period_days"`
+	Enabled           bool       `json:"enabled" db:"enabled"`
+	SyncedUntil       *time.Time `json:"syncedUntil,omitempty" db:"synced_until"`
+	CreatedAt         time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updatedAt" db:"updated_at"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -20,6 +21,23 @@ const (
 	AccessLevelContentModerator AdminAccessLevel = "CONTENT_MODERATOR"
 )
 
+type ProficiencyLevel string
+
+const (
+	ProficiencyBeginner     ProficiencyLevel = "BEGINNER"
+	ProficiencyIntermediate ProficiencyLevel = "INTERMEDIATE"
+	ProficiencyAdvanced     ProficiencyLevel = "ADVANCED"
+	ProficiencyExpert       ProficiencyLevel = "EXPERT"
+)
+
+func (l ProficiencyLevel) Valid() bool {
+	switch l {
+	case ProficiencyBeginner, ProficiencyIntermediate, ProficiencyAdvanced, ProficiencyExpert:
+		return true
+	}
+	return false
+}
+
 type ApplicationStatus string
 
 const (
@@ -30,15 +48,31 @@ const (
 	StatusOfferExtended      ApplicationStatus = "OFFER_EXTENDED"
 	StatusAccepted           ApplicationStatus = "ACCEPTED"
 	StatusRejected           ApplicationStatus = "REJECTED"
+	StatusWithdrawn          ApplicationStatus = "WITHDRAWN"
 )
 
 type User struct {
-	ID        uint      `json:"id" db:"id"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"password" db:"password"`
-	UserType  UserType  `json:"userType" db:"user_type"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+	ID        uint       `json:"id" db:"id"`
+	Email     string     `json:"email" db:"email"`
+	Password  string     `json:"password" db:"password"`
+	UserType  UserType   `json:"userType" db:"user_type"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time  `json:"updatedAt" db:"updated_at"`
+	BannedAt  *time.Time `json:"bannedAt,omitempty" db:"banned_at"`
+	BanReason string     `json:"banReason,omitempty" db:"ban_reason"`
+}
+
+type RefreshToken struct {
+	ID         uint       `json:"id" db:"id"`
+	UserID     uint       `json:"userId" db:"user_id"`
+	FamilyID   string     `json:"familyId" db:"family_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	ExpiresAt  time.Time  `json:"expiresAt" db:"expires_at"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	ReplacedBy *uint      `json:"replacedBy,omitempty" db:"replaced_by"`
+	UserAgent  string     `json:"userAgent" db:"user_agent"`
+	IPAddress  string     `json:"ipAddress" db:"ip_address"`
 }
 
 type StudentProfile struct {
@@ -59,29 +93,45 @@ type EmployerProfile struct {
 
 type AdminProfile struct {
 	UserID      uint             `json:"userId" db:"user_id"`
-	Permissions string           `json:"permissions" db:"permissions"`
+	Permissions json.RawMessage  `json:"permissions" db:"permissions"`
 	AccessLevel AdminAccessLevel `json:"accessLevel" db:"access_level"`
-	LastAccess  time.Time        `json:"lastAccess" db:"last_access"`
+	LastAccess  *time.Time       `json:"lastAccess,omitempty" db:"last_access"`
 }
 
 type Vacancy struct {
-	ID             uint           `json:"id" db:"id"`
-	Title          string         `json:"title" db:"title"`
-	Description    string         `json:"description" db:"description"`
-	Requirements   string         `json:"requirements" db:"requirements"`
-	Location       string         `json:"location" db:"location"`
-	PostedDate     time.Time      `json:"postedDate" db:"posted_date"`
-	EmployerID     uint           `json:"employerId" db:"employer_id"`
-	CreatedAt      time.Time      `json:"createdAt" db:"created_at"`
-	SalaryFrom     float64        `json:"salary_from" db:"salary_from"`
-	SalaryTo       float64        `json:"salary_to" db:"salary_to"`
-	SalaryCurrency string         `json:"salary_currency" db:"salary_currency"`
-	SalaryGross    bool           `json:"salary_gross" db:"salary_gross"`
-	VacancyURL     sql.NullString `json:"vacancy_url" db:"vacancy_url"`
-	WorkSchedule   string         `json:"work_schedule" db:"work_schedule"`
-	Experience     string         `json:"experience" db:"experience"`
-	Skills         []string       `json:"skills,omitempty" db:"-"`
-	Applied        bool           `json:"applied,omitempty" db:"-"`
+	ID                  uint               `json:"id" db:"id"`
+	Title               string             `json:"title" db:"title"`
+	Description         string             `json:"description" db:"description"`
+	DescriptionText     string             `json:"descriptionText,omitempty" db:"description_text"`
+	DescriptionMarkdown string             `json:"descriptionMarkdown,omitempty" db:"description_markdown"`
+	Requirements        string             `json:"requirements" db:"requirements"`
+	Location            string             `json:"location" db:"location"`
+	PostedDate          time.Time          `json:"postedDate" db:"posted_date"`
+	EmployerID          uint               `json:"employerId" db:"employer_id"`
+	CompanyID           uint               `json:"companyId,omitempty" db:"company_id"`
+	CreatedAt           time.Time          `json:"createdAt" db:"created_at"`
+	SalaryFrom          float64            `json:"salary_from" db:"salary_from"`
+	SalaryTo            float64            `json:"salary_to" db:"salary_to"`
+	SalaryCurrency      string             `json:"salary_currency" db:"salary_currency"`
+	SalaryGross         bool               `json:"salary_gross" db:"salary_gross"`
+	VacancyURL          sql.NullString     `json:"vacancy_url" db:"vacancy_url"`
+	WorkSchedule        string             `json:"work_schedule" db:"work_schedule"`
+	Experience          string             `json:"experience" db:"experience"`
+	Skills              []string           `json:"skills,omitempty" db:"-"`
+	Applied             bool               `json:"applied,omitempty" db:"-"`
+	Bookmarked          bool               `json:"bookmarked,omitempty" db:"-"`
+	Applications        *ApplicationCounts `json:"applications,omitempty" db:"-"`
+	Snippet             string             `json:"snippet,omitempty" db:"-"`
+	SalaryFromKZT       *float64           `json:"salary_from_kzt,omitempty" db:"salary_from_kzt"`
+	SalaryToKZT         *float64           `json:"salary_to_kzt,omitempty" db:"salary_to_kzt"`
+	SalaryDisplay       *SalaryDisplay     `json:"salary_display,omitempty" db:"-"`
+	Status              VacancyStatus      `json:"status,omitempty" db:"status"`
+	PublishAt           *time.Time         `json:"publishAt,omitempty" db:"publish_at"`
+	ExpiresAt           *time.Time         `json:"expiresAt,omitempty" db:"expires_at"`
+	DeletedAt           *time.Time         `json:"deletedAt,omitempty" db:"deleted_at"`
+	UpdatedAt           *time.Time         `json:"updatedAt,omitempty" db:"updated_at"`
+	Source              string             `json:"source,omitempty" db:"source"`
+	ExternalID          string             `json:"externalId,omitempty" db:"external_id"`
 }
 
 type Application struct {
@@ -92,6 +142,11 @@ type Application struct {
 	SubmittedDate time.Time         `json:"submittedDate" db:"submitted_date"`
 	Status        ApplicationStatus `json:"status" db:"status"`
 	UpdatedDate   time.Time         `json:"updatedDate" db:"updated_date"`
+	ResumeID      *uint             `json:"resumeId,omitempty" db:"resume_id"`
+	// VacancyVersion is the vacancy version applied to; VacancyChangedAt is
+	// set when salary or requirements change after that.
+	VacancyVersion   *int       `json:"vacancyVersion,omitempty" db:"vacancy_version"`
+	VacancyChangedAt *time.Time `json:"vacancyChangedAt,omitempty" db:"vacancy_changed_at"`
 }
 
 type Skill struct {
@@ -109,18 +164,26 @@ type VacancySkill struct {
 }
 
 type StudentSkill struct {
-	StudentID uint      `json:"studentId" db:"student_id"`
-	SkillID   uint      `json:"skillId" db:"skill_id"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+	StudentID        uint             `json:"studentId" db:"student_id"`
+	SkillID          uint             `json:"skillId" db:"skill_id"`
+	SkillName        string           `json:"skillName" db:"name"`
+	ProficiencyLevel ProficiencyLevel `json:"proficiencyLevel" db:"proficiency_level"`
+	YearsExperience  float64          `json:"yearsExperience" db:"years_experience"`
+	CreatedAt        time.Time        `json:"createdAt" db:"created_at"`
+	UpdatedAt        time.Time        `json:"updatedAt" db:"updated_at"`
 }
 
 type Resume struct {
-	ID         uint      `json:"id" db:"id"`
-	StudentID  uint      `json:"studentId" db:"student_id"`
-	FileURL    string    `json:"fileURL" db:"file_url"`
-	FileName   string    `json:"fileName" db:"file_name"`
-	UploadedAt time.Time `json:"uploadedAt" db:"uploaded_at"`
+	ID          uint      `json:"id" db:"id"`
+	StudentID   uint      `json:"studentId" db:"student_id"`
+	FileURL     string    `json:"fileURL" db:"file_url"`
+	FileName    string    `json:"fileName" db:"file_name"`
+	UploadedAt  time.Time `json:"uploadedAt" db:"uploaded_at"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	SizeBytes   int64     `json:"sizeBytes" db:"size_bytes"`
+	Version     int       `json:"version" db:"version"`
+	IsPrimary   bool      `json:"isPrimary" db:"is_primary"`
 }
 
 type Hackathon struct {
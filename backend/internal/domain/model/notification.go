@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationSavedSearchMatch NotificationType = "SAVED_SEARCH_MATCH"
+	NotificationBookmarkExpiring NotificationType = "BOOKMARK_EXPIRING"
+	NotificationBookmarkClosed   NotificationType = "BOOKMARK_CLOSED"
+	NotificationVacancyRemoved   NotificationType = "VACANCY_REMOVED"
+
+	NotificationCompanyClaimApproved NotificationType = "COMPANY_CLAIM_APPROVED"
+	NotificationCompanyClaimDenied   NotificationType = "COMPANY_CLAIM_DENIED"
+)
+
+// Notification is an in-app message. DedupKey, when set, makes creating the
+// same notification for a user twice a no-op.
+type Notification struct {
+	ID            uint             `json:"id" db:"id"`
+	UserID        uint             `json:"userId" db:"user_id"`
+	Type          NotificationType `json:"type" db:"type"`
+	Title         string           `json:"title" db:"title"`
+	Body          string           `json:"body" db:"body"`
+	VacancyID     *uint            `json:"vacancyId,omitempty" db:"vacancy_id"`
+	SavedSearchID *uint            `json:"savedSearchId,omitempty" db:"saved_search_id"`
+	DedupKey      string           `json:"-" db:"dedup_key"`
+	ReadAt        *time.Time       `json:"readAt,omitempty" db:"read_at"`
+	EmailedAt     *time.Time       `json:"-" db:"emailed_at"`
+	CreatedAt     time.Time        `json:"createdAt" db:"created_at"`
+}
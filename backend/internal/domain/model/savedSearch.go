@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// DigestFrequency is how often a saved search's new matches are emailed.
+type DigestFrequency string
+
+const (
+	DigestNone   DigestFrequency = "NONE"
+	DigestDaily  DigestFrequency = "DAILY"
+	DigestWeekly DigestFrequency = "WEEKLY"
+)
+
+var digestIntervals = map[DigestFrequency]time.Duration{
+	DigestNone:   0,
+	DigestDaily:  24 * time.Hour,
+	DigestWeekly: 7 * 24 * time.Hour,
+}
+
+func (f DigestFrequency) Valid() bool {
+	_, ok := digestIntervals[f]
+	return ok
+}
+
+// Interval is the time between two digests, zero when none are sent.
+func (f DigestFrequency) Interval() time.Duration {
+	return digestIntervals[f]
+}
+
+// SavedSearch is a vacancy search a student wants to be told about. Filter
+// keeps the salary bounds in the currency they were entered in.
+type SavedSearch struct {
+	ID            uint            `json:"id" db:"id"`
+	StudentID     uint            `json:"studentId" db:"student_id"`
+	Name          string          `json:"name" db:"name"`
+	Filter        VacancyFilter   `json:"filter" db:"filter"`
+	MLSkills      string          `json:"mlSkills,omitempty" db:"ml_skills"`
+	Digest        DigestFrequency `json:"digest" db:"digest"`
+	LastCheckedAt time.Time       `json:"lastCheckedAt" db:"last_checked_at"`
+	LastDigestAt  time.Time       `json:"lastDigestAt" db:"last_digest_at"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updatedAt" db:"updated_at"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+type SyncRunStatus string
+
+const (
+	SyncRunning   SyncRunStatus = "RUNNING"
+	SyncSucceeded SyncRunStatus = "SUCCEEDED"
+	SyncFailed    SyncRunStatus = "FAILED"
+	SyncCancelled SyncRunStatus = "CANCELLED"
+)
+
+// UpsertResult tells what an import did to the stored vacancy.
+type UpsertResult int
+
+const (
+	UpsertUnchanged UpsertResult = iota
+	UpsertCreated
+	UpsertUpdated
+	// UpsertOwned means the vacancy's company was claimed; the employer
+	// manages it now and the import only marked it seen.
+	UpsertOwned
+)
+
+// SyncCounts tallies the vacancies an import saw.
+type SyncCounts struct {
+	Created   int `json:"created" db:"created"`
+	Updated   int `json:"updated" db:"updated"`
+	Unchanged int `json:"unchanged" db:"unchanged"`
+	Failed    int `json:"failed" db:"failed"`
+	// Closed counts vacancies closed because the source removed them.
+	Closed int `json:"closed" db:"closed"`
+}
+
+func (c *SyncCounts) Add(r UpsertResult) {
+	switch r {
+	case UpsertCreated:
+		c.Created++
+	case UpsertUpdated:
+		c.Updated++
+	default:
+		c.Unchanged++
+	}
+}
+
+// SyncRun is one import cycle from an external vacancy source. A full resync
+// ignores the watermarks of earlier runs. Errors holds one line per failed
+// page or vacancy.
+type SyncRun struct {
+	ID         uint          `json:"id" db:"id"`
+	Source     string        `json:"source" db:"source"`
+	Status     SyncRunStatus `json:"status" db:"status"`
+	FullResync bool          `json:"fullResync" db:"full_resync"`
+	StartedAt  time.Time     `json:"startedAt" db:"started_at"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty" db:"finished_at"`
+	Pages      int           `json:"pages" db:"pages"`
+	SyncCounts
+	Errors string `json:"errors,omitempty" db:"errors"`
+}
+
+// ImportedVacancy is an open imported vacancy due for a liveness check.
+type ImportedVacancy struct {
+	ID         uint          `json:"id" db:"id"`
+	Title      string        `json:"title" db:"title"`
+	ExternalID string        `json:"externalId" db:"external_id"`
+	Status     VacancyStatus `json:"status" db:"status"`
+	LastSeenAt time.Time     `json:"lastSeenAt" db:"last_seen_at"`
+}
@@ -0,0 +1,24 @@
+package model
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SalaryBucket counts vacancies with salary_from of at least From, matching
+// the salary_from filter semantics.
+type SalaryBucket struct {
+	From  float64 `json:"from"`
+	Count int     `json:"count"`
+}
+
+// VacancyFacets tells the filter UI how many vacancies each option would
+// return. Every facet ignores its own filter so that siblings stay visible.
+type VacancyFacets struct {
+	Total      int            `json:"total"`
+	Regions    []FacetCount   `json:"regions"`
+	Experience []FacetCount   `json:"experience"`
+	Schedules  []FacetCount   `json:"schedules"`
+	Companies  []FacetCount   `json:"companies"`
+	SalaryFrom []SalaryBucket `json:"salaryFrom"`
+}
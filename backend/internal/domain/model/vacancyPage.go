@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// VacancyChangeCursor is a keyset position in the (updated_at, id) ordering
+// used to walk vacancies changed since a point in time. A zero ID stands for
+// the start of UpdatedAt itself, before any vacancy updated at that instant.
+type VacancyChangeCursor struct {
+	UpdatedAt time.Time
+	ID        uint
+}
+
+// VacancyCursor is a keyset position in the (posted_date, id) ordering used by
+// vacancy listings. Before selects the page preceding the position.
+type VacancyCursor struct {
+	PostedDate time.Time `json:"p"`
+	ID         uint      `json:"i"`
+	Before     bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form handed out to clients.
+func (c VacancyCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeVacancyCursor(s string) (VacancyCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return VacancyCursor{}, ErrInvalidCursor
+	}
+	var c VacancyCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 || c.PostedDate.IsZero() {
+		return VacancyCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+type VacancyPage struct {
+	Vacancies  []Vacancy `json:"vacancies"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	PrevCursor string    `json:"prevCursor,omitempty"`
+}
+
+// NewVacancyPage wraps vacancies listed newest first and derives the cursors
+// pointing past the last and before the first item.
+func NewVacancyPage(vacancies []Vacancy, hasNext, hasPrev bool) VacancyPage {
+	page := VacancyPage{Vacancies: vacancies}
+	if len(vacancies) == 0 {
+		return page
+	}
+	if hasNext {
+		last := vacancies[len(vacancies)-1]
+		page.NextCursor = VacancyCursor{PostedDate: last.PostedDate, ID: last.ID}.Encode()
+	}
+	if hasPrev {
+		first := vacancies[0]
+		page.PrevCursor = VacancyCursor{PostedDate: first.PostedDate, ID: first.ID, Before: true}.Encode()
+	}
+	return page
+}
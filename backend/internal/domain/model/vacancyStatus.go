@@ -0,0 +1,49 @@
+package model
+
+type VacancyStatus string
+
+const (
+	VacancyDraft     VacancyStatus = "DRAFT"
+	VacancyPublished VacancyStatus = "PUBLISHED"
+	VacancyPaused    VacancyStatus = "PAUSED"
+	VacancyClosed    VacancyStatus = "CLOSED"
+	VacancyArchived  VacancyStatus = "ARCHIVED"
+)
+
+// vacancyTransitions lists the statuses an employer may move a vacancy to.
+// Publishing a closed vacancy reopens it with a fresh expiry; ARCHIVED is
+// final.
+var vacancyTransitions = map[VacancyStatus][]VacancyStatus{
+	VacancyDraft:     {VacancyPublished, VacancyArchived},
+	VacancyPublished: {VacancyPaused, VacancyClosed, VacancyArchived},
+	VacancyPaused:    {VacancyPublished, VacancyClosed, VacancyArchived},
+	VacancyClosed:    {VacancyPublished, VacancyArchived},
+	VacancyArchived:  {},
+}
+
+func (s VacancyStatus) Valid() bool {
+	_, ok := vacancyTransitions[s]
+	return ok
+}
+
+func (s VacancyStatus) CanTransitionTo(next VacancyStatus) bool {
+	for _, allowed := range vacancyTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Listed reports whether vacancies in this status appear in public listings
+// and accept applications.
+func (s VacancyStatus) Listed() bool {
+	return s == VacancyPublished
+}
+
+// Viewable reports whether anyone but the owner may open the vacancy page.
+// Paused and closed vacancies stay reachable so applicants can still see
+// what they applied to.
+func (s VacancyStatus) Viewable() bool {
+	return s == VacancyPublished || s == VacancyPaused || s == VacancyClosed
+}
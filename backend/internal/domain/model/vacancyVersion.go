@@ -0,0 +1,110 @@
+package model
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// VacancySnapshot is the content of a vacancy as it was at one version.
+// Skills are kept sorted so snapshots compare by value.
+type VacancySnapshot struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description"`
+	Requirements   string   `json:"requirements"`
+	Location       string   `json:"location"`
+	SalaryFrom     float64  `json:"salary_from"`
+	SalaryTo       float64  `json:"salary_to"`
+	SalaryCurrency string   `json:"salary_currency"`
+	SalaryGross    bool     `json:"salary_gross"`
+	VacancyURL     string   `json:"vacancy_url"`
+	WorkSchedule   string   `json:"work_schedule"`
+	Experience     string   `json:"experience"`
+	Skills         []string `json:"skills"`
+}
+
+func NewVacancySnapshot(v Vacancy) VacancySnapshot {
+	skills := append([]string{}, v.Skills...)
+	sort.Strings(skills)
+	return VacancySnapshot{
+		Title:          v.Title,
+		Description:    v.Description,
+		Requirements:   v.Requirements,
+		Location:       v.Location,
+		SalaryFrom:     v.SalaryFrom,
+		SalaryTo:       v.SalaryTo,
+		SalaryCurrency: v.SalaryCurrency,
+		SalaryGross:    v.SalaryGross,
+		VacancyURL:     v.VacancyURL.String,
+		WorkSchedule:   v.WorkSchedule,
+		Experience:     v.Experience,
+		Skills:         skills,
+	}
+}
+
+// FieldChange is one field that differs between two versions. Field uses the
+// snapshot's JSON names.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff lists the fields of s that differ from prev.
+func (s VacancySnapshot) Diff(prev VacancySnapshot) []FieldChange {
+	fields := []FieldChange{
+		{"title", prev.Title, s.Title},
+		{"description", prev.Description, s.Description},
+		{"requirements", prev.Requirements, s.Requirements},
+		{"location", prev.Location, s.Location},
+		{"salary_from", prev.SalaryFrom, s.SalaryFrom},
+		{"salary_to", prev.SalaryTo, s.SalaryTo},
+		{"salary_currency", prev.SalaryCurrency, s.SalaryCurrency},
+		{"salary_gross", prev.SalaryGross, s.SalaryGross},
+		{"vacancy_url", prev.VacancyURL, s.VacancyURL},
+		{"work_schedule", prev.WorkSchedule, s.WorkSchedule},
+		{"experience", prev.Experience, s.Experience},
+		{"skills", prev.Skills, s.Skills},
+	}
+	changes := []FieldChange{}
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.From, f.To) {
+			changes = append(changes, f)
+		}
+	}
+	return changes
+}
+
+// OfferChanged reports whether the salary or what is required of the
+// candidate (requirements text or skills) differs from prev; applicants are
+// flagged on these changes.
+func (s VacancySnapshot) OfferChanged(prev VacancySnapshot) bool {
+	return s.SalaryFrom != prev.SalaryFrom ||
+		s.SalaryTo != prev.SalaryTo ||
+		s.SalaryCurrency != prev.SalaryCurrency ||
+		s.SalaryGross != prev.SalaryGross ||
+		s.Requirements != prev.Requirements ||
+		!reflect.DeepEqual(s.Skills, prev.Skills)
+}
+
+// VacancyVersion is one recorded state of a vacancy. Imports have no
+// ChangedBy. Changes is filled against the previous version when the history
+// is read.
+type VacancyVersion struct {
+	ID            uint            `json:"id" db:"id"`
+	VacancyID     uint            `json:"vacancyId" db:"vacancy_id"`
+	Version       int             `json:"version" db:"version"`
+	Snapshot      VacancySnapshot `json:"snapshot" db:"snapshot"`
+	ChangedBy     *uint           `json:"changedBy,omitempty" db:"changed_by"`
+	ChangedByType UserType        `json:"changedByType,omitempty" db:"changed_by_type"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	Changes       []FieldChange   `json:"changes,omitempty" db:"-"`
+}
+
+// VacancyHistory is the version list of a vacancy, oldest first. For a
+// student who applied, AppliedVersion is the version they applied to.
+type VacancyHistory struct {
+	VacancyID      uint             `json:"vacancyId"`
+	Versions       []VacancyVersion `json:"versions"`
+	AppliedVersion *int             `json:"appliedVersion,omitempty"`
+}
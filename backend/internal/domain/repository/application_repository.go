@@ -1,13 +1,25 @@
 package repository
 
-import "github.com/chotamkz/career-track-backend/internal/domain/model"
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+// ErrStatusChanged is returned by ChangeStatus when the application is no
+// longer in change.FromStatus, i.e. someone else moved it first.
+var ErrStatusChanged = errors.New("application status was changed concurrently")
 
 type ApplicationRepository interface {
 	CreateApplication(app *model.Application) error
-	UpdateApplicationStatus(appID uint, newStatus model.ApplicationStatus) error
+	ChangeStatus(change *model.ApplicationStatusChange) error
+	GetStatusHistory(appID uint) ([]model.ApplicationStatusChange, error)
 	GetApplicationsByStudentID(studentID uint) ([]model.Application, error)
 	GetApplicationByID(appID uint) (model.Application, error)
 	GetByStudentAndVacancy(studentID, vacancyID uint) (model.Application, error)
 	GetApplicationsByVacancyID(vacancyID uint) ([]model.Application, error)
 	GetApplicationsWithVacanciesAndEmployers(studentID uint) ([]model.ApplicationForStudent, error)
+	GetApplicationStats(topVacancies int) (model.ApplicationStats, error)
+	FlagVacancyChanged(vacancyID uint, at time.Time) (int64, error)
+	GetOpenApplicantIDs(vacancyID uint) ([]uint, error)
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+type BookmarkRepository interface {
+	// Adding an existing bookmark and removing a missing one are no-ops.
+	AddVacancyBookmark(studentID, vacancyID uint) error
+	RemoveVacancyBookmark(studentID, vacancyID uint) error
+	AddHackathonBookmark(studentID, hackathonID uint) error
+	RemoveHackathonBookmark(studentID, hackathonID uint) error
+	GetBookmarkedVacancies(studentID uint) ([]model.Vacancy, error)
+	GetBookmarkedHackathons(studentID uint) ([]model.Hackathon, error)
+	// GetExpiringVacancyBookmarks returns bookmarks of published vacancies
+	// expiring in (now, before] that were not yet warned about.
+	GetExpiringVacancyBookmarks(now, before time.Time) ([]model.VacancyBookmarkAlert, error)
+	MarkExpiryNotified(studentID, vacancyID uint, expiresAt time.Time) error
+	// GetClosedVacancyBookmarks returns bookmarks of vacancies closed,
+	// archived or deleted since the student was last told.
+	GetClosedVacancyBookmarks() ([]model.VacancyBookmarkAlert, error)
+	MarkClosedNotified(studentID, vacancyID uint, at time.Time) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+var (
+	ErrCompanyClaimStatusChanged = errors.New("company claim status was changed concurrently")
+	ErrCompanyAlreadyOwned       = errors.New("company already has an owner")
+)
+
+type CompanyClaimRepository interface {
+	// CreateCompanyClaim stores a PENDING claim, or replaces the email and
+	// token of the employer's pending claim to the same company. It returns
+	// sql.ErrNoRows when the employer's claim is already awaiting review.
+	CreateCompanyClaim(c *model.CompanyClaim) error
+	GetCompanyClaim(id uint) (model.CompanyClaim, error)
+	GetCompanyClaimByToken(tokenHash string) (model.CompanyClaim, error)
+	GetCompanyClaims(status model.CompanyClaimStatus, limit int) ([]model.CompanyClaim, error)
+	GetEmployerCompanyClaims(employerID uint) ([]model.CompanyClaim, error)
+	VerifyCompanyClaim(id uint, at time.Time) error
+	// ApproveCompanyClaim makes the claimant the company's owner and the
+	// owner of its vacancies, and denies the company's other open claims.
+	ApproveCompanyClaim(id, reviewerID uint, note string, at time.Time) (model.CompanyClaim, error)
+	DenyCompanyClaim(id, reviewerID uint, note string, at time.Time) (model.CompanyClaim, error)
+}
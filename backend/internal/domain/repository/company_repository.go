@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type CompanyRepository interface {
+	EnsureImportedCompany(source, externalID, name, website string) (model.Company, error)
+	EnsureEmployerCompany(employerID uint) (uint, error)
+	UpdateEmployerCompany(employerID uint, name, description string) error
+	SetCompanyWebsite(id uint, website string) error
+	GetCompany(id uint) (model.Company, error)
+	GetCompaniesByIDs(ids []uint) ([]model.Company, error)
+}
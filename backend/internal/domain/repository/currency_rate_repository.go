@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type CurrencyRateRepository interface {
+	GetRates() ([]model.CurrencyRate, error)
+	UpsertRates(rates []model.CurrencyRate) error
+}
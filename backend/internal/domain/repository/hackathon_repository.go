@@ -6,4 +6,6 @@ type HackathonRepository interface {
 	CreateHackathon(h *model.Hackathon) error
 	GetHackathons() ([]model.Hackathon, error)
 	GetHackathonByID(id uint) (model.Hackathon, error)
+	UpdateHackathon(h *model.Hackathon) error
+	DeleteHackathon(id uint) error
 }
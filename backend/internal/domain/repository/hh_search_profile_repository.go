@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+type HHSearchProfileRepository interface {
+	CreateHHSearchProfile(p *model.HHSearchProfile) error
+	GetHHSearchProfiles(enabledOnly bool) ([]model.HHSearchProfile, error)
+	GetHHSearchProfile(id uint) (model.HHSearchProfile, error)
+	GetHHSearchProfileByName(name string) (model.HHSearchProfile, error)
+	UpdateHHSearchProfile(p *model.HHSearchProfile) error
+	DeleteHHSearchProfile(id uint) error
+	// UpsertHHSearchProfile creates p or overwrites the profile of the same
+	// name.
+	UpsertHHSearchProfile(p *model.HHSearchProfile) error
+	// SetHHSearchProfileSyncedUntil moves the profile's watermark: the newest
+	// publication time its syncs have fully imported.
+	SetHHSearchProfileSyncedUntil(id uint, at time.Time) error
+}
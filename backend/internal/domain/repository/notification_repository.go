@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+type NotificationRepository interface {
+	// CreateNotification reports false when a notification with the same
+	// dedup key already exists for the user.
+	CreateNotification(n *model.Notification) (bool, error)
+	GetNotifications(userID uint, unreadOnly bool, limit, offset int) ([]model.Notification, error)
+	CountUnreadNotifications(userID uint) (int, error)
+	// MarkNotificationRead returns sql.ErrNoRows when the user has no such
+	// notification.
+	MarkNotificationRead(userID, id uint) error
+	MarkAllNotificationsRead(userID uint) (int64, error)
+	// GetUnemailedNotifications returns unread, not yet emailed
+	// notifications produced by the given saved searches.
+	GetUnemailedNotifications(savedSearchIDs []uint) ([]model.Notification, error)
+	MarkNotificationsEmailed(ids []uint, at time.Time) error
+}
@@ -8,6 +8,13 @@ type EmployerProfileRepository interface {
 	CreateEmployerProfile(profile *model.EmployerProfile) error
 }
 
+type AdminProfileRepository interface {
+	GetAdminProfile(userID uint) (model.AdminProfile, error)
+	CreateAdminProfile(profile *model.AdminProfile) error
+	HasSuperAdmin() (bool, error)
+	TouchLastAccess(userID uint) error
+}
+
 type StudentProfileRepository interface {
 	GetStudentProfile(userID uint) (model.StudentProfile, error)
 	UpdateStudentProfile(profile *model.StudentProfile) error
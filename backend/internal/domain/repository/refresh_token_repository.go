@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+)
+
+var ErrRefreshTokenAlreadyRotated = errors.New("refresh token already rotated or revoked")
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	GetByHash(tokenHash string) (model.RefreshToken, error)
+	Rotate(oldID uint, next *model.RefreshToken) error
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID uint) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type ResumeRepository interface {
+	CreateResume(r *model.Resume) error
+	GetResumeByID(id uint) (model.Resume, error)
+	GetResumesByStudentID(studentID uint) ([]model.Resume, error)
+	GetPrimaryResume(studentID uint) (model.Resume, error)
+	SetPrimary(studentID, resumeID uint) error
+	DeleteResume(id uint) error
+	CountApplicationsWithResume(resumeID uint) (int, error)
+	IsResumeSharedWithEmployer(resumeID, employerID uint) (bool, error)
+}
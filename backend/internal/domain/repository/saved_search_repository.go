@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+type SavedSearchRepository interface {
+	CreateSavedSearch(s *model.SavedSearch) error
+	GetSavedSearches(studentID uint) ([]model.SavedSearch, error)
+	CountSavedSearches(studentID uint) (int, error)
+	// GetSavedSearch and UpdateSavedSearch return sql.ErrNoRows when the
+	// student has no such saved search.
+	GetSavedSearch(studentID, id uint) (model.SavedSearch, error)
+	UpdateSavedSearch(s *model.SavedSearch) error
+	DeleteSavedSearch(studentID, id uint) error
+	GetAllSavedSearches() ([]model.SavedSearch, error)
+	MarkSavedSearchChecked(id uint, at time.Time) error
+	// GetDigestDueSavedSearches returns searches whose digest interval has
+	// passed since their last digest.
+	GetDigestDueSavedSearches(now time.Time) ([]model.SavedSearch, error)
+	// ClaimDigests atomically marks those of ids that are still due as
+	// digested at now and returns them; ReleaseDigest gives one back after
+	// its digest could not be sent.
+	ClaimDigests(ids []uint, now time.Time) ([]uint, error)
+	ReleaseDigest(id uint, claimedAt, prev time.Time) error
+}
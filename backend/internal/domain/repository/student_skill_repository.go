@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type StudentSkillRepository interface {
+	GetStudentSkills(studentID uint) ([]model.StudentSkill, error)
+	GetStudentSkill(studentID, skillID uint) (model.StudentSkill, error)
+	UpsertStudentSkill(s *model.StudentSkill) error
+	DeleteStudentSkill(studentID, skillID uint) error
+}
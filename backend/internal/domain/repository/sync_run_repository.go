@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type SyncRunRepository interface {
+	CreateSyncRun(r *model.SyncRun) error
+	FinishSyncRun(r *model.SyncRun) error
+	// FailInterruptedSyncRuns marks runs of the source still RUNNING, left
+	// behind by a process that died mid-sync, as FAILED.
+	FailInterruptedSyncRuns(source string) (int64, error)
+	GetSyncRuns(source string, limit int) ([]model.SyncRun, error)
+}
@@ -3,8 +3,10 @@ package repository
 import "github.com/chotamkz/career-track-backend/internal/domain/model"
 
 type UserRepository interface {
-	EnsureEmployerExists(employerID uint, companyName string) error
 	CreateUser(v *model.User) error
 	GetUserByEmail(email string) (model.User, error)
 	GetByID(userID uint) (model.User, error)
+	SearchUsers(filter model.UserFilter, limit, offset int) ([]model.User, int, error)
+	SetBanned(userID uint, banned bool, reason string) error
+	DeleteUser(userID uint) error
 }
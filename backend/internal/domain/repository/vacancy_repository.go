@@ -1,20 +1,41 @@
 package repository
 
-import "github.com/chotamkz/career-track-backend/internal/domain/model"
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+// ErrVacancyStatusChanged is returned by ChangeVacancyStatus when the vacancy
+// is no longer in the expected status.
+var ErrVacancyStatusChanged = errors.New("vacancy status was changed concurrently")
 
 type VacancyRepository interface {
 	GetVacancies(limit, offset int) ([]model.Vacancy, error)
-	UpsertVacancy(v *model.Vacancy) error
+	UpsertVacancy(v *model.Vacancy) (model.UpsertResult, error)
 	GetVacancyById(id uint) (model.Vacancy, error)
 	GetFilteredVacancies(filter model.VacancyFilter, limit, offset int) ([]model.Vacancy, error)
 	CountFilteredVacancies(filter model.VacancyFilter) (int, error)
+	GetVacanciesByCursor(cursor *model.VacancyCursor, limit int, studentID *uint) ([]model.Vacancy, error)
+	GetFilteredVacanciesByCursor(filter model.VacancyFilter, cursor *model.VacancyCursor, limit int) ([]model.Vacancy, error)
+	GetFilteredVacanciesChangedBetween(filter model.VacancyFilter, after model.VacancyChangeCursor, to time.Time, limit int) ([]model.Vacancy, error)
+	GetVacancyFacets(filter model.VacancyFilter, salaryThresholds []float64, limit int) (model.VacancyFacets, error)
 	CreateVacancy(v *model.Vacancy) error
-	InsertVacancySkill(vacancyID uint, skillName string) error
 	GetVacanciesByIDs(ids []uint) ([]model.Vacancy, error)
 	CountVacancies() (int, error)
 	GetVacanciesByEmployerID(employerID uint) ([]model.Vacancy, error)
 	DeleteVacancyByID(vacancyID uint) error
+	ChangeVacancyStatus(v *model.Vacancy, from model.VacancyStatus) error
+	PublishScheduledVacancies(now time.Time, ttl time.Duration) (int64, error)
+	GetStaleImportedVacancies(source string, before time.Time, limit int) ([]model.ImportedVacancy, error)
+	MarkVacancyChecked(id uint, at time.Time, seen bool) error
+	ExpireVacancies(now time.Time) (int64, error)
+	ArchiveClosedVacancies(closedBefore time.Time) (int64, error)
 	UpdateVacancy(vac *model.Vacancy) error
+	RenormalizeSalaries(netRatio float64) (int64, error)
+	GetSalaryNetRatio() (ratio float64, ok bool, err error)
+	GetUnsanitizedDescriptions(limit int) ([]model.Vacancy, error)
+	SetDescription(id uint, descriptionHTML, descriptionText, requirements string) error
 	SetSkills(vacancyID uint, skillIDs []uint) error
 	GetAllRegions() ([]string, error)
 	GetVacanciesWithApplicationStatus(limit, offset int, studentID uint) ([]model.Vacancy, error)
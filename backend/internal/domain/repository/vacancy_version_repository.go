@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/chotamkz/career-track-backend/internal/domain/model"
+
+type VacancyVersionRepository interface {
+	// GetLatestVersion returns sql.ErrNoRows for a vacancy without versions.
+	GetLatestVersion(vacancyID uint) (model.VacancyVersion, error)
+	CreateVersion(v *model.VacancyVersion) error
+	GetVersions(vacancyID uint) ([]model.VacancyVersion, error)
+}
@@ -0,0 +1,77 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+)
+
+const defaultInterval = time.Hour
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// Config describes one JSON vacancy feed.
+type Config struct {
+	Name      string
+	URL       string
+	Interval  time.Duration
+	UserAgent string
+}
+
+// LoadConfigs reads feeds from a JSON file of the form
+//
+//	{"feeds": [{"name": "enbek", "url": "https://example.kz/vacancies.json",
+//	  "interval": "2h", "user_agent": "CareerTrack/1.0", "enabled": true}]}
+//
+// Disabled feeds are left out. reserved lists source names already taken,
+// such as "hh".
+func LoadConfigs(path string, reserved ...string) ([]Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read feeds file: %w", err)
+	}
+	var doc struct {
+		Feeds []struct {
+			Name      string `json:"name"`
+			URL       string `json:"url"`
+			Interval  string `json:"interval"`
+			UserAgent string `json:"user_agent"`
+			Enabled   *bool  `json:"enabled"`
+		} `json:"feeds"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse feeds file %s: %w", path, err)
+	}
+
+	taken := make(map[string]bool)
+	for _, name := range reserved {
+		taken[name] = true
+	}
+	var configs []Config
+	for _, f := range doc.Feeds {
+		if !namePattern.MatchString(f.Name) {
+			return nil, fmt.Errorf("feed name %q must be 1-40 lowercase letters, digits or dashes", f.Name)
+		}
+		if taken[f.Name] {
+			return nil, fmt.Errorf("feed name %q is already taken", f.Name)
+		}
+		taken[f.Name] = true
+		if u, err := url.Parse(f.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("feed %s: invalid url %q", f.Name, f.URL)
+		}
+		interval := defaultInterval
+		if f.Interval != "" {
+			if interval, err = time.ParseDuration(f.Interval); err != nil || interval <= 0 {
+				return nil, fmt.Errorf("feed %s: invalid interval %q", f.Name, f.Interval)
+			}
+		}
+		if f.Enabled != nil && !*f.Enabled {
+			continue
+		}
+		configs = append(configs, Config{Name: f.Name, URL: f.URL, Interval: interval, UserAgent: f.UserAgent})
+	}
+	return configs, nil
+}
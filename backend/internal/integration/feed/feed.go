@@ -0,0 +1,219 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/integration"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	requestTimeout = time.Minute
+	maxFeedBytes   = 50 << 20
+	pageSize       = 100
+)
+
+// Vacancy is one entry of a JSON feed:
+//
+//	{"vacancies": [{"id": "A-17", "title": "Go developer", "description": "...",
+//	  "requirements": "...", "location": "Almaty", "url": "https://...",
+//	  "published_at": "2024-05-01T10:00:00Z",
+//	  "employer": {"id": "acme", "name": "ACME", "website": "https://acme.kz"},
+//	  "salary": {"from": 300000, "to": 500000, "currency": "KZT", "gross": true},
+//	  "work_schedule": "Full day", "experience": "1-3 years",
+//	  "skills": ["Go", "SQL"], "archived": false}]}
+//
+// A vacancy missing from the feed or marked archived is considered removed.
+type Vacancy struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Requirements string `json:"requirements"`
+	Location     string `json:"location"`
+	URL          string `json:"url"`
+	PublishedAt  string `json:"published_at"`
+	Employer     struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Website string `json:"website"`
+	} `json:"employer"`
+	Salary *struct {
+		From     float64 `json:"from"`
+		To       float64 `json:"to"`
+		Currency string  `json:"currency"`
+		Gross    bool    `json:"gross"`
+	} `json:"salary"`
+	WorkSchedule string   `json:"work_schedule"`
+	Experience   string   `json:"experience"`
+	Skills       []string `json:"skills"`
+	Archived     bool     `json:"archived"`
+}
+
+// Source imports a JSON vacancy feed. Feeds are always read whole, so List
+// ignores full.
+type Source struct {
+	cfg    Config
+	client *http.Client
+	logger *util.Logger
+}
+
+func NewSource(cfg Config, logger *util.Logger) *Source {
+	return &Source{cfg: cfg, client: &http.Client{Timeout: requestTimeout}, logger: logger}
+}
+
+func (s *Source) Name() string {
+	return s.cfg.Name
+}
+
+// Interval is how often the feed is synced.
+func (s *Source) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+func (s *Source) List(ctx context.Context, full bool, l integration.Listing) error {
+	vacancies, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	items := make([]integration.Item, 0, len(vacancies))
+	for _, v := range vacancies {
+		if v.Archived {
+			continue
+		}
+		if strings.TrimSpace(v.ID) == "" {
+			l.Errorf("feed %s: vacancy %q has no id", s.cfg.Name, v.Title)
+			continue
+		}
+		items = append(items, itemOf(v))
+	}
+	for start := 0; start < len(items); start += pageSize {
+		end := start + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		// Feeds are read whole every sync, so failed items are retried
+		// without tracking them.
+		if _, err := l.Page(items[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Details reads the feed once and looks every id up in it.
+func (s *Source) Details(ctx context.Context, externalIDs []string) ([]integration.Item, []error) {
+	items := make([]integration.Item, len(externalIDs))
+	errs := make([]error, len(externalIDs))
+	vacancies, err := s.fetch(ctx)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return items, errs
+	}
+	byID := make(map[string]Vacancy, len(vacancies))
+	for _, v := range vacancies {
+		byID[v.ID] = v
+	}
+	for i, id := range externalIDs {
+		v, ok := byID[id]
+		if !ok || v.Archived {
+			errs[i] = integration.ErrVacancyGone
+			continue
+		}
+		items[i] = itemOf(v)
+	}
+	return items, errs
+}
+
+func (s *Source) Map(ctx context.Context, items []integration.Item) ([]model.Vacancy, []error) {
+	vacancies := make([]model.Vacancy, len(items))
+	errs := make([]error, len(items))
+	for i, item := range items {
+		v, ok := item.Raw.(Vacancy)
+		if !ok {
+			errs[i] = fmt.Errorf("item %s was not listed by feed %s", item.ExternalID, s.cfg.Name)
+			continue
+		}
+		vacancies[i] = mapVacancy(v, item.PublishedAt)
+	}
+	return vacancies, errs
+}
+
+func (s *Source) fetch(ctx context.Context) ([]Vacancy, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", s.cfg.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+	s.logger.Infof("Fetching vacancy feed %s: %s", s.cfg.Name, s.cfg.URL)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed %s returned status: %d", s.cfg.Name, resp.StatusCode)
+	}
+	var doc struct {
+		Vacancies []Vacancy `json:"vacancies"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFeedBytes)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode feed %s: %w", s.cfg.Name, err)
+	}
+	return doc.Vacancies, nil
+}
+
+func itemOf(v Vacancy) integration.Item {
+	item := integration.Item{
+		ExternalID: v.ID,
+		Employer:   integration.Employer{ExternalID: v.Employer.ID, Name: v.Employer.Name, Website: v.Employer.Website},
+		Raw:        v,
+	}
+	if t, err := time.Parse(time.RFC3339, v.PublishedAt); err == nil {
+		item.PublishedAt = t
+	}
+	return item
+}
+
+func mapVacancy(v Vacancy, publishedAt time.Time) model.Vacancy {
+	vacancy := model.Vacancy{
+		Title:        strings.TrimSpace(v.Title),
+		Description:  v.Description,
+		Requirements: v.Requirements,
+		Location:     v.Location,
+		WorkSchedule: v.WorkSchedule,
+		Experience:   v.Experience,
+		PostedDate:   publishedAt,
+		CreatedAt:    time.Now(),
+		VacancyURL:   sql.NullString{String: v.URL, Valid: v.URL != ""},
+	}
+	if vacancy.PostedDate.IsZero() {
+		vacancy.PostedDate = time.Now()
+	}
+	if vacancy.Location == "" {
+		vacancy.Location = "Unknown"
+	}
+	for _, skill := range v.Skills {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			vacancy.Skills = append(vacancy.Skills, skill)
+		}
+	}
+	if v.Salary != nil {
+		vacancy.SalaryFrom = v.Salary.From
+		vacancy.SalaryTo = v.Salary.To
+		vacancy.SalaryCurrency = strings.ToUpper(v.Salary.Currency)
+		vacancy.SalaryGross = v.Salary.Gross
+	}
+	return vacancy
+}
@@ -0,0 +1,297 @@
+package hh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://api.hh.ru"
+
+	defaultTimeout       = 15 * time.Second
+	defaultRateLimit     = 5
+	defaultMaxRetries    = 4
+	defaultDetailWorkers = 4
+
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+	// maxRetryAfter caps how long a Retry-After header can stall a sync.
+	maxRetryAfter = 2 * time.Minute
+	// maxErrorBody is how much of an error response is kept in StatusError.
+	maxErrorBody = 512
+)
+
+// ClientConfig configures a Client. Zero values fall back to defaults, except
+// UserAgent, which HH requires in the form "AppName/1.0 (contact@example.com)".
+type ClientConfig struct {
+	BaseURL   string
+	UserAgent string
+	// Timeout bounds a single HTTP request, retries excluded.
+	Timeout time.Duration
+	// RateLimit is the average number of requests per second; bursts of up
+	// to one second's worth are allowed.
+	RateLimit float64
+	// MaxRetries is how often a request failing with a network error, 429 or
+	// 5xx is retried.
+	MaxRetries int
+	// DetailWorkers bounds concurrent vacancy detail requests.
+	DetailWorkers int
+}
+
+// StatusError is returned for a response HH answered with a non-200 status
+// after retries were exhausted or were not applicable.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HH API returned status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HH API returned status: %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is HH answering 404.
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotFound
+}
+
+// Client talks to the HH.ru API. It is safe for concurrent use; all requests
+// share one rate limiter.
+type Client struct {
+	baseURL       string
+	userAgent     string
+	http          *http.Client
+	limiter       *tokenBucket
+	maxRetries    int
+	detailWorkers int
+	logger        *util.Logger
+}
+
+func NewClient(cfg ClientConfig, logger *util.Logger) (*Client, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid HH base URL %q", cfg.BaseURL)
+	}
+	if strings.TrimSpace(cfg.UserAgent) == "" {
+		return nil, errors.New("HH client requires a User-Agent")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = defaultRateLimit
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.DetailWorkers <= 0 {
+		cfg.DetailWorkers = defaultDetailWorkers
+	}
+	burst := int(cfg.RateLimit)
+	if burst < 1 {
+		burst = 1
+	}
+	return &Client{
+		baseURL:       strings.TrimRight(cfg.BaseURL, "/"),
+		userAgent:     cfg.UserAgent,
+		http:          &http.Client{Timeout: cfg.Timeout},
+		limiter:       newTokenBucket(cfg.RateLimit, burst),
+		maxRetries:    cfg.MaxRetries,
+		detailWorkers: cfg.DetailWorkers,
+		logger:        logger,
+	}, nil
+}
+
+// FetchVacancies returns one page of the profile's vacancies published
+// between from and to, along with the size of the whole result.
+func (c *Client) FetchVacancies(ctx context.Context, profile model.HHSearchProfile, from, to time.Time, perPage, page int) (HHVacancyResponse, error) {
+	var resp HHVacancyResponse
+	err := c.getJSON(ctx, "/vacancies", searchQuery(profile, from, to, perPage, page), &resp)
+	return resp, err
+}
+
+func (c *Client) FetchVacancyDetail(ctx context.Context, vacancyID string) (HHVacancyDetail, error) {
+	var detail HHVacancyDetail
+	err := c.getJSON(ctx, "/vacancies/"+url.PathEscape(vacancyID), nil, &detail)
+	return detail, err
+}
+
+func (c *Client) FetchEmployer(ctx context.Context, employerID string) (HHEmployer, error) {
+	var employer HHEmployer
+	err := c.getJSON(ctx, "/employers/"+url.PathEscape(employerID), nil, &employer)
+	return employer, err
+}
+
+// FetchVacancyDetails fetches the details of several vacancies concurrently.
+// details[i] and errs[i] belong to ids[i].
+func (c *Client) FetchVacancyDetails(ctx context.Context, ids []string) (details []HHVacancyDetail, errs []error) {
+	details = make([]HHVacancyDetail, len(ids))
+	errs = make([]error, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	workers := c.detailWorkers
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				details[i], errs[i] = c.FetchVacancyDetail(ctx, ids[i])
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return details, errs
+}
+
+// MapVacancies maps a page of search results to vacancies, fetching their
+// details concurrently. The result is in the order of hhVacancies; errs[i]
+// is the error fetching the detail of hhVacancies[i], which is then left
+// unmapped. When ctx is cancelled midway some details are missing, so
+// callers must check ctx before using the result.
+func (c *Client) MapVacancies(ctx context.Context, hhVacancies []HHVacancy) (vacancies []model.Vacancy, errs []error) {
+	ids := make([]string, len(hhVacancies))
+	for i, v := range hhVacancies {
+		ids[i] = v.ID
+	}
+	details, errs := c.FetchVacancyDetails(ctx, ids)
+	vacancies = make([]model.Vacancy, len(hhVacancies))
+	for i, hhVac := range hhVacancies {
+		if errs[i] != nil {
+			if ctx.Err() == nil {
+				c.logger.Errorf("Failed to fetch detail for vacancy %s: %v", hhVac.ID, errs[i])
+			}
+			continue
+		}
+		vacancies[i] = mapHHVacancy(hhVac, details[i])
+	}
+	return vacancies, errs
+}
+
+// getJSON GETs path and decodes the response into out. Network errors, 429
+// and 5xx responses are retried with exponential backoff and full jitter,
+// or after the delay a Retry-After header asks for.
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.do(ctx, u, out)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if retryAfter < 0 || attempt >= c.maxRetries {
+			return err
+		}
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoff(attempt)
+		}
+		c.logger.Warnf("HH request %s failed (%v), retrying in %s", u, err, delay.Round(time.Millisecond))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do makes one request. On failure it returns how long to wait before
+// retrying: zero for the default backoff, negative when the request must not
+// be retried.
+func (c *Client) do(ctx context.Context, u string, out interface{}) (time.Duration, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return -1, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("HH-User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	c.logger.Debugf("Fetching from HH API: %s", u)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		err := &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return parseRetryAfter(resp.Header.Get("Retry-After")), err
+		}
+		return -1, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return -1, fmt.Errorf("decode HH response: %w", err)
+	}
+	return 0, nil
+}
+
+// backoff returns a random delay of up to retryBaseDelay*2^attempt, capped
+// at retryMaxDelay.
+func backoff(attempt int) time.Duration {
+	ceiling := retryMaxDelay
+	if attempt < 16 {
+		if d := retryBaseDelay << attempt; d < ceiling {
+			ceiling = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + time.Millisecond
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date; it returns zero when the header is absent or unusable.
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(v); err == nil {
+		d = time.Until(at)
+	}
+	if d <= 0 {
+		return 0
+	}
+	if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d
+}
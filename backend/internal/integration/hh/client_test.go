@@ -0,0 +1,225 @@
+package hh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testUserAgent = "CareerTrackTest/1.0 (test@example.com)"
+
+func newTestClient(t *testing.T, cfg ClientConfig, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	cfg.BaseURL = srv.URL
+	cfg.UserAgent = testUserAgent
+	if cfg.RateLimit == 0 {
+		// High enough that the limiter never delays a test.
+		cfg.RateLimit = 1000
+	}
+	c, err := NewClient(cfg, util.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestClientRetriesAfterRetryAfter(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"description":"ok"}`)
+	})
+
+	start := time.Now()
+	detail, err := c.FetchVacancyDetail(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("FetchVacancyDetail: %v", err)
+	}
+	if detail.Description != "ok" {
+		t.Errorf("description = %q, want %q", detail.Description, "ok")
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, ClientConfig{MaxRetries: 3}, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"description":"ok"}`)
+	})
+
+	detail, err := c.FetchVacancyDetail(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("FetchVacancyDetail: %v", err)
+	}
+	if detail.Description != "ok" {
+		t.Errorf("description = %q, want %q", detail.Description, "ok")
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, ClientConfig{MaxRetries: 2}, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	_, err := c.FetchVacancyDetail(context.Background(), "1")
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("err = %v, want a 500 StatusError", err)
+	}
+	if se.Body != "boom" {
+		t.Errorf("error body = %q, want %q", se.Body, "boom")
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("got %d requests, want the first one and 2 retries", n)
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, r)
+	})
+
+	_, err := c.FetchVacancyDetail(context.Background(), "1")
+	if !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestClientSendsUserAgent(t *testing.T) {
+	c := newTestClient(t, ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		for _, h := range []string{"User-Agent", "HH-User-Agent"} {
+			if got := r.Header.Get(h); got != testUserAgent {
+				t.Errorf("%s = %q, want %q", h, got, testUserAgent)
+			}
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	if _, err := c.FetchVacancyDetail(context.Background(), "1"); err != nil {
+		t.Fatalf("FetchVacancyDetail: %v", err)
+	}
+}
+
+func TestNewClientRequiresUserAgent(t *testing.T) {
+	if _, err := NewClient(ClientConfig{}, util.NewLogger("error")); err == nil {
+		t.Fatal("NewClient without a User-Agent succeeded")
+	}
+}
+
+func TestFetchVacancyDetailsBoundsWorkers(t *testing.T) {
+	const workers = 3
+	var inFlight, maxInFlight int32
+	c := newTestClient(t, ClientConfig{DetailWorkers: workers}, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintf(w, `{"description":%q}`, strings.TrimPrefix(r.URL.Path, "/vacancies/"))
+	})
+
+	ids := make([]string, 4*workers)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i)
+	}
+	details, errs := c.FetchVacancyDetails(context.Background(), ids)
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Errorf("detail %s: %v", id, errs[i])
+		} else if details[i].Description != id {
+			t.Errorf("details[%d] belongs to vacancy %q, want %q", i, details[i].Description, id)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > workers {
+		t.Errorf("%d requests in flight, want at most %d", max, workers)
+	}
+}
+
+func TestMapVacanciesSkipsFailedDetails(t *testing.T) {
+	c := newTestClient(t, ClientConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/vacancies/2" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"description":"full","address":{"raw":"Almaty"}}`)
+	})
+
+	vacancies, errs := c.MapVacancies(context.Background(), []HHVacancy{{ID: "1", Name: "Go"}, {ID: "2", Name: "Rust"}})
+	if errs[0] != nil || vacancies[0].Location != "Almaty" {
+		t.Errorf("vacancy 1 = %+v, %v; want it mapped from its detail", vacancies[0], errs[0])
+	}
+	if !IsNotFound(errs[1]) {
+		t.Errorf("errs[1] = %v, want not found", errs[1])
+	}
+}
+
+// The limiter is shared by all workers, so a burst of concurrent requests is
+// spread out at the configured rate.
+func TestTokenBucketLimitsRate(t *testing.T) {
+	b := newTokenBucket(20, 1)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.Wait(context.Background()); err != nil {
+				t.Errorf("Wait: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	// One token is available at once, the other four come 50ms apart.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("5 requests at 20/s with a burst of 1 took %s", elapsed)
+	}
+}
+
+func TestTokenBucketWaitHonoursContext(t *testing.T) {
+	b := newTokenBucket(0.1, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want the context deadline", err)
+	}
+}
@@ -0,0 +1,102 @@
+package hh
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// MaxPerPage is the largest page HH serves.
+	MaxPerPage = 100
+	// MaxSearchDepth is how many results of one search HH lets a client page
+	// through; narrower searches are needed to reach the rest.
+	MaxSearchDepth = 2000
+
+	timeLayout = "2006-01-02T15:04:05-0700"
+)
+
+// ParseTime parses HH timestamps such as "2024-05-01T10:00:00+0500".
+func ParseTime(s string) (time.Time, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Parse(time.RFC3339, s)
+	}
+	return t, nil
+}
+
+// SearchProfileSpec is a search profile as written in a profiles file or sent
+// to the admin API, e.g. {"name": "almaty-interns", "areas": ["160"],
+// "professional_roles": ["96"], "text": "стажер", "period_days": 7}.
+// Enabled defaults to true.
+type SearchProfileSpec struct {
+	Name              string   `json:"name"`
+	Areas             []string `json:"areas"`
+	ProfessionalRoles []string `json:"professional_roles"`
+	Text              string   `json:"text"`
+	OnlyWithSalary    bool     `json:"only_with_salary"`
+	PeriodDays        int      `json:"period_days"`
+	Enabled           *bool    `json:"enabled"`
+}
+
+func (s SearchProfileSpec) Profile() model.HHSearchProfile {
+	enabled := s.Enabled == nil || *s.Enabled
+	return model.HHSearchProfile{
+		Name:              s.Name,
+		Areas:             s.Areas,
+		ProfessionalRoles: s.ProfessionalRoles,
+		Text:              s.Text,
+		OnlyWithSalary:    s.OnlyWithSalary,
+		PeriodDays:        s.PeriodDays,
+		Enabled:           enabled,
+	}
+}
+
+// LoadSearchProfiles reads profiles from a JSON file of the form
+//
+//	{"profiles": [{"name": "kz-it", "areas": ["40"], ...}]}
+func LoadSearchProfiles(path string) ([]model.HHSearchProfile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read search profiles file: %w", err)
+	}
+	var doc struct {
+		Profiles []SearchProfileSpec `json:"profiles"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse search profiles file %s: %w", path, err)
+	}
+	profiles := make([]model.HHSearchProfile, len(doc.Profiles))
+	for i, s := range doc.Profiles {
+		profiles[i] = s.Profile()
+	}
+	return profiles, nil
+}
+
+// searchQuery selects one page of the profile's vacancies published between
+// from and to, newest first.
+func searchQuery(p model.HHSearchProfile, from, to time.Time, perPage, page int) url.Values {
+	q := url.Values{}
+	for _, role := range p.ProfessionalRoles {
+		q.Add("professional_role", role)
+	}
+	for _, area := range p.Areas {
+		q.Add("area", area)
+	}
+	if p.Text != "" {
+		q.Set("text", p.Text)
+	}
+	if p.OnlyWithSalary {
+		q.Set("only_with_salary", "true")
+	}
+	q.Set("date_from", from.Format(timeLayout))
+	q.Set("date_to", to.Format(timeLayout))
+	q.Set("order_by", "publication_time")
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("page", strconv.Itoa(page))
+	return q
+}
@@ -0,0 +1,250 @@
+package hh
+
+import (
+	"context"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/integration"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"strings"
+	"time"
+)
+
+// SourceName names HH.ru in vacancies.source and sync_runs.
+const SourceName = "hh"
+
+const (
+	// syncOverlap is fetched again before a profile's watermark, so that
+	// vacancies HH indexes late are not missed.
+	syncOverlap = 10 * time.Minute
+	// minSyncWindow is the narrowest window a search is split into.
+	minSyncWindow = time.Minute
+)
+
+// Source imports the vacancies of every enabled HH search profile.
+type Source struct {
+	client   *Client
+	profiles repository.HHSearchProfileRepository
+	perPage  int
+	logger   *util.Logger
+}
+
+func NewSource(client *Client, profiles repository.HHSearchProfileRepository, perPage int, logger *util.Logger) *Source {
+	if perPage <= 0 || perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	return &Source{client: client, profiles: profiles, perPage: perPage, logger: logger}
+}
+
+func (s *Source) Name() string {
+	return SourceName
+}
+
+// List walks the vacancies of every enabled profile published since the
+// profile's watermark, or over the profile's whole period when full is set
+// or the profile has never synced.
+func (s *Source) List(ctx context.Context, full bool, l integration.Listing) error {
+	profiles, err := s.profiles.GetHHSearchProfiles(true)
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		s.logger.Warn("No enabled HH search profiles; nothing to import")
+	}
+	to := time.Now().UTC()
+	for _, profile := range profiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.listProfile(ctx, l, profile, to, full); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// profileListing tracks one profile's listing. earliestFailed is the
+// earliest publication time of a vacancy that failed to import.
+type profileListing struct {
+	integration.Listing
+	profile        model.HHSearchProfile
+	latest         time.Time
+	earliestFailed time.Time
+	failed         bool
+}
+
+// listProfile lists the profile's vacancies published up to to. The
+// watermark only moves when every page was fetched, so a failed page is
+// retried by the next sync, and no further than the earliest vacancy that
+// failed to import, so the next sync lists that vacancy again.
+func (s *Source) listProfile(ctx context.Context, l integration.Listing, profile model.HHSearchProfile, to time.Time, full bool) error {
+	days := profile.PeriodDays
+	if days == 0 {
+		days = model.MaxHHSearchPeriodDays
+	}
+	from := to.AddDate(0, 0, -days)
+	if profile.SyncedUntil != nil && !full {
+		from = profile.SyncedUntil.Add(-syncOverlap)
+	}
+	s.logger.Infof("Syncing HH search profile %s from %s", profile.Name, from.Format(time.RFC3339))
+
+	pl := &profileListing{Listing: l, profile: profile}
+	if err := s.listWindow(ctx, pl, from, to); err != nil {
+		return err
+	}
+	if pl.failed || pl.latest.IsZero() {
+		return nil
+	}
+	if !pl.earliestFailed.IsZero() && pl.earliestFailed.Before(pl.latest) {
+		pl.latest = pl.earliestFailed
+	}
+	if profile.SyncedUntil != nil && !pl.latest.After(*profile.SyncedUntil) {
+		return nil
+	}
+	if err := s.profiles.SetHHSearchProfileSyncedUntil(profile.ID, pl.latest); err != nil {
+		s.logger.Errorf("Failed to save watermark of HH search profile %s: %v", profile.Name, err)
+		l.Errorf("profile %s: save watermark: %v", profile.Name, err)
+	}
+	return nil
+}
+
+// listWindow walks every page of the vacancies published between from and
+// to. A window with more results than HH lets a client page through is split
+// in two until each half fits.
+func (s *Source) listWindow(ctx context.Context, pl *profileListing, from, to time.Time) error {
+	resp, err := s.fetchPage(ctx, pl, from, to, 0)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	if resp.Found > MaxSearchDepth {
+		if to.Sub(from) > minSyncWindow {
+			mid := from.Add(to.Sub(from) / 2)
+			s.logger.Infof("HH search profile %s found %d vacancies between %s and %s; splitting the window",
+				pl.profile.Name, resp.Found, from.Format(time.RFC3339), to.Format(time.RFC3339))
+			if err := s.listWindow(ctx, pl, from, mid); err != nil {
+				return err
+			}
+			return s.listWindow(ctx, pl, mid, to)
+		}
+		s.logger.Warnf("HH search profile %s found %d vacancies within %s of %s; only %d are reachable",
+			pl.profile.Name, resp.Found, minSyncWindow, from.Format(time.RFC3339), MaxSearchDepth)
+		pl.Errorf("profile %s: %d vacancies at %s exceed the HH search depth", pl.profile.Name, resp.Found, from.Format(time.RFC3339))
+	}
+
+	pages := resp.Pages
+	if maxPages := MaxSearchDepth / s.perPage; pages > maxPages {
+		pages = maxPages
+	}
+	for page := 0; page < pages; page++ {
+		if page > 0 {
+			if resp, err = s.fetchPage(ctx, pl, from, to, page); err != nil {
+				return err
+			}
+			if resp == nil {
+				continue
+			}
+		}
+		items := make([]integration.Item, len(resp.Items))
+		for i, v := range resp.Items {
+			items[i] = itemOf(v)
+			if items[i].PublishedAt.After(pl.latest) {
+				pl.latest = items[i].PublishedAt
+			}
+		}
+		failed, err := pl.Page(items)
+		if err != nil {
+			return err
+		}
+		for _, item := range failed {
+			if item.PublishedAt.IsZero() {
+				// Without a publication time the watermark cannot be
+				// capped below the vacancy, so it stays where it is.
+				pl.failed = true
+			} else if pl.earliestFailed.IsZero() || item.PublishedAt.Before(pl.earliestFailed) {
+				pl.earliestFailed = item.PublishedAt
+			}
+		}
+	}
+	return nil
+}
+
+// fetchPage returns nil and records the failure when the page could not be
+// fetched; the only error returned is ctx's.
+func (s *Source) fetchPage(ctx context.Context, pl *profileListing, from, to time.Time, page int) (*HHVacancyResponse, error) {
+	resp, err := s.client.FetchVacancies(ctx, pl.profile, from, to, s.perPage, page)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		s.logger.Errorf("Error fetching vacancies for profile %s, page %d: %v", pl.profile.Name, page, err)
+		pl.Errorf("profile %s page %d (from %s): %v", pl.profile.Name, page, from.Format(time.RFC3339), err)
+		pl.failed = true
+		return nil, nil
+	}
+	return &resp, nil
+}
+
+// Details fetches vacancy details concurrently; archived and unknown
+// vacancies are reported as gone.
+func (s *Source) Details(ctx context.Context, externalIDs []string) ([]integration.Item, []error) {
+	details, errs := s.client.FetchVacancyDetails(ctx, externalIDs)
+	items := make([]integration.Item, len(externalIDs))
+	for i, id := range externalIDs {
+		switch {
+		case IsNotFound(errs[i]):
+			errs[i] = integration.ErrVacancyGone
+		case errs[i] == nil && details[i].Archived:
+			errs[i] = integration.ErrVacancyGone
+		}
+		items[i] = integration.Item{ExternalID: id, Raw: details[i]}
+	}
+	return items, errs
+}
+
+// EmployerWebsite looks up the site HH lists for the employer. Search
+// results do not include it, so it is fetched on demand.
+func (s *Source) EmployerWebsite(ctx context.Context, externalID string) (string, error) {
+	employer, err := s.client.FetchEmployer(ctx, externalID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(employer.SiteURL), nil
+}
+
+// Map maps listed HH vacancies, fetching their details concurrently.
+func (s *Source) Map(ctx context.Context, items []integration.Item) ([]model.Vacancy, []error) {
+	hhVacancies := make([]HHVacancy, 0, len(items))
+	listed := make([]int, 0, len(items))
+	errs := make([]error, len(items))
+	for i, item := range items {
+		v, ok := item.Raw.(HHVacancy)
+		if !ok {
+			errs[i] = fmt.Errorf("item %s was not listed by the HH source", item.ExternalID)
+			continue
+		}
+		hhVacancies = append(hhVacancies, v)
+		listed = append(listed, i)
+	}
+	mapped, mapErrs := s.client.MapVacancies(ctx, hhVacancies)
+	vacancies := make([]model.Vacancy, len(items))
+	for j, i := range listed {
+		vacancies[i], errs[i] = mapped[j], mapErrs[j]
+	}
+	return vacancies, errs
+}
+
+func itemOf(v HHVacancy) integration.Item {
+	item := integration.Item{
+		ExternalID: v.ID,
+		Employer:   integration.Employer{ExternalID: v.Employer.ID, Name: v.Employer.Name},
+		Raw:        v,
+	}
+	if posted, err := ParseTime(v.PostedDate); err == nil {
+		item.PublishedAt = posted
+	}
+	return item
+}
@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"time"
+)
+
+// ErrVacancyGone is returned by VacancySource.Details for a vacancy the
+// source removed or archived.
+var ErrVacancyGone = errors.New("vacancy is no longer listed by the source")
+
+// Employer identifies a vacancy's employer within its source. Website is the
+// employer's own site, empty when the listing does not say.
+type Employer struct {
+	ExternalID string
+	Name       string
+	Website    string
+}
+
+// Item is a vacancy as a source lists it. ExternalID is the source's own id
+// of the vacancy and, together with the source name, identifies it across
+// syncs. Raw is the source's record, handed back to Map.
+type Item struct {
+	ExternalID  string
+	Employer    Employer
+	PublishedAt time.Time
+	Raw         interface{}
+}
+
+// Listing receives what a source lists during a sync.
+type Listing interface {
+	// Page imports one batch of items and returns those it failed to import,
+	// which an incremental source must list again next time. An error stops
+	// the listing.
+	Page(items []Item) (failed []Item, err error)
+	// Errorf records a failure the source worked around, such as a page it
+	// could not fetch.
+	Errorf(format string, args ...interface{})
+}
+
+// VacancySource is an external job board vacancies are imported from.
+type VacancySource interface {
+	// Name identifies the source in vacancies.source and sync_runs.
+	Name() string
+	// List walks the vacancies the source offers. Sources that can list
+	// incrementally do so unless full is set.
+	List(ctx context.Context, full bool, l Listing) error
+	// Details looks vacancies up by external id. errs[i] is ErrVacancyGone
+	// when the source no longer lists externalIDs[i].
+	Details(ctx context.Context, externalIDs []string) (items []Item, errs []error)
+	// Map converts items the source listed to vacancies, in order. The
+	// caller sets the source, external id and employer. errs[i] is set when
+	// items[i] could not be mapped in full; vacancies[i] must then be skipped
+	// so the stored vacancy is left as it is. When ctx is cancelled midway
+	// the result may be incomplete and must be discarded.
+	Map(ctx context.Context, items []Item) (vacancies []model.Vacancy, errs []error)
+}
+
+// EmployerDirectory is implemented by sources that can look up an employer's
+// website when their listings do not carry it.
+type EmployerDirectory interface {
+	// EmployerWebsite returns the website of the employer the source knows
+	// as externalID, or "" when it has none.
+	EmployerWebsite(ctx context.Context, externalID string) (string, error)
+}
+
+// Scheduled is implemented by sources that configure their own sync
+// interval, such as feeds. Other sources are synced at the server's default.
+type Scheduled interface {
+	Interval() time.Duration
+}
@@ -0,0 +1,21 @@
+package mail
+
+import (
+	"context"
+	"github.com/chotamkz/career-track-backend/internal/util"
+)
+
+// LogSender writes messages to the log instead of sending them, for local
+// development.
+type LogSender struct {
+	logger *util.Logger
+}
+
+func NewLogSender(logger *util.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Infof("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/util"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New builds the sender selected by driver: "log" or "smtp".
+func New(driver string, smtpCfg SMTPConfig, logger *util.Logger) (Sender, error) {
+	switch driver {
+	case "", "log":
+		return NewLogSender(logger), nil
+	case "smtp":
+		return NewSMTPSender(smtpCfg)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", driver)
+	}
+}
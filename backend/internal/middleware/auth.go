@@ -1,47 +1,215 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func OptionalAuth(jwtSecret string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.Next()
-			return
+const (
+	principalKey = "principal"
+	authErrorKey = "authError"
+)
+
+var (
+	errMissingHeader = errors.New("Authorization header missing")
+	errInvalidHeader = errors.New("Invalid authorization header")
+	errInvalidToken  = errors.New("Invalid token")
+	errInvalidClaims = errors.New("Invalid token claims")
+)
+
+// Principal is the authenticated caller, parsed once per request from the
+// access token.
+type Principal struct {
+	UserID      uint
+	Email       string
+	UserType    model.UserType
+	AccessLevel model.AdminAccessLevel
+	SessionID   string
+}
+
+func (p *Principal) HasRole(roles ...model.UserType) bool {
+	for _, r := range roles {
+		if p.UserType == r {
+			return true
 		}
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.Next()
+	}
+	return false
+}
+
+// HasAccessLevel reports whether an admin principal holds one of the levels.
+// SUPER_ADMIN satisfies every level.
+func (p *Principal) HasAccessLevel(levels ...model.AdminAccessLevel) bool {
+	if p.UserType != model.UserTypeAdmin {
+		return false
+	}
+	if p.AccessLevel == model.AccessLevelSuperAdmin {
+		return true
+	}
+	for _, l := range levels {
+		if p.AccessLevel == l {
+			return true
+		}
+	}
+	return false
+}
+
+type Authenticator struct {
+	jwtSecret []byte
+}
+
+func NewAuthenticator(jwtSecret string) *Authenticator {
+	return &Authenticator{jwtSecret: []byte(jwtSecret)}
+}
+
+// Authenticate parses the bearer token, if any, and stores the principal on
+// the context. It never rejects a request: anonymous and invalid callers are
+// passed through and Require decides what to do with them.
+func (a *Authenticator) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.resolve(c)
+		c.Next()
+	}
+}
+
+// Require lets the request through only for the given roles. With no roles
+// any authenticated user is accepted.
+func (a *Authenticator) Require(roles ...model.UserType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := a.requirePrincipal(c)
+		if !ok {
 			return
 		}
-		tokenStr := parts[1]
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(jwtSecret), nil
-		})
-		if err != nil || !token.Valid {
-			c.Next()
+		if len(roles) > 0 && !p.HasRole(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": forbiddenMessage(roles)})
 			return
 		}
-		claims, ok := token.Claims.(jwt.MapClaims)
+		c.Next()
+	}
+}
+
+// RequireAdmin lets through admins holding one of the given access levels.
+// With no levels any admin is accepted.
+func (a *Authenticator) RequireAdmin(levels ...model.AdminAccessLevel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := a.requirePrincipal(c)
 		if !ok {
-			c.Next()
 			return
 		}
-		if uidFloat, ok := claims["user_id"].(float64); ok {
-			c.Set("user", uint(uidFloat))
+		if p.UserType != model.UserTypeAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Only administrators can perform this action"})
+			return
 		}
-		if ut, ok := claims["userType"].(string); ok {
-			c.Set("userType", ut)
+		if len(levels) > 0 && !p.HasAccessLevel(levels...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient access level"})
+			return
 		}
 		c.Next()
 	}
 }
+
+func PrincipalFromContext(c *gin.Context) (*Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := v.(*Principal)
+	return p, ok
+}
+
+func (a *Authenticator) requirePrincipal(c *gin.Context) (*Principal, bool) {
+	p, err := a.resolve(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return p, true
+}
+
+// resolve returns the principal for the request, parsing the token only if
+// no earlier middleware has done so.
+func (a *Authenticator) resolve(c *gin.Context) (*Principal, error) {
+	if p, ok := PrincipalFromContext(c); ok {
+		return p, nil
+	}
+	if v, ok := c.Get(authErrorKey); ok {
+		return nil, v.(error)
+	}
+
+	p, err := a.parse(c.GetHeader("Authorization"))
+	if err != nil {
+		c.Set(authErrorKey, err)
+		return nil, err
+	}
+	c.Set(principalKey, p)
+	// Legacy keys read by the handlers.
+	c.Set("user", p.UserID)
+	c.Set("userType", string(p.UserType))
+	return p, nil
+}
+
+func (a *Authenticator) parse(authHeader string) (*Principal, error) {
+	if authHeader == "" {
+		return nil, errMissingHeader
+	}
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errInvalidHeader
+	}
+	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return a.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+	userType, ok := claims["userType"].(string)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+
+	p := &Principal{
+		UserID:   uint(userIDFloat),
+		UserType: model.UserType(userType),
+	}
+	if email, ok := claims["email"].(string); ok {
+		p.Email = email
+	}
+	if level, ok := claims["accessLevel"].(string); ok {
+		p.AccessLevel = model.AdminAccessLevel(level)
+	}
+	if sid, ok := claims["sid"].(string); ok {
+		p.SessionID = sid
+	}
+	return p, nil
+}
+
+func forbiddenMessage(roles []model.UserType) string {
+	if len(roles) == 1 {
+		switch roles[0] {
+		case model.UserTypeStudent:
+			return "Only students can perform this action"
+		case model.UserTypeEmployer:
+			return "Only employers can perform this action"
+		case model.UserTypeAdmin:
+			return "Only administrators can perform this action"
+		}
+	}
+	return "You are not allowed to perform this action"
+}
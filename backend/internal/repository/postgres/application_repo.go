@@ -16,42 +16,130 @@ func NewApplicationRepo(db *sql.DB) repository.ApplicationRepository {
 	return &ApplicationRepo{DB: db}
 }
 
+const applicationColumns = `id, student_id, vacancy_id, cover_letter, submitted_date, status, updated_date, resume_id, vacancy_version, vacancy_changed_at`
+
+func scanApplication(row interface{ Scan(...interface{}) error }) (model.Application, error) {
+	var app model.Application
+	var resumeID sql.NullInt64
+	if err := row.Scan(&app.ID, &app.StudentID, &app.VacancyID, &app.CoverLetter, &app.SubmittedDate, &app.Status, &app.UpdatedDate, &resumeID,
+		&app.VacancyVersion, &app.VacancyChangedAt); err != nil {
+		return model.Application{}, err
+	}
+	if resumeID.Valid {
+		id := uint(resumeID.Int64)
+		app.ResumeID = &id
+	}
+	return app, nil
+}
+
 func (ar *ApplicationRepo) CreateApplication(app *model.Application) error {
+	tx, err := ar.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("CreateApplication begin: %v", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO applications (student_id, vacancy_id, cover_letter, submitted_date, status, updated_date)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
+		INSERT INTO applications (student_id, vacancy_id, cover_letter, submitted_date, status, updated_date, resume_id, vacancy_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT MAX(version) FROM vacancy_versions WHERE vacancy_id = $2))
+		RETURNING id, vacancy_version
 	`
-	err := ar.DB.QueryRow(query, app.StudentID, app.VacancyID, app.CoverLetter, app.SubmittedDate, app.Status, app.UpdatedDate).Scan(&app.ID)
+	err = tx.QueryRow(query, app.StudentID, app.VacancyID, app.CoverLetter, app.SubmittedDate, app.Status, app.UpdatedDate, app.ResumeID).Scan(&app.ID, &app.VacancyVersion)
 	if err != nil {
 		return fmt.Errorf("CreateApplication: %v", err)
 	}
-	return nil
+
+	_, err = tx.Exec(insertStatusHistoryQuery, app.ID, nil, app.Status, app.StudentID, model.UserTypeStudent, "", app.SubmittedDate)
+	if err != nil {
+		return fmt.Errorf("CreateApplication history: %v", err)
+	}
+	return tx.Commit()
 }
 
-func (ar *ApplicationRepo) UpdateApplicationStatus(appID uint, newStatus model.ApplicationStatus) error {
-	query := `
-		UPDATE applications 
+const insertStatusHistoryQuery = `
+	INSERT INTO application_status_history (application_id, from_status, to_status, changed_by, changed_by_type, note, changed_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id
+`
+
+// ChangeStatus moves the application from change.FromStatus to
+// change.ToStatus and appends the change to its history in one transaction.
+func (ar *ApplicationRepo) ChangeStatus(change *model.ApplicationStatusChange) error {
+	tx, err := ar.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ChangeStatus begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = time.Now()
+	}
+	res, err := tx.Exec(`
+		UPDATE applications
 		SET status = $1, updated_date = $2
-		WHERE id = $3
-	`
-	res, err := ar.DB.Exec(query, newStatus, time.Now(), appID)
+		WHERE id = $3 AND status = $4
+	`, change.ToStatus, change.ChangedAt, change.ApplicationID, change.FromStatus)
 	if err != nil {
-		return fmt.Errorf("UpdateApplicationStatus: %v", err)
+		return fmt.Errorf("ChangeStatus: %w", err)
 	}
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("UpdateApplicationStatus (rows): %v", err)
+		return fmt.Errorf("ChangeStatus (rows): %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("UpdateApplicationStatus: no application found with id %d", appID)
+		return repository.ErrStatusChanged
+	}
+
+	err = tx.QueryRow(insertStatusHistoryQuery, change.ApplicationID, change.FromStatus, change.ToStatus,
+		change.ChangedBy, change.ChangedByType, change.Note, change.ChangedAt).Scan(&change.ID)
+	if err != nil {
+		return fmt.Errorf("ChangeStatus history: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (ar *ApplicationRepo) GetStatusHistory(appID uint) ([]model.ApplicationStatusChange, error) {
+	const query = `
+		SELECT id, application_id, from_status, to_status, changed_by, changed_by_type, note, changed_at
+		FROM application_status_history
+		WHERE application_id = $1
+		ORDER BY changed_at, id
+	`
+	rows, err := ar.DB.Query(query, appID)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatusHistory: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	history := make([]model.ApplicationStatusChange, 0)
+	for rows.Next() {
+		var (
+			ch        model.ApplicationStatusChange
+			from      sql.NullString
+			changedBy sql.NullInt64
+		)
+		if err := rows.Scan(&ch.ID, &ch.ApplicationID, &from, &ch.ToStatus, &changedBy, &ch.ChangedByType, &ch.Note, &ch.ChangedAt); err != nil {
+			return nil, fmt.Errorf("GetStatusHistory scan: %w", err)
+		}
+		if from.Valid {
+			status := model.ApplicationStatus(from.String)
+			ch.FromStatus = &status
+		}
+		if changedBy.Valid {
+			id := uint(changedBy.Int64)
+			ch.ChangedBy = &id
+		}
+		history = append(history, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetStatusHistory rows: %w", err)
+	}
+	return history, nil
 }
 
 func (ar *ApplicationRepo) GetApplicationsByStudentID(studentID uint) ([]model.Application, error) {
 	query := `
-		SELECT id, student_id, vacancy_id, cover_letter, submitted_date, status, updated_date
+		SELECT ` + applicationColumns + `
 		FROM applications
 		WHERE student_id = $1
 		ORDER BY submitted_date DESC
@@ -64,8 +152,8 @@ func (ar *ApplicationRepo) GetApplicationsByStudentID(studentID uint) ([]model.A
 
 	var apps []model.Application
 	for rows.Next() {
-		var app model.Application
-		if err := rows.Scan(&app.ID, &app.StudentID, &app.VacancyID, &app.CoverLetter, &app.SubmittedDate, &app.Status, &app.UpdatedDate); err != nil {
+		app, err := scanApplication(rows)
+		if err != nil {
 			return nil, fmt.Errorf("GetApplicationsByStudentID scan: %v", err)
 		}
 		apps = append(apps, app)
@@ -78,28 +166,26 @@ func (ar *ApplicationRepo) GetApplicationsByStudentID(studentID uint) ([]model.A
 
 func (ar *ApplicationRepo) GetApplicationByID(appID uint) (model.Application, error) {
 	query := `
-		SELECT id, student_id, vacancy_id, cover_letter, submitted_date, status, updated_date
+		SELECT ` + applicationColumns + `
 		FROM applications
 		WHERE id = $1
 	`
-	var app model.Application
-	if err := ar.DB.QueryRow(query, appID).Scan(&app.ID, &app.StudentID, &app.VacancyID, &app.CoverLetter, &app.SubmittedDate, &app.Status, &app.UpdatedDate); err != nil {
-		return model.Application{}, fmt.Errorf("GetApplicationByID: %v", err)
+	app, err := scanApplication(ar.DB.QueryRow(query, appID))
+	if err != nil {
+		return model.Application{}, fmt.Errorf("GetApplicationByID: %w", err)
 	}
 	return app, nil
 }
 
 func (ar *ApplicationRepo) GetByStudentAndVacancy(studentID, vacancyID uint) (model.Application, error) {
 	const query = `
-        SELECT id, student_id, vacancy_id, cover_letter, submitted_date, status, updated_date
+        SELECT ` + applicationColumns + `
         FROM applications
         WHERE student_id = $1 AND vacancy_id = $2
         ORDER BY submitted_date DESC
         LIMIT 1
     `
-	var app model.Application
-	err := ar.DB.QueryRow(query, studentID, vacancyID).
-		Scan(&app.ID, &app.StudentID, &app.VacancyID, &app.CoverLetter, &app.SubmittedDate, &app.Status, &app.UpdatedDate)
+	app, err := scanApplication(ar.DB.QueryRow(query, studentID, vacancyID))
 	if err != nil {
 		return model.Application{}, err
 	}
@@ -108,7 +194,7 @@ func (ar *ApplicationRepo) GetByStudentAndVacancy(studentID, vacancyID uint) (mo
 
 func (ar *ApplicationRepo) GetApplicationsByVacancyID(vacancyID uint) ([]model.Application, error) {
 	const query = `
-      SELECT id, student_id, vacancy_id, cover_letter, submitted_date, status, updated_date
+      SELECT ` + applicationColumns + `
       FROM applications
       WHERE vacancy_id = $1
       ORDER BY submitted_date DESC
@@ -121,8 +207,8 @@ func (ar *ApplicationRepo) GetApplicationsByVacancyID(vacancyID uint) ([]model.A
 
 	var apps []model.Application
 	for rows.Next() {
-		var a model.Application
-		if err := rows.Scan(&a.ID, &a.StudentID, &a.VacancyID, &a.CoverLetter, &a.SubmittedDate, &a.Status, &a.UpdatedDate); err != nil {
+		a, err := scanApplication(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan applications: %v", err)
 		}
 		apps = append(apps, a)
@@ -133,15 +219,15 @@ func (ar *ApplicationRepo) GetApplicationsByVacancyID(vacancyID uint) ([]model.A
 func (ar *ApplicationRepo) GetApplicationsWithVacanciesAndEmployers(studentID uint) ([]model.ApplicationForStudent, error) {
 	query := `
         SELECT 
-            a.id, a.vacancy_id, a.cover_letter, a.submitted_date, a.status, a.updated_date,
+            a.id, a.vacancy_id, a.cover_letter, a.submitted_date, a.status, a.updated_date, a.vacancy_changed_at,
             v.title AS vacancy_title,
-            e.company_name
+            c.name
         FROM 
             applications a
         JOIN 
             vacancies v ON a.vacancy_id = v.id
         JOIN 
-            employer_profiles e ON v.employer_id = e.user_id
+            companies c ON c.id = v.company_id
         WHERE 
             a.student_id = $1
         ORDER BY 
@@ -159,7 +245,7 @@ func (ar *ApplicationRepo) GetApplicationsWithVacanciesAndEmployers(studentID ui
 		var app model.ApplicationForStudent
 		if err := rows.Scan(
 			&app.ID, &app.VacancyID, &app.CoverLetter, &app.SubmittedDate,
-			&app.Status, &app.UpdatedDate, &app.VacancyTitle, &app.CompanyName,
+			&app.Status, &app.UpdatedDate, &app.VacancyChangedAt, &app.VacancyTitle, &app.CompanyName,
 		); err != nil {
 			return nil, fmt.Errorf("GetApplicationsWithVacanciesAndEmployers scan: %v", err)
 		}
@@ -172,3 +258,105 @@ func (ar *ApplicationRepo) GetApplicationsWithVacanciesAndEmployers(studentID ui
 
 	return results, nil
 }
+
+func (ar *ApplicationRepo) GetApplicationStats(topVacancies int) (model.ApplicationStats, error) {
+	stats := model.ApplicationStats{ByStatus: make(map[model.ApplicationStatus]int)}
+
+	const totalsQuery = `
+        SELECT
+            COUNT(*),
+            COUNT(*) FILTER (WHERE submitted_date >= NOW() - INTERVAL '7 days'),
+            COUNT(*) FILTER (WHERE submitted_date >= NOW() - INTERVAL '30 days')
+        FROM applications
+    `
+	if err := ar.DB.QueryRow(totalsQuery).Scan(&stats.Total, &stats.LastWeek, &stats.LastMonth); err != nil {
+		return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats totals: %w", err)
+	}
+
+	rows, err := ar.DB.Query(`SELECT status, COUNT(*) FROM applications GROUP BY status`)
+	if err != nil {
+		return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats by status: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status model.ApplicationStatus
+		var cnt int
+		if err := rows.Scan(&status, &cnt); err != nil {
+			return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats by status scan: %w", err)
+		}
+		stats.ByStatus[status] = cnt
+	}
+	if err := rows.Err(); err != nil {
+		return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats by status rows: %w", err)
+	}
+
+	const topQuery = `
+        SELECT v.id, v.title, c.name, COUNT(a.id) AS cnt
+        FROM applications a
+        JOIN vacancies v ON v.id = a.vacancy_id
+        JOIN companies c ON c.id = v.company_id
+        WHERE a.status <> 'WITHDRAWN'
+        GROUP BY v.id, v.title, c.name
+        ORDER BY cnt DESC, v.id
+        LIMIT $1
+    `
+	topRows, err := ar.DB.Query(topQuery, topVacancies)
+	if err != nil {
+		return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats top: %w", err)
+	}
+	defer topRows.Close()
+	for topRows.Next() {
+		var vc model.VacancyApplicationCount
+		if err := topRows.Scan(&vc.VacancyID, &vc.VacancyTitle, &vc.CompanyName, &vc.Count); err != nil {
+			return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats top scan: %w", err)
+		}
+		stats.TopVacancies = append(stats.TopVacancies, vc)
+	}
+	if err := topRows.Err(); err != nil {
+		return model.ApplicationStats{}, fmt.Errorf("GetApplicationStats top rows: %w", err)
+	}
+	return stats, nil
+}
+
+// FlagVacancyChanged marks the applications still in play for the vacancy as
+// having seen the offer change.
+func (ar *ApplicationRepo) FlagVacancyChanged(vacancyID uint, at time.Time) (int64, error) {
+	const q = `
+        UPDATE applications
+        SET vacancy_changed_at = $2
+        WHERE vacancy_id = $1 AND status NOT IN ('ACCEPTED', 'REJECTED', 'WITHDRAWN')
+    `
+	res, err := ar.DB.Exec(q, vacancyID, at)
+	if err != nil {
+		return 0, fmt.Errorf("FlagVacancyChanged: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetOpenApplicantIDs returns the students whose applications to the vacancy
+// are still in play.
+func (ar *ApplicationRepo) GetOpenApplicantIDs(vacancyID uint) ([]uint, error) {
+	const q = `
+        SELECT DISTINCT student_id
+        FROM applications
+        WHERE vacancy_id = $1 AND status NOT IN ('ACCEPTED', 'REJECTED', 'WITHDRAWN')
+    `
+	rows, err := ar.DB.Query(q, vacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("GetOpenApplicantIDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("GetOpenApplicantIDs scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetOpenApplicantIDs rows: %w", err)
+	}
+	return ids, nil
+}
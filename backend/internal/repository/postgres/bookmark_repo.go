@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"time"
+)
+
+type BookmarkRepo struct {
+	DB *sql.DB
+}
+
+func NewBookmarkRepo(db *sql.DB) repository.BookmarkRepository {
+	return &BookmarkRepo{DB: db}
+}
+
+func (r *BookmarkRepo) AddVacancyBookmark(studentID, vacancyID uint) error {
+	const q = `
+        INSERT INTO vacancy_bookmarks (student_id, vacancy_id, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (student_id, vacancy_id) DO NOTHING
+    `
+	if _, err := r.DB.Exec(q, studentID, vacancyID); err != nil {
+		return fmt.Errorf("AddVacancyBookmark: %w", err)
+	}
+	return nil
+}
+
+func (r *BookmarkRepo) RemoveVacancyBookmark(studentID, vacancyID uint) error {
+	if _, err := r.DB.Exec(`DELETE FROM vacancy_bookmarks WHERE student_id = $1 AND vacancy_id = $2`, studentID, vacancyID); err != nil {
+		return fmt.Errorf("RemoveVacancyBookmark: %w", err)
+	}
+	return nil
+}
+
+func (r *BookmarkRepo) AddHackathonBookmark(studentID, hackathonID uint) error {
+	const q = `
+        INSERT INTO hackathon_bookmarks (student_id, hackathon_id, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (student_id, hackathon_id) DO NOTHING
+    `
+	if _, err := r.DB.Exec(q, studentID, hackathonID); err != nil {
+		return fmt.Errorf("AddHackathonBookmark: %w", err)
+	}
+	return nil
+}
+
+func (r *BookmarkRepo) RemoveHackathonBookmark(studentID, hackathonID uint) error {
+	if _, err := r.DB.Exec(`DELETE FROM hackathon_bookmarks WHERE student_id = $1 AND hackathon_id = $2`, studentID, hackathonID); err != nil {
+		return fmt.Errorf("RemoveHackathonBookmark: %w", err)
+	}
+	return nil
+}
+
+// GetBookmarkedVacancies lists bookmarked vacancies that still exist, closed
+// ones included so the student can see what happened to them.
+func (r *BookmarkRepo) GetBookmarkedVacancies(studentID uint) ([]model.Vacancy, error) {
+	const q = `
+        SELECT v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
+               v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url,
+               v.work_schedule, v.experience, v.status, v.expires_at,
+               EXISTS (
+                   SELECT 1 FROM applications a
+                   WHERE a.vacancy_id = v.id AND a.student_id = $1 AND a.status <> 'WITHDRAWN'
+               ) AS applied
+        FROM vacancy_bookmarks b
+        JOIN vacancies v ON v.id = b.vacancy_id
+        WHERE b.student_id = $1 AND v.deleted_at IS NULL
+        ORDER BY b.created_at DESC, v.id DESC
+    `
+	rows, err := r.DB.Query(q, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBookmarkedVacancies: %w", err)
+	}
+	defer rows.Close()
+
+	vacancies := []model.Vacancy{}
+	for rows.Next() {
+		v := model.Vacancy{Bookmarked: true}
+		if err := rows.Scan(
+			&v.ID, &v.Title, &v.Requirements, &v.Location, &v.PostedDate, &v.EmployerID, &v.CreatedAt,
+			&v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL,
+			&v.WorkSchedule, &v.Experience, &v.Status, &v.ExpiresAt,
+			&v.Applied,
+		); err != nil {
+			return nil, fmt.Errorf("GetBookmarkedVacancies scan: %w", err)
+		}
+		vacancies = append(vacancies, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBookmarkedVacancies rows: %w", err)
+	}
+	return vacancies, nil
+}
+
+func (r *BookmarkRepo) GetBookmarkedHackathons(studentID uint) ([]model.Hackathon, error) {
+	const q = `
+        SELECT h.id, h.name, h.organizer, h.start_date, h.end_date, h.format, h.location, h.theme, h.prizes,
+               h.required_skills, h.website, h.created_at, h.updated_at
+        FROM hackathon_bookmarks b
+        JOIN hackathons h ON h.id = b.hackathon_id
+        WHERE b.student_id = $1
+        ORDER BY b.created_at DESC, h.id DESC
+    `
+	rows, err := r.DB.Query(q, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBookmarkedHackathons: %w", err)
+	}
+	defer rows.Close()
+
+	hackathons := []model.Hackathon{}
+	for rows.Next() {
+		var h model.Hackathon
+		if err := rows.Scan(&h.ID, &h.Name, &h.Organizer, &h.StartDate, &h.EndDate, &h.Format, &h.Location, &h.Theme, &h.Prizes,
+			&h.RequiredSkills, &h.Website, &h.CreatedAt, &h.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("GetBookmarkedHackathons scan: %w", err)
+		}
+		hackathons = append(hackathons, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBookmarkedHackathons rows: %w", err)
+	}
+	return hackathons, nil
+}
+
+func (r *BookmarkRepo) queryAlerts(op, q string, args ...interface{}) ([]model.VacancyBookmarkAlert, error) {
+	rows, err := r.DB.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	alerts := []model.VacancyBookmarkAlert{}
+	for rows.Next() {
+		var a model.VacancyBookmarkAlert
+		if err := rows.Scan(&a.StudentID, &a.VacancyID, &a.Title, &a.Status, &a.ExpiresAt, &a.PostedDate); err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return alerts, nil
+}
+
+func (r *BookmarkRepo) GetExpiringVacancyBookmarks(now, before time.Time) ([]model.VacancyBookmarkAlert, error) {
+	const q = `
+        SELECT b.student_id, v.id, v.title, v.status, v.expires_at, v.posted_date
+        FROM vacancy_bookmarks b
+        JOIN vacancies v ON v.id = b.vacancy_id
+        WHERE v.status = 'PUBLISHED' AND v.deleted_at IS NULL
+          AND v.expires_at > $1 AND v.expires_at <= $2
+          AND b.expiry_notified_for IS DISTINCT FROM v.expires_at
+        ORDER BY v.id, b.student_id
+    `
+	return r.queryAlerts("GetExpiringVacancyBookmarks", q, now, before)
+}
+
+func (r *BookmarkRepo) MarkExpiryNotified(studentID, vacancyID uint, expiresAt time.Time) error {
+	const q = `UPDATE vacancy_bookmarks SET expiry_notified_for = $1 WHERE student_id = $2 AND vacancy_id = $3`
+	if _, err := r.DB.Exec(q, expiresAt, studentID, vacancyID); err != nil {
+		return fmt.Errorf("MarkExpiryNotified: %w", err)
+	}
+	return nil
+}
+
+func (r *BookmarkRepo) GetClosedVacancyBookmarks() ([]model.VacancyBookmarkAlert, error) {
+	const q = `
+        SELECT b.student_id, v.id, v.title, v.status, v.expires_at, v.posted_date
+        FROM vacancy_bookmarks b
+        JOIN vacancies v ON v.id = b.vacancy_id
+        WHERE (v.status IN ('CLOSED', 'ARCHIVED') OR v.deleted_at IS NOT NULL)
+          AND (b.closed_notified_at IS NULL OR b.closed_notified_at < v.posted_date)
+        ORDER BY v.id, b.student_id
+    `
+	return r.queryAlerts("GetClosedVacancyBookmarks", q)
+}
+
+func (r *BookmarkRepo) MarkClosedNotified(studentID, vacancyID uint, at time.Time) error {
+	const q = `UPDATE vacancy_bookmarks SET closed_notified_at = $1 WHERE student_id = $2 AND vacancy_id = $3`
+	if _, err := r.DB.Exec(q, at, studentID, vacancyID); err != nil {
+		return fmt.Errorf("MarkClosedNotified: %w", err)
+	}
+	return nil
+}
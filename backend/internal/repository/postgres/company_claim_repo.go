@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"time"
+)
+
+const companyClaimColumns = `
+    cl.id, cl.company_id, c.name, c.website, cl.employer_id, cl.email, cl.status, COALESCE(cl.token_hash, ''), cl.token_expires_at,
+    cl.verified_at, cl.reviewed_by, cl.review_note, cl.reviewed_at, cl.created_at, cl.updated_at`
+
+const companyClaimFrom = ` FROM company_claims cl JOIN companies c ON c.id = cl.company_id`
+
+type CompanyClaimRepo struct {
+	DB *sql.DB
+}
+
+func NewCompanyClaimRepo(db *sql.DB) repository.CompanyClaimRepository {
+	return &CompanyClaimRepo{DB: db}
+}
+
+func scanCompanyClaim(row interface{ Scan(...interface{}) error }) (model.CompanyClaim, error) {
+	var cl model.CompanyClaim
+	err := row.Scan(&cl.ID, &cl.CompanyID, &cl.CompanyName, &cl.CompanyWebsite, &cl.EmployerID, &cl.Email, &cl.Status, &cl.TokenHash,
+		&cl.TokenExpiresAt, &cl.VerifiedAt, &cl.ReviewedBy, &cl.ReviewNote, &cl.ReviewedAt, &cl.CreatedAt, &cl.UpdatedAt)
+	return cl, err
+}
+
+func (r *CompanyClaimRepo) CreateCompanyClaim(cl *model.CompanyClaim) error {
+	const q = `
+        INSERT INTO company_claims (company_id, employer_id, email, token_hash, token_expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (company_id, employer_id) WHERE status IN ('PENDING', 'VERIFIED')
+        DO UPDATE SET email = EXCLUDED.email, token_hash = EXCLUDED.token_hash,
+                      token_expires_at = EXCLUDED.token_expires_at, updated_at = NOW()
+        WHERE company_claims.status = 'PENDING'
+        RETURNING id
+    `
+	var id uint
+	if err := r.DB.QueryRow(q, cl.CompanyID, cl.EmployerID, cl.Email, cl.TokenHash, cl.TokenExpiresAt).Scan(&id); err != nil {
+		return fmt.Errorf("CreateCompanyClaim: %w", err)
+	}
+	created, err := r.GetCompanyClaim(id)
+	if err != nil {
+		return err
+	}
+	*cl = created
+	return nil
+}
+
+func (r *CompanyClaimRepo) GetCompanyClaim(id uint) (model.CompanyClaim, error) {
+	cl, err := scanCompanyClaim(r.DB.QueryRow(`SELECT `+companyClaimColumns+companyClaimFrom+` WHERE cl.id = $1`, id))
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("GetCompanyClaim: %w", err)
+	}
+	return cl, nil
+}
+
+func (r *CompanyClaimRepo) GetCompanyClaimByToken(tokenHash string) (model.CompanyClaim, error) {
+	cl, err := scanCompanyClaim(r.DB.QueryRow(`SELECT `+companyClaimColumns+companyClaimFrom+` WHERE cl.token_hash = $1`, tokenHash))
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("GetCompanyClaimByToken: %w", err)
+	}
+	return cl, nil
+}
+
+// GetCompanyClaims lists claims oldest first, all of them when status is
+// empty.
+func (r *CompanyClaimRepo) GetCompanyClaims(status model.CompanyClaimStatus, limit int) ([]model.CompanyClaim, error) {
+	q := `SELECT ` + companyClaimColumns + companyClaimFrom + `
+        WHERE cl.status = $1 OR $1 = ''
+        ORDER BY cl.created_at, cl.id
+        LIMIT $2`
+	return r.queryCompanyClaims("GetCompanyClaims", q, string(status), limit)
+}
+
+func (r *CompanyClaimRepo) GetEmployerCompanyClaims(employerID uint) ([]model.CompanyClaim, error) {
+	q := `SELECT ` + companyClaimColumns + companyClaimFrom + `
+        WHERE cl.employer_id = $1
+        ORDER BY cl.created_at DESC, cl.id DESC`
+	return r.queryCompanyClaims("GetEmployerCompanyClaims", q, employerID)
+}
+
+func (r *CompanyClaimRepo) queryCompanyClaims(op, q string, args ...interface{}) ([]model.CompanyClaim, error) {
+	rows, err := r.DB.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	claims := []model.CompanyClaim{}
+	for rows.Next() {
+		cl, err := scanCompanyClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		claims = append(claims, cl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return claims, nil
+}
+
+func (r *CompanyClaimRepo) VerifyCompanyClaim(id uint, at time.Time) error {
+	const q = `
+        UPDATE company_claims
+        SET status = 'VERIFIED', verified_at = $2, token_hash = NULL, token_expires_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND status = 'PENDING'
+    `
+	res, err := r.DB.Exec(q, id, at)
+	if err != nil {
+		return fmt.Errorf("VerifyCompanyClaim: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("VerifyCompanyClaim rows: %w", err)
+	} else if n == 0 {
+		return repository.ErrCompanyClaimStatusChanged
+	}
+	return nil
+}
+
+func (r *CompanyClaimRepo) ApproveCompanyClaim(id, reviewerID uint, note string, at time.Time) (model.CompanyClaim, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	var companyID, employerID uint
+	err = tx.QueryRow(`
+        UPDATE company_claims
+        SET status = 'APPROVED', reviewed_by = $2, review_note = $3, reviewed_at = $4, updated_at = NOW()
+        WHERE id = $1 AND status = 'VERIFIED'
+        RETURNING company_id, employer_id`, id, reviewerID, note, at).Scan(&companyID, &employerID)
+	if err == sql.ErrNoRows {
+		return model.CompanyClaim{}, repository.ErrCompanyClaimStatusChanged
+	}
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim: %w", err)
+	}
+
+	res, err := tx.Exec(`UPDATE companies SET owner_id = $2, updated_at = NOW() WHERE id = $1 AND owner_id IS NULL`,
+		companyID, employerID)
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim company: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim company rows: %w", err)
+	} else if n == 0 {
+		return model.CompanyClaim{}, repository.ErrCompanyAlreadyOwned
+	}
+
+	if _, err := tx.Exec(`UPDATE vacancies SET employer_id = $2 WHERE company_id = $1`, companyID, employerID); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim vacancies: %w", err)
+	}
+	if _, err := tx.Exec(`
+        UPDATE company_claims
+        SET status = 'DENIED', reviewed_by = $3, review_note = 'Another claim to this company was approved',
+            reviewed_at = $4, token_hash = NULL, token_expires_at = NULL, updated_at = NOW()
+        WHERE company_id = $1 AND id <> $2 AND status IN ('PENDING', 'VERIFIED')`, companyID, id, reviewerID, at); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim other claims: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("ApproveCompanyClaim commit: %w", err)
+	}
+	return r.GetCompanyClaim(id)
+}
+
+func (r *CompanyClaimRepo) DenyCompanyClaim(id, reviewerID uint, note string, at time.Time) (model.CompanyClaim, error) {
+	const q = `
+        UPDATE company_claims
+        SET status = 'DENIED', reviewed_by = $2, review_note = $3, reviewed_at = $4,
+            token_hash = NULL, token_expires_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND status IN ('PENDING', 'VERIFIED')
+    `
+	res, err := r.DB.Exec(q, id, reviewerID, note, at)
+	if err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("DenyCompanyClaim: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("DenyCompanyClaim rows: %w", err)
+	} else if n == 0 {
+		return model.CompanyClaim{}, repository.ErrCompanyClaimStatusChanged
+	}
+	return r.GetCompanyClaim(id)
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/lib/pq"
+)
+
+const companyColumns = `id, COALESCE(source, ''), COALESCE(external_id, ''), name, description, website, owner_id, created_at, updated_at`
+
+type CompanyRepo struct {
+	DB *sql.DB
+}
+
+func NewCompanyRepo(db *sql.DB) repository.CompanyRepository {
+	return &CompanyRepo{DB: db}
+}
+
+func scanCompany(row interface{ Scan(...interface{}) error }) (model.Company, error) {
+	var c model.Company
+	err := row.Scan(&c.ID, &c.Source, &c.ExternalID, &c.Name, &c.Description, &c.Website, &c.OwnerID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// EnsureImportedCompany returns the source's company, creating it the first
+// time it is seen. The name follows the source until an employer takes the
+// company over; the website whenever the source reports one.
+func (r *CompanyRepo) EnsureImportedCompany(source, externalID, name, website string) (model.Company, error) {
+	const q = `
+        WITH upsert AS (
+            INSERT INTO companies (source, external_id, name, website)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (source, external_id) WHERE source IS NOT NULL
+            DO UPDATE SET
+                name = CASE WHEN companies.owner_id IS NULL THEN EXCLUDED.name ELSE companies.name END,
+                website = COALESCE(NULLIF(EXCLUDED.website, ''), companies.website),
+                updated_at = NOW()
+            WHERE (companies.owner_id IS NULL AND companies.name IS DISTINCT FROM EXCLUDED.name)
+               OR (EXCLUDED.website <> '' AND companies.website <> EXCLUDED.website)
+            RETURNING ` + companyColumns + `
+        )
+        SELECT * FROM upsert
+        UNION ALL
+        SELECT ` + companyColumns + ` FROM companies WHERE source = $1 AND external_id = $2
+        LIMIT 1
+    `
+	c, err := scanCompany(r.DB.QueryRow(q, source, externalID, name, website))
+	if err != nil {
+		return model.Company{}, fmt.Errorf("EnsureImportedCompany: %w", err)
+	}
+	return c, nil
+}
+
+// EnsureEmployerCompany returns the id of the employer's own company,
+// creating it from the employer profile if the employer has none yet.
+func (r *CompanyRepo) EnsureEmployerCompany(employerID uint) (uint, error) {
+	const selectQ = `SELECT id FROM companies WHERE owner_id = $1 AND source IS NULL`
+	var id uint
+	err := r.DB.QueryRow(selectQ, employerID).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("EnsureEmployerCompany: %w", err)
+	}
+
+	const insertQ = `
+        INSERT INTO companies (name, description, owner_id)
+        SELECT company_name, COALESCE(company_description, ''), user_id
+        FROM employer_profiles
+        WHERE user_id = $1
+        ON CONFLICT (owner_id) WHERE source IS NULL DO NOTHING
+    `
+	if _, err := r.DB.Exec(insertQ, employerID); err != nil {
+		return 0, fmt.Errorf("EnsureEmployerCompany insert: %w", err)
+	}
+	if err := r.DB.QueryRow(selectQ, employerID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("EnsureEmployerCompany: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateEmployerCompany copies the employer profile's company details to the
+// employer's own company, if it has one.
+func (r *CompanyRepo) UpdateEmployerCompany(employerID uint, name, description string) error {
+	const q = `
+        UPDATE companies
+        SET name = $2, description = $3, updated_at = NOW()
+        WHERE owner_id = $1 AND source IS NULL
+    `
+	if _, err := r.DB.Exec(q, employerID, name, description); err != nil {
+		return fmt.Errorf("UpdateEmployerCompany: %w", err)
+	}
+	return nil
+}
+
+// SetCompanyWebsite records the website of an imported company looked up
+// from its source.
+func (r *CompanyRepo) SetCompanyWebsite(id uint, website string) error {
+	const q = `UPDATE companies SET website = $2, updated_at = NOW() WHERE id = $1 AND source IS NOT NULL`
+	if _, err := r.DB.Exec(q, id, website); err != nil {
+		return fmt.Errorf("SetCompanyWebsite: %w", err)
+	}
+	return nil
+}
+
+func (r *CompanyRepo) GetCompany(id uint) (model.Company, error) {
+	const q = `SELECT ` + companyColumns + ` FROM companies WHERE id = $1`
+	c, err := scanCompany(r.DB.QueryRow(q, id))
+	if err != nil {
+		return model.Company{}, fmt.Errorf("GetCompany: %w", err)
+	}
+	return c, nil
+}
+
+func (r *CompanyRepo) GetCompaniesByIDs(ids []uint) ([]model.Company, error) {
+	companies := []model.Company{}
+	if len(ids) == 0 {
+		return companies, nil
+	}
+	intIDs := make([]int64, len(ids))
+	for i, id := range ids {
+		intIDs[i] = int64(id)
+	}
+	const q = `SELECT ` + companyColumns + ` FROM companies WHERE id = ANY($1)`
+	rows, err := r.DB.Query(q, pq.Array(intIDs))
+	if err != nil {
+		return nil, fmt.Errorf("GetCompaniesByIDs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetCompaniesByIDs scan: %w", err)
+		}
+		companies = append(companies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetCompaniesByIDs rows: %w", err)
+	}
+	return companies, nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+type CurrencyRateRepo struct {
+	DB *sql.DB
+}
+
+func NewCurrencyRateRepo(db *sql.DB) repository.CurrencyRateRepository {
+	return &CurrencyRateRepo{DB: db}
+}
+
+func (cr *CurrencyRateRepo) GetRates() ([]model.CurrencyRate, error) {
+	rows, err := cr.DB.Query(`SELECT code, rate_to_kzt, updated_at FROM currency_rates ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("GetRates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []model.CurrencyRate
+	for rows.Next() {
+		var r model.CurrencyRate
+		if err := rows.Scan(&r.Code, &r.RateToKZT, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("GetRates scan: %w", err)
+		}
+		rates = append(rates, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetRates rows: %w", err)
+	}
+	return rates, nil
+}
+
+func (cr *CurrencyRateRepo) UpsertRates(rates []model.CurrencyRate) error {
+	tx, err := cr.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("UpsertRates begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+        INSERT INTO currency_rates (code, rate_to_kzt, updated_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (code) DO UPDATE
+        SET rate_to_kzt = EXCLUDED.rate_to_kzt, updated_at = NOW()
+    `)
+	if err != nil {
+		return fmt.Errorf("UpsertRates prepare: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range rates {
+		if _, err := stmt.Exec(r.Code, r.RateToKZT); err != nil {
+			return fmt.Errorf("UpsertRates %s: %w", r.Code, err)
+		}
+	}
+	return tx.Commit()
+}
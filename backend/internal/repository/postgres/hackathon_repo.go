@@ -67,3 +67,36 @@ func (hr *HackathonRepo) GetHackathonByID(id uint) (model.Hackathon, error) {
 	}
 	return h, nil
 }
+
+func (hr *HackathonRepo) UpdateHackathon(h *model.Hackathon) error {
+	query := `
+		UPDATE hackathons SET
+			name = $1, organizer = $2, start_date = $3, end_date = $4, format = $5, location = $6,
+			theme = $7, prizes = $8, required_skills = $9, website = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $11
+		RETURNING created_at, updated_at
+	`
+	err := hr.DB.QueryRow(
+		query,
+		h.Name, h.Organizer, h.StartDate, h.EndDate, h.Format, h.Location, h.Theme, h.Prizes, h.RequiredSkills, h.Website, h.ID,
+	).Scan(&h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("UpdateHackathon: %w", err)
+	}
+	return nil
+}
+
+func (hr *HackathonRepo) DeleteHackathon(id uint) error {
+	res, err := hr.DB.Exec(`DELETE FROM hackathons WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteHackathon: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteHackathon rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
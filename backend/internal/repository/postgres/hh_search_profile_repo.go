@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/lib/pq"
+	"time"
+)
+
+const hhSearchProfileColumns = `id, name, areas, professional_roles, text, only_with_salary, period_days, enabled, synced_until, created_at, updated_at`
+
+type HHSearchProfileRepo struct {
+	DB *sql.DB
+}
+
+func NewHHSearchProfileRepo(db *sql.DB) repository.HHSearchProfileRepository {
+	return &HHSearchProfileRepo{DB: db}
+}
+
+func scanHHSearchProfile(row interface{ Scan(...interface{}) error }) (model.HHSearchProfile, error) {
+	var p model.HHSearchProfile
+	var areas, roles pq.StringArray
+	if err := row.Scan(&p.ID, &p.Name, &areas, &roles, &p.Text, &p.OnlyWithSalary, &p.PeriodDays,
+		&p.Enabled, &p.SyncedUntil, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return model.HHSearchProfile{}, err
+	}
+	p.Areas, p.ProfessionalRoles = []string(areas), []string(roles)
+	return p, nil
+}
+
+func (r *HHSearchProfileRepo) CreateHHSearchProfile(p *model.HHSearchProfile) error {
+	const q = `
+        INSERT INTO hh_search_profiles (name, areas, professional_roles, text, only_with_salary, period_days, enabled)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING ` + hhSearchProfileColumns
+	created, err := scanHHSearchProfile(r.DB.QueryRow(q, p.Name, pq.Array(p.Areas), pq.Array(p.ProfessionalRoles),
+		p.Text, p.OnlyWithSalary, p.PeriodDays, p.Enabled))
+	if err != nil {
+		return fmt.Errorf("CreateHHSearchProfile: %w", err)
+	}
+	*p = created
+	return nil
+}
+
+func (r *HHSearchProfileRepo) GetHHSearchProfiles(enabledOnly bool) ([]model.HHSearchProfile, error) {
+	const q = `SELECT ` + hhSearchProfileColumns + ` FROM hh_search_profiles WHERE enabled OR NOT $1 ORDER BY id`
+	rows, err := r.DB.Query(q, enabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("GetHHSearchProfiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []model.HHSearchProfile{}
+	for rows.Next() {
+		p, err := scanHHSearchProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetHHSearchProfiles scan: %w", err)
+		}
+		profiles = append(profiles, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetHHSearchProfiles rows: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *HHSearchProfileRepo) GetHHSearchProfile(id uint) (model.HHSearchProfile, error) {
+	p, err := scanHHSearchProfile(r.DB.QueryRow(`SELECT `+hhSearchProfileColumns+` FROM hh_search_profiles WHERE id = $1`, id))
+	if err != nil {
+		return model.HHSearchProfile{}, fmt.Errorf("GetHHSearchProfile: %w", err)
+	}
+	return p, nil
+}
+
+func (r *HHSearchProfileRepo) GetHHSearchProfileByName(name string) (model.HHSearchProfile, error) {
+	p, err := scanHHSearchProfile(r.DB.QueryRow(`SELECT `+hhSearchProfileColumns+` FROM hh_search_profiles WHERE name = $1`, name))
+	if err != nil {
+		return model.HHSearchProfile{}, fmt.Errorf("GetHHSearchProfileByName: %w", err)
+	}
+	return p, nil
+}
+
+// UpdateHHSearchProfile rewrites the profile. A changed search loses its
+// watermark, so its next sync covers the whole period again.
+func (r *HHSearchProfileRepo) UpdateHHSearchProfile(p *model.HHSearchProfile) error {
+	const q = `
+        UPDATE hh_search_profiles
+        SET name = $1, areas = $2, professional_roles = $3, text = $4, only_with_salary = $5,
+            period_days = $6, enabled = $7, updated_at = NOW(),
+            synced_until = CASE
+                WHEN (areas, professional_roles, text, only_with_salary, period_days)
+                    IS DISTINCT FROM ($2::text[], $3::text[], $4::text, $5::boolean, $6::int)
+                THEN NULL ELSE synced_until END
+        WHERE id = $8
+        RETURNING ` + hhSearchProfileColumns
+	updated, err := scanHHSearchProfile(r.DB.QueryRow(q, p.Name, pq.Array(p.Areas), pq.Array(p.ProfessionalRoles),
+		p.Text, p.OnlyWithSalary, p.PeriodDays, p.Enabled, p.ID))
+	if err != nil {
+		return fmt.Errorf("UpdateHHSearchProfile: %w", err)
+	}
+	*p = updated
+	return nil
+}
+
+func (r *HHSearchProfileRepo) DeleteHHSearchProfile(id uint) error {
+	res, err := r.DB.Exec(`DELETE FROM hh_search_profiles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteHHSearchProfile: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteHHSearchProfile: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("DeleteHHSearchProfile: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *HHSearchProfileRepo) UpsertHHSearchProfile(p *model.HHSearchProfile) error {
+	const q = `
+        INSERT INTO hh_search_profiles (name, areas, professional_roles, text, only_with_salary, period_days, enabled)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (name) DO UPDATE
+        SET areas = EXCLUDED.areas, professional_roles = EXCLUDED.professional_roles, text = EXCLUDED.text,
+            only_with_salary = EXCLUDED.only_with_salary, period_days = EXCLUDED.period_days,
+            enabled = EXCLUDED.enabled, updated_at = NOW(),
+            synced_until = CASE
+                WHEN (hh_search_profiles.areas, hh_search_profiles.professional_roles, hh_search_profiles.text,
+                      hh_search_profiles.only_with_salary, hh_search_profiles.period_days)
+                    IS DISTINCT FROM (EXCLUDED.areas, EXCLUDED.professional_roles, EXCLUDED.text,
+                      EXCLUDED.only_with_salary, EXCLUDED.period_days)
+                THEN NULL ELSE hh_search_profiles.synced_until END
+        RETURNING ` + hhSearchProfileColumns
+	upserted, err := scanHHSearchProfile(r.DB.QueryRow(q, p.Name, pq.Array(p.Areas), pq.Array(p.ProfessionalRoles),
+		p.Text, p.OnlyWithSalary, p.PeriodDays, p.Enabled))
+	if err != nil {
+		return fmt.Errorf("UpsertHHSearchProfile: %w", err)
+	}
+	*p = upserted
+	return nil
+}
+
+func (r *HHSearchProfileRepo) SetHHSearchProfileSyncedUntil(id uint, at time.Time) error {
+	if _, err := r.DB.Exec(`UPDATE hh_search_profiles SET synced_until = $1 WHERE id = $2`, at.UTC(), id); err != nil {
+		return fmt.Errorf("SetHHSearchProfileSyncedUntil: %w", err)
+	}
+	return nil
+}
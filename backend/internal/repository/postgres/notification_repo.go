@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/lib/pq"
+	"time"
+)
+
+const notificationColumns = `id, user_id, type, title, body, vacancy_id, saved_search_id, COALESCE(dedup_key, ''), read_at, emailed_at, created_at`
+
+type NotificationRepo struct {
+	DB *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) repository.NotificationRepository {
+	return &NotificationRepo{DB: db}
+}
+
+func scanNotification(row interface{ Scan(...interface{}) error }) (model.Notification, error) {
+	var n model.Notification
+	var vacancyID, savedSearchID sql.NullInt64
+	if err := row.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Body, &vacancyID, &savedSearchID,
+		&n.DedupKey, &n.ReadAt, &n.EmailedAt, &n.CreatedAt); err != nil {
+		return model.Notification{}, err
+	}
+	if vacancyID.Valid {
+		id := uint(vacancyID.Int64)
+		n.VacancyID = &id
+	}
+	if savedSearchID.Valid {
+		id := uint(savedSearchID.Int64)
+		n.SavedSearchID = &id
+	}
+	return n, nil
+}
+
+func (r *NotificationRepo) queryNotifications(op, q string, args ...interface{}) ([]model.Notification, error) {
+	rows, err := r.DB.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepo) CreateNotification(n *model.Notification) (bool, error) {
+	var dedupKey sql.NullString
+	if n.DedupKey != "" {
+		dedupKey = sql.NullString{String: n.DedupKey, Valid: true}
+	}
+	const q = `
+        INSERT INTO notifications (user_id, type, title, body, vacancy_id, saved_search_id, dedup_key, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+        ON CONFLICT (user_id, dedup_key) DO NOTHING
+        RETURNING id, created_at
+    `
+	err := r.DB.QueryRow(q, n.UserID, n.Type, n.Title, n.Body, n.VacancyID, n.SavedSearchID, dedupKey).Scan(&n.ID, &n.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("CreateNotification: %w", err)
+	}
+	return true, nil
+}
+
+func (r *NotificationRepo) GetNotifications(userID uint, unreadOnly bool, limit, offset int) ([]model.Notification, error) {
+	const q = `
+        SELECT ` + notificationColumns + `
+        FROM notifications
+        WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $3 OFFSET $4
+    `
+	return r.queryNotifications("GetNotifications", q, userID, unreadOnly, limit, offset)
+}
+
+func (r *NotificationRepo) CountUnreadNotifications(userID uint) (int, error) {
+	var n int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("CountUnreadNotifications: %w", err)
+	}
+	return n, nil
+}
+
+func (r *NotificationRepo) MarkNotificationRead(userID, id uint) error {
+	const q = `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+	res, err := r.DB.Exec(q, id, userID)
+	if err != nil {
+		return fmt.Errorf("MarkNotificationRead: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("MarkNotificationRead: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("MarkNotificationRead: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) MarkAllNotificationsRead(userID uint) (int64, error) {
+	res, err := r.DB.Exec(`UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("MarkAllNotificationsRead: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *NotificationRepo) GetUnemailedNotifications(savedSearchIDs []uint) ([]model.Notification, error) {
+	if len(savedSearchIDs) == 0 {
+		return []model.Notification{}, nil
+	}
+	const q = `
+        SELECT ` + notificationColumns + `
+        FROM notifications
+        WHERE saved_search_id = ANY($1) AND read_at IS NULL AND emailed_at IS NULL
+        ORDER BY user_id, created_at, id
+    `
+	return r.queryNotifications("GetUnemailedNotifications", q, pq.Array(savedSearchIDs))
+}
+
+func (r *NotificationRepo) MarkNotificationsEmailed(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.DB.Exec(`UPDATE notifications SET emailed_at = $1 WHERE id = ANY($2)`, at, pq.Array(ids)); err != nil {
+		return fmt.Errorf("MarkNotificationsEmailed: %w", err)
+	}
+	return nil
+}
@@ -96,3 +96,56 @@ func (pr *ProfileRepo) UpdateStudentProfile(p *model.StudentProfile) error {
 	)
 	return err
 }
+
+func (pr *ProfileRepo) GetAdminProfile(userID uint) (model.AdminProfile, error) {
+	const q = `
+      SELECT user_id, permissions, access_level, last_access
+      FROM admin_profiles
+      WHERE user_id = $1
+    `
+	var p model.AdminProfile
+	var permissions []byte
+	var lastAccess sql.NullTime
+	err := pr.DB.QueryRow(q, userID).Scan(&p.UserID, &permissions, &p.AccessLevel, &lastAccess)
+	if err != nil {
+		return model.AdminProfile{}, fmt.Errorf("GetAdminProfile: %w", err)
+	}
+	p.Permissions = permissions
+	if lastAccess.Valid {
+		p.LastAccess = &lastAccess.Time
+	}
+	return p, nil
+}
+
+func (pr *ProfileRepo) CreateAdminProfile(profile *model.AdminProfile) error {
+	permissions := profile.Permissions
+	if len(permissions) == 0 {
+		permissions = []byte("{}")
+	}
+	query := `INSERT INTO admin_profiles (user_id, permissions, access_level) VALUES ($1, $2, $3)`
+	_, err := pr.DB.Exec(query, profile.UserID, string(permissions), profile.AccessLevel)
+	if err != nil {
+		pr.Logger.Errorf("Error creating admin profile for user_id %d: %v", profile.UserID, err)
+		return err
+	}
+	profile.Permissions = permissions
+	return nil
+}
+
+// HasSuperAdmin reports whether any SUPER_ADMIN account exists.
+func (pr *ProfileRepo) HasSuperAdmin() (bool, error) {
+	var exists bool
+	err := pr.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM admin_profiles WHERE access_level = $1)`, model.AccessLevelSuperAdmin).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("HasSuperAdmin: %w", err)
+	}
+	return exists, nil
+}
+
+func (pr *ProfileRepo) TouchLastAccess(userID uint) error {
+	_, err := pr.DB.Exec(`UPDATE admin_profiles SET last_access = CURRENT_TIMESTAMP WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("TouchLastAccess: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+type RefreshTokenRepo struct {
+	DB *sql.DB
+}
+
+func NewRefreshTokenRepo(db *sql.DB) repository.RefreshTokenRepository {
+	return &RefreshTokenRepo{DB: db}
+}
+
+func (rr *RefreshTokenRepo) Create(t *model.RefreshToken) error {
+	const q = `
+        INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at, user_agent, ip_address)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, $5, $6)
+        RETURNING id, created_at
+    `
+	err := rr.DB.QueryRow(q, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt, t.UserAgent, t.IPAddress).
+		Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("CreateRefreshToken: %w", err)
+	}
+	return nil
+}
+
+func (rr *RefreshTokenRepo) GetByHash(tokenHash string) (model.RefreshToken, error) {
+	const q = `
+        SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at, replaced_by,
+               COALESCE(user_agent, ''), COALESCE(ip_address, '')
+        FROM refresh_tokens
+        WHERE token_hash = $1
+    `
+	var t model.RefreshToken
+	var revokedAt sql.NullTime
+	var replacedBy sql.NullInt64
+	err := rr.DB.QueryRow(q, tokenHash).Scan(
+		&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt,
+		&revokedAt, &replacedBy, &t.UserAgent, &t.IPAddress,
+	)
+	if err != nil {
+		return model.RefreshToken{}, fmt.Errorf("GetRefreshTokenByHash: %w", err)
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	if replacedBy.Valid {
+		id := uint(replacedBy.Int64)
+		t.ReplacedBy = &id
+	}
+	return t, nil
+}
+
+// Rotate stores next and revokes oldID in one transaction. If oldID was
+// already revoked (e.g. two concurrent refreshes with the same token) it
+// returns repository.ErrRefreshTokenAlreadyRotated and stores nothing.
+func (rr *RefreshTokenRepo) Rotate(oldID uint, next *model.RefreshToken) error {
+	tx, err := rr.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const insertQ = `
+        INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at, user_agent, ip_address)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, $5, $6)
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRow(insertQ, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.UserAgent, next.IPAddress).
+		Scan(&next.ID, &next.CreatedAt); err != nil {
+		return fmt.Errorf("RotateRefreshToken insert: %w", err)
+	}
+
+	res, err := tx.Exec(`
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $1
+        WHERE id = $2 AND revoked_at IS NULL
+    `, next.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("RotateRefreshToken revoke: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("RotateRefreshToken rows: %w", err)
+	}
+	if rows == 0 {
+		return repository.ErrRefreshTokenAlreadyRotated
+	}
+
+	return tx.Commit()
+}
+
+func (rr *RefreshTokenRepo) RevokeFamily(familyID string) error {
+	_, err := rr.DB.Exec(`
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE family_id = $1 AND revoked_at IS NULL
+    `, familyID)
+	if err != nil {
+		return fmt.Errorf("RevokeFamily: %w", err)
+	}
+	return nil
+}
+
+func (rr *RefreshTokenRepo) RevokeAllForUser(userID uint) error {
+	_, err := rr.DB.Exec(`
+        UPDATE refresh_tokens
+        SET revoked_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `, userID)
+	if err != nil {
+		return fmt.Errorf("RevokeAllForUser: %w", err)
+	}
+	return nil
+}
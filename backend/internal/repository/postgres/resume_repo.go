@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+const resumeColumns = `id, student_id, file_url, file_name, uploaded_at, storage_key, content_type, size_bytes, version, is_primary`
+
+type ResumeRepo struct {
+	DB *sql.DB
+}
+
+func NewResumeRepo(db *sql.DB) repository.ResumeRepository {
+	return &ResumeRepo{DB: db}
+}
+
+func scanResume(row interface{ Scan(...interface{}) error }) (model.Resume, error) {
+	var r model.Resume
+	err := row.Scan(&r.ID, &r.StudentID, &r.FileURL, &r.FileName, &r.UploadedAt,
+		&r.StorageKey, &r.ContentType, &r.SizeBytes, &r.Version, &r.IsPrimary)
+	return r, err
+}
+
+// CreateResume stores a new resume version for the student. The first resume
+// a student uploads becomes primary automatically; file_url is set to the
+// API download path of the new row.
+func (rr *ResumeRepo) CreateResume(r *model.Resume) error {
+	tx, err := rr.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if r.IsPrimary {
+		if _, err := tx.Exec(`UPDATE resumes SET is_primary = FALSE WHERE student_id = $1 AND is_primary`, r.StudentID); err != nil {
+			return fmt.Errorf("CreateResume unset primary: %w", err)
+		}
+	}
+
+	const q = `
+        WITH next AS (SELECT nextval(pg_get_serial_sequence('resumes', 'id')) AS id)
+        INSERT INTO resumes (id, student_id, file_url, file_name, uploaded_at, storage_key, content_type, size_bytes, version, is_primary)
+        SELECT next.id, $1, '/api/v1/resumes/' || next.id || '/download', $2, CURRENT_TIMESTAMP, $3, $4, $5,
+               (SELECT COALESCE(MAX(version), 0) + 1 FROM resumes WHERE student_id = $1),
+               $6 OR NOT EXISTS (SELECT 1 FROM resumes WHERE student_id = $1 AND is_primary)
+        FROM next
+        RETURNING ` + resumeColumns
+	created, err := scanResume(tx.QueryRow(q, r.StudentID, r.FileName, r.StorageKey, r.ContentType, r.SizeBytes, r.IsPrimary))
+	if err != nil {
+		return fmt.Errorf("CreateResume: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*r = created
+	return nil
+}
+
+func (rr *ResumeRepo) GetResumeByID(id uint) (model.Resume, error) {
+	r, err := scanResume(rr.DB.QueryRow(`SELECT `+resumeColumns+` FROM resumes WHERE id = $1`, id))
+	if err != nil {
+		return model.Resume{}, fmt.Errorf("GetResumeByID: %w", err)
+	}
+	return r, nil
+}
+
+func (rr *ResumeRepo) GetResumesByStudentID(studentID uint) ([]model.Resume, error) {
+	rows, err := rr.DB.Query(`SELECT `+resumeColumns+` FROM resumes WHERE student_id = $1 ORDER BY version DESC`, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("GetResumesByStudentID: %w", err)
+	}
+	defer rows.Close()
+
+	var resumes []model.Resume
+	for rows.Next() {
+		r, err := scanResume(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetResumesByStudentID scan: %w", err)
+		}
+		resumes = append(resumes, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetResumesByStudentID rows: %w", err)
+	}
+	return resumes, nil
+}
+
+func (rr *ResumeRepo) GetPrimaryResume(studentID uint) (model.Resume, error) {
+	r, err := scanResume(rr.DB.QueryRow(`SELECT `+resumeColumns+` FROM resumes WHERE student_id = $1 AND is_primary`, studentID))
+	if err != nil {
+		return model.Resume{}, fmt.Errorf("GetPrimaryResume: %w", err)
+	}
+	return r, nil
+}
+
+func (rr *ResumeRepo) SetPrimary(studentID, resumeID uint) error {
+	tx, err := rr.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE resumes SET is_primary = FALSE WHERE student_id = $1 AND is_primary`, studentID); err != nil {
+		return fmt.Errorf("SetPrimary unset: %w", err)
+	}
+	res, err := tx.Exec(`UPDATE resumes SET is_primary = TRUE WHERE id = $1 AND student_id = $2`, resumeID, studentID)
+	if err != nil {
+		return fmt.Errorf("SetPrimary: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("SetPrimary rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (rr *ResumeRepo) DeleteResume(id uint) error {
+	res, err := rr.DB.Exec(`DELETE FROM resumes WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteResume: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteResume rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (rr *ResumeRepo) CountApplicationsWithResume(resumeID uint) (int, error) {
+	var cnt int
+	if err := rr.DB.QueryRow(`SELECT COUNT(*) FROM applications WHERE resume_id = $1`, resumeID).Scan(&cnt); err != nil {
+		return 0, fmt.Errorf("CountApplicationsWithResume: %w", err)
+	}
+	return cnt, nil
+}
+
+func (rr *ResumeRepo) IsResumeSharedWithEmployer(resumeID, employerID uint) (bool, error) {
+	const q = `
+        SELECT EXISTS (
+            SELECT 1
+            FROM applications a
+            JOIN vacancies v ON v.id = a.vacancy_id
+            WHERE a.resume_id = $1 AND v.employer_id = $2
+        )
+    `
+	var shared bool
+	if err := rr.DB.QueryRow(q, resumeID, employerID).Scan(&shared); err != nil {
+		return false, fmt.Errorf("IsResumeSharedWithEmployer: %w", err)
+	}
+	return shared, nil
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/lib/pq"
+	"time"
+)
+
+const savedSearchColumns = `id, student_id, name, filter, ml_skills, digest, last_checked_at, last_digest_at, created_at, updated_at`
+
+type SavedSearchRepo struct {
+	DB *sql.DB
+}
+
+func NewSavedSearchRepo(db *sql.DB) repository.SavedSearchRepository {
+	return &SavedSearchRepo{DB: db}
+}
+
+func scanSavedSearch(row interface{ Scan(...interface{}) error }) (model.SavedSearch, error) {
+	var s model.SavedSearch
+	var filter []byte
+	if err := row.Scan(&s.ID, &s.StudentID, &s.Name, &filter, &s.MLSkills, &s.Digest,
+		&s.LastCheckedAt, &s.LastDigestAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return model.SavedSearch{}, err
+	}
+	if err := json.Unmarshal(filter, &s.Filter); err != nil {
+		return model.SavedSearch{}, fmt.Errorf("decode filter of saved search %d: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+func (r *SavedSearchRepo) querySavedSearches(op, q string, args ...interface{}) ([]model.SavedSearch, error) {
+	rows, err := r.DB.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	searches := []model.SavedSearch{}
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		searches = append(searches, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s rows: %w", op, err)
+	}
+	return searches, nil
+}
+
+// CreateSavedSearch stores s. Both watermarks start at now, so only vacancies
+// that appear after saving produce notifications.
+func (r *SavedSearchRepo) CreateSavedSearch(s *model.SavedSearch) error {
+	filter, err := json.Marshal(s.Filter)
+	if err != nil {
+		return fmt.Errorf("CreateSavedSearch encode: %w", err)
+	}
+	const q = `
+        INSERT INTO saved_searches (student_id, name, filter, ml_skills, digest, last_checked_at, last_digest_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), NOW(), NOW())
+        RETURNING id, last_checked_at, last_digest_at, created_at, updated_at
+    `
+	if err := r.DB.QueryRow(q, s.StudentID, s.Name, string(filter), s.MLSkills, s.Digest).
+		Scan(&s.ID, &s.LastCheckedAt, &s.LastDigestAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return fmt.Errorf("CreateSavedSearch: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepo) GetSavedSearches(studentID uint) ([]model.SavedSearch, error) {
+	return r.querySavedSearches("GetSavedSearches",
+		`SELECT `+savedSearchColumns+` FROM saved_searches WHERE student_id = $1 ORDER BY created_at DESC, id DESC`, studentID)
+}
+
+func (r *SavedSearchRepo) CountSavedSearches(studentID uint) (int, error) {
+	var n int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM saved_searches WHERE student_id = $1`, studentID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("CountSavedSearches: %w", err)
+	}
+	return n, nil
+}
+
+func (r *SavedSearchRepo) GetSavedSearch(studentID, id uint) (model.SavedSearch, error) {
+	const q = `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE id = $1 AND student_id = $2`
+	s, err := scanSavedSearch(r.DB.QueryRow(q, id, studentID))
+	if err != nil {
+		return model.SavedSearch{}, fmt.Errorf("GetSavedSearch: %w", err)
+	}
+	return s, nil
+}
+
+// UpdateSavedSearch rewrites the name, filter and digest settings. The match
+// watermark is reset so results of the old filter are not reported late.
+func (r *SavedSearchRepo) UpdateSavedSearch(s *model.SavedSearch) error {
+	filter, err := json.Marshal(s.Filter)
+	if err != nil {
+		return fmt.Errorf("UpdateSavedSearch encode: %w", err)
+	}
+	const q = `
+        UPDATE saved_searches
+        SET name = $1, filter = $2, ml_skills = $3, digest = $4,
+            last_checked_at = NOW(), updated_at = NOW()
+        WHERE id = $5 AND student_id = $6
+        RETURNING ` + savedSearchColumns
+	updated, err := scanSavedSearch(r.DB.QueryRow(q, s.Name, string(filter), s.MLSkills, s.Digest, s.ID, s.StudentID))
+	if err != nil {
+		return fmt.Errorf("UpdateSavedSearch: %w", err)
+	}
+	*s = updated
+	return nil
+}
+
+func (r *SavedSearchRepo) DeleteSavedSearch(studentID, id uint) error {
+	res, err := r.DB.Exec(`DELETE FROM saved_searches WHERE id = $1 AND student_id = $2`, id, studentID)
+	if err != nil {
+		return fmt.Errorf("DeleteSavedSearch: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteSavedSearch: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("DeleteSavedSearch: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepo) GetAllSavedSearches() ([]model.SavedSearch, error) {
+	return r.querySavedSearches("GetAllSavedSearches",
+		`SELECT `+savedSearchColumns+` FROM saved_searches ORDER BY id`)
+}
+
+func (r *SavedSearchRepo) MarkSavedSearchChecked(id uint, at time.Time) error {
+	if _, err := r.DB.Exec(`UPDATE saved_searches SET last_checked_at = $1 WHERE id = $2`, at, id); err != nil {
+		return fmt.Errorf("MarkSavedSearchChecked: %w", err)
+	}
+	return nil
+}
+
+// digestDue matches saved searches whose digest interval has passed by $1.
+const digestDue = `((digest = 'DAILY' AND last_digest_at <= $1 - INTERVAL '1 day')
+           OR (digest = 'WEEKLY' AND last_digest_at <= $1 - INTERVAL '7 days'))`
+
+func (r *SavedSearchRepo) GetDigestDueSavedSearches(now time.Time) ([]model.SavedSearch, error) {
+	const q = `
+        SELECT ` + savedSearchColumns + `
+        FROM saved_searches
+        WHERE ` + digestDue + `
+        ORDER BY student_id, id
+    `
+	return r.querySavedSearches("GetDigestDueSavedSearches", q, now)
+}
+
+// ClaimDigests moves last_digest_at to now on those of ids that are still
+// due and returns them. The due check and the update are one statement, so
+// when replicas race for a digest only one of them gets each search back.
+func (r *SavedSearchRepo) ClaimDigests(ids []uint, now time.Time) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	q := `UPDATE saved_searches SET last_digest_at = $1 WHERE id = ANY($2) AND ` + digestDue + ` RETURNING id`
+	rows, err := r.DB.Query(q, now, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("ClaimDigests: %w", err)
+	}
+	defer rows.Close()
+
+	var claimed []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ClaimDigests scan: %w", err)
+		}
+		claimed = append(claimed, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClaimDigests rows: %w", err)
+	}
+	return claimed, nil
+}
+
+// ReleaseDigest undoes ClaimDigests for one search, setting last_digest_at
+// back to prev unless it moved again since the claim at claimedAt.
+func (r *SavedSearchRepo) ReleaseDigest(id uint, claimedAt, prev time.Time) error {
+	const q = `UPDATE saved_searches SET last_digest_at = $1 WHERE id = $2 AND last_digest_at = $3`
+	if _, err := r.DB.Exec(q, prev, id, claimedAt); err != nil {
+		return fmt.Errorf("ReleaseDigest: %w", err)
+	}
+	return nil
+}
@@ -14,9 +14,11 @@ func NewSkillRepo(db *sql.DB) repository.SkillRepository {
 	return &SkillRepo{DB: db}
 }
 
+// GetByName looks the skill up ignoring case, so "Go", "go" and "GO" are the
+// same skill; the unique index on lower(name) keeps it that way.
 func (sr *SkillRepo) GetByName(name string) (model.Skill, error) {
 	var s model.Skill
-	err := sr.DB.QueryRow(`SELECT id,name,created_at,updated_at FROM skills WHERE name=$1`, name).
+	err := sr.DB.QueryRow(`SELECT id,name,created_at,updated_at FROM skills WHERE lower(name)=lower($1)`, name).
 		Scan(&s.ID, &s.Name, &s.CreatedAt, &s.UpdatedAt)
 	if err != nil {
 		return s, err
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+const studentSkillSelect = `
+    SELECT ss.student_id, ss.skill_id, s.name, ss.proficiency_level, ss.years_experience, ss.created_at, ss.updated_at
+    FROM student_skills ss
+    JOIN skills s ON s.id = ss.skill_id
+`
+
+type StudentSkillRepo struct {
+	DB *sql.DB
+}
+
+func NewStudentSkillRepo(db *sql.DB) repository.StudentSkillRepository {
+	return &StudentSkillRepo{DB: db}
+}
+
+func scanStudentSkill(row interface{ Scan(...interface{}) error }) (model.StudentSkill, error) {
+	var s model.StudentSkill
+	err := row.Scan(&s.StudentID, &s.SkillID, &s.SkillName, &s.ProficiencyLevel, &s.YearsExperience, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (r *StudentSkillRepo) GetStudentSkills(studentID uint) ([]model.StudentSkill, error) {
+	rows, err := r.DB.Query(studentSkillSelect+` WHERE ss.student_id = $1 ORDER BY s.name`, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("GetStudentSkills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := make([]model.StudentSkill, 0)
+	for rows.Next() {
+		s, err := scanStudentSkill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetStudentSkills scan: %w", err)
+		}
+		skills = append(skills, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetStudentSkills rows: %w", err)
+	}
+	return skills, nil
+}
+
+func (r *StudentSkillRepo) GetStudentSkill(studentID, skillID uint) (model.StudentSkill, error) {
+	s, err := scanStudentSkill(r.DB.QueryRow(studentSkillSelect+` WHERE ss.student_id = $1 AND ss.skill_id = $2`, studentID, skillID))
+	if err != nil {
+		return model.StudentSkill{}, fmt.Errorf("GetStudentSkill: %w", err)
+	}
+	return s, nil
+}
+
+func (r *StudentSkillRepo) UpsertStudentSkill(s *model.StudentSkill) error {
+	const q = `
+        INSERT INTO student_skills (student_id, skill_id, proficiency_level, years_experience, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, NOW(), NOW())
+        ON CONFLICT (student_id, skill_id) DO UPDATE
+        SET proficiency_level = EXCLUDED.proficiency_level,
+            years_experience  = EXCLUDED.years_experience,
+            updated_at        = NOW()
+        RETURNING created_at, updated_at
+    `
+	if err := r.DB.QueryRow(q, s.StudentID, s.SkillID, s.ProficiencyLevel, s.YearsExperience).Scan(&s.CreatedAt, &s.UpdatedAt); err != nil {
+		return fmt.Errorf("UpsertStudentSkill: %w", err)
+	}
+	return nil
+}
+
+func (r *StudentSkillRepo) DeleteStudentSkill(studentID, skillID uint) error {
+	res, err := r.DB.Exec(`DELETE FROM student_skills WHERE student_id = $1 AND skill_id = $2`, studentID, skillID)
+	if err != nil {
+		return fmt.Errorf("DeleteStudentSkill: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteStudentSkill rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
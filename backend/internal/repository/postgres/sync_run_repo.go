@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+const syncRunColumns = `id, source, status, full_resync, started_at, finished_at, pages, created, updated, unchanged, failed, closed, errors`
+
+type SyncRunRepo struct {
+	DB *sql.DB
+}
+
+func NewSyncRunRepo(db *sql.DB) repository.SyncRunRepository {
+	return &SyncRunRepo{DB: db}
+}
+
+func (r *SyncRunRepo) CreateSyncRun(run *model.SyncRun) error {
+	const q = `
+        INSERT INTO sync_runs (source, status, full_resync, started_at)
+        VALUES ($1, 'RUNNING', $2, NOW())
+        RETURNING id, status, started_at
+    `
+	if err := r.DB.QueryRow(q, run.Source, run.FullResync).Scan(&run.ID, &run.Status, &run.StartedAt); err != nil {
+		return fmt.Errorf("CreateSyncRun: %w", err)
+	}
+	return nil
+}
+
+func (r *SyncRunRepo) FinishSyncRun(run *model.SyncRun) error {
+	const q = `
+        UPDATE sync_runs
+        SET status = $1, finished_at = NOW(), pages = $2, created = $3, updated = $4,
+            unchanged = $5, failed = $6, closed = $7, errors = $8
+        WHERE id = $9
+        RETURNING finished_at
+    `
+	if err := r.DB.QueryRow(q, run.Status, run.Pages, run.Created, run.Updated, run.Unchanged, run.Failed, run.Closed, run.Errors, run.ID).
+		Scan(&run.FinishedAt); err != nil {
+		return fmt.Errorf("FinishSyncRun: %w", err)
+	}
+	return nil
+}
+
+func (r *SyncRunRepo) FailInterruptedSyncRuns(source string) (int64, error) {
+	const q = `
+        UPDATE sync_runs
+        SET status = 'FAILED', finished_at = NOW(),
+            errors = CASE WHEN errors = '' THEN 'interrupted' ELSE errors || E'\ninterrupted' END
+        WHERE source = $1 AND status = 'RUNNING'
+    `
+	res, err := r.DB.Exec(q, source)
+	if err != nil {
+		return 0, fmt.Errorf("FailInterruptedSyncRuns: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetSyncRuns returns the latest runs, of every source when source is empty.
+func (r *SyncRunRepo) GetSyncRuns(source string, limit int) ([]model.SyncRun, error) {
+	const q = `
+        SELECT ` + syncRunColumns + `
+        FROM sync_runs
+        WHERE $1::text = '' OR source = $1::text
+        ORDER BY started_at DESC, id DESC
+        LIMIT $2
+    `
+	rows, err := r.DB.Query(q, source, limit)
+	if err != nil {
+		return nil, fmt.Errorf("GetSyncRuns: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []model.SyncRun{}
+	for rows.Next() {
+		var run model.SyncRun
+		if err := rows.Scan(&run.ID, &run.Source, &run.Status, &run.FullResync, &run.StartedAt, &run.FinishedAt, &run.Pages,
+			&run.Created, &run.Updated, &run.Unchanged, &run.Failed, &run.Closed, &run.Errors); err != nil {
+			return nil, fmt.Errorf("GetSyncRuns scan: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetSyncRuns rows: %w", err)
+	}
+	return runs, nil
+}
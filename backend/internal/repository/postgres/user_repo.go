@@ -6,7 +6,6 @@ import (
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/domain/repository"
 	"github.com/chotamkz/career-track-backend/internal/util"
-	"strings"
 )
 
 type UserRepo struct {
@@ -18,61 +17,6 @@ func NewUserRepo(db *sql.DB, logger *util.Logger) repository.UserRepository {
 	return &UserRepo{DB: db, Logger: logger}
 }
 
-func (ur *UserRepo) EnsureEmployerExists(employerID uint, companyName string) error {
-	dummyEmail := generateDummyEmail(companyName)
-	dummyPassword := "dummy"
-
-	tx, err := ur.DB.Begin()
-	if err != nil {
-		ur.Logger.Errorf("Error starting transaction: %v", err)
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-			return
-		}
-	}()
-
-	userQuery := `
-        INSERT INTO users (id, email, password, user_type, created_at, updated_at)
-        VALUES ($1, $2, $3, 'EMPLOYER', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-        ON CONFLICT (id) DO NOTHING
-    `
-
-	_, err = tx.Exec(userQuery, employerID, dummyEmail, dummyPassword)
-	if err != nil {
-		ur.Logger.Errorf("Error inserting user: %v", err)
-		return err
-	}
-
-	profileQuery := `
-        INSERT INTO employer_profiles (user_id, company_name, company_description, contact_info)
-        VALUES ($1, $2, '', '')
-        ON CONFLICT (user_id) 
-        DO UPDATE SET company_name = $2 WHERE employer_profiles.company_name = '' OR employer_profiles.company_name IS NULL
-    `
-
-	_, err = tx.Exec(profileQuery, employerID, companyName)
-	if err != nil {
-		ur.Logger.Errorf("Error inserting employer profile: %v", err)
-		return err
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		ur.Logger.Errorf("Error committing transaction: %v", err)
-		return err
-	}
-
-	return nil
-}
-
-func generateDummyEmail(companyName string) string {
-	processed := strings.ReplaceAll(companyName, " ", "")
-	return fmt.Sprintf("%s@example.com", strings.ToLower(processed))
-}
-
 func (ur *UserRepo) CreateUser(user *model.User) error {
 	query := `
 		INSERT INTO users (email, password, user_type, created_at, updated_at)
@@ -84,26 +28,132 @@ func (ur *UserRepo) CreateUser(user *model.User) error {
 
 func (ur *UserRepo) GetUserByEmail(email string) (model.User, error) {
 	var user model.User
+	var bannedAt sql.NullTime
 	query := `
-		SELECT id, email, password, user_type, created_at, updated_at
+		SELECT id, email, password, user_type, created_at, updated_at, banned_at, COALESCE(ban_reason, '')
 		FROM users
 		WHERE email = $1
 	`
-	err := ur.DB.QueryRow(query, email).Scan(&user.ID, &user.Email, &user.Password, &user.UserType, &user.CreatedAt, &user.UpdatedAt)
+	err := ur.DB.QueryRow(query, email).Scan(&user.ID, &user.Email, &user.Password, &user.UserType, &user.CreatedAt, &user.UpdatedAt, &bannedAt, &user.BanReason)
 	if err != nil {
 		return model.User{}, fmt.Errorf("GetUserByEmail: %w", err)
 	}
+	if bannedAt.Valid {
+		user.BannedAt = &bannedAt.Time
+	}
 	return user, nil
 }
 
 func (ur *UserRepo) GetByID(userID uint) (model.User, error) {
 	const q = `
-      SELECT id, email, user_type, created_at, updated_at
+      SELECT id, email, user_type, created_at, updated_at, banned_at, COALESCE(ban_reason, '')
       FROM users
       WHERE id = $1
     `
 	var u model.User
+	var bannedAt sql.NullTime
 	err := ur.DB.QueryRow(q, userID).
-		Scan(&u.ID, &u.Email, &u.UserType, &u.CreatedAt, &u.UpdatedAt)
+		Scan(&u.ID, &u.Email, &u.UserType, &u.CreatedAt, &u.UpdatedAt, &bannedAt, &u.BanReason)
+	if bannedAt.Valid {
+		u.BannedAt = &bannedAt.Time
+	}
 	return u, err
 }
+
+func (ur *UserRepo) SearchUsers(filter model.UserFilter, limit, offset int) ([]model.User, int, error) {
+	where := " WHERE 1=1"
+	var args []interface{}
+	idx := 1
+
+	if filter.Query != "" {
+		where += fmt.Sprintf(` AND (u.email ILIKE $%d OR sp.name ILIKE $%d OR ep.company_name ILIKE $%d)`, idx, idx, idx)
+		args = append(args, "%"+filter.Query+"%")
+		idx++
+	}
+	if filter.UserType != "" {
+		where += fmt.Sprintf(" AND u.user_type = $%d", idx)
+		args = append(args, filter.UserType)
+		idx++
+	}
+	if filter.Banned != nil {
+		if *filter.Banned {
+			where += " AND u.banned_at IS NOT NULL"
+		} else {
+			where += " AND u.banned_at IS NULL"
+		}
+	}
+
+	from := `
+        FROM users u
+        LEFT JOIN student_profiles sp ON sp.user_id = u.id
+        LEFT JOIN employer_profiles ep ON ep.user_id = u.id`
+
+	var total int
+	if err := ur.DB.QueryRow("SELECT COUNT(*)"+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("SearchUsers count: %w", err)
+	}
+
+	query := `SELECT u.id, u.email, u.user_type, u.created_at, u.updated_at, u.banned_at, COALESCE(u.ban_reason, '')` +
+		from + where +
+		fmt.Sprintf(" ORDER BY u.created_at DESC, u.id DESC LIMIT $%d OFFSET $%d", idx, idx+1)
+	args = append(args, limit, offset)
+
+	rows, err := ur.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("SearchUsers query: %w", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var u model.User
+		var bannedAt sql.NullTime
+		if err := rows.Scan(&u.ID, &u.Email, &u.UserType, &u.CreatedAt, &u.UpdatedAt, &bannedAt, &u.BanReason); err != nil {
+			return nil, 0, fmt.Errorf("SearchUsers scan: %w", err)
+		}
+		if bannedAt.Valid {
+			u.BannedAt = &bannedAt.Time
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("SearchUsers rows: %w", err)
+	}
+	return users, total, nil
+}
+
+func (ur *UserRepo) SetBanned(userID uint, banned bool, reason string) error {
+	var res sql.Result
+	var err error
+	if banned {
+		res, err = ur.DB.Exec(`UPDATE users SET banned_at = CURRENT_TIMESTAMP, ban_reason = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, reason, userID)
+	} else {
+		res, err = ur.DB.Exec(`UPDATE users SET banned_at = NULL, ban_reason = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("SetBanned: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("SetBanned rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (ur *UserRepo) DeleteUser(userID uint) error {
+	res, err := ur.DB.Exec(`DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("DeleteUser: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("DeleteUser rows: %w", err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
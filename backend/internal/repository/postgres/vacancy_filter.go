@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vacancySearchQuery matches both Russian and English word forms; websearch
+// syntax lets users write "quoted phrases", OR and -exclusions.
+const vacancySearchQuery = `(websearch_to_tsquery('russian', %[1]s) || websearch_to_tsquery('english', %[1]s))`
+
+const vacancyHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`
+
+// listedVacancy restricts a query over vacancies aliased as v to the rows
+// public listings show.
+const listedVacancy = `v.status = 'PUBLISHED' AND v.deleted_at IS NULL`
+
+// queryArgs collects positional arguments and hands out their placeholders.
+type queryArgs struct {
+	values []interface{}
+}
+
+func (a *queryArgs) add(v interface{}) string {
+	a.values = append(a.values, v)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+// vacancyFilterSQL holds one SQL condition per filter, each "TRUE" when the
+// filter is not set. tsQuery is empty unless the filter has keywords.
+type vacancyFilterSQL struct {
+	keywords   string
+	region     string
+	experience string
+	salary     string
+	schedule   string
+	company    string
+	tsQuery    string
+	args       queryArgs
+}
+
+func buildVacancyFilter(filter model.VacancyFilter) *vacancyFilterSQL {
+	f := &vacancyFilterSQL{
+		keywords:   "TRUE",
+		region:     "TRUE",
+		experience: "TRUE",
+		salary:     "TRUE",
+		schedule:   "TRUE",
+		company:    "TRUE",
+	}
+
+	if kw := strings.TrimSpace(filter.Keywords); kw != "" {
+		f.tsQuery = fmt.Sprintf(vacancySearchQuery, f.args.add(kw))
+		f.keywords = "v.search_vector @@ " + f.tsQuery
+	}
+	if filter.Region != "" {
+		var regionConds []string
+		for _, region := range strings.Split(filter.Region, ",") {
+			region = strings.TrimSpace(region)
+			if region != "" {
+				regionConds = append(regionConds, "v.location ILIKE "+f.args.add("%"+region+"%"))
+			}
+		}
+		if len(regionConds) > 0 {
+			f.region = "(" + strings.Join(regionConds, " OR ") + ")"
+		}
+	}
+	if filter.Experience != "" {
+		f.experience = "v.experience = " + f.args.add(filter.Experience)
+	}
+	var salaryConds []string
+	if filter.SalaryFrom > 0 {
+		salaryConds = append(salaryConds, "v.salary_from_kzt >= "+f.args.add(filter.SalaryFrom))
+	}
+	// A vacancy matches the range when its own fork overlaps it; a missing
+	// end of the fork is taken to equal the other end.
+	if filter.SalaryMin > 0 {
+		salaryConds = append(salaryConds, "COALESCE(v.salary_to_kzt, v.salary_from_kzt) >= "+f.args.add(filter.SalaryMin))
+	}
+	if filter.SalaryMax > 0 {
+		salaryConds = append(salaryConds, "COALESCE(v.salary_from_kzt, v.salary_to_kzt) <= "+f.args.add(filter.SalaryMax))
+	}
+	if len(salaryConds) > 0 {
+		f.salary = "(" + strings.Join(salaryConds, " AND ") + ")"
+	}
+	if filter.Schedule != "" {
+		f.schedule = "v.work_schedule = " + f.args.add(filter.Schedule)
+	}
+	if filter.CompanyName != "" {
+		f.company = "c.name ILIKE " + f.args.add("%"+filter.CompanyName+"%")
+	}
+	return f
+}
+
+// where combines every filter condition with the listing visibility rule.
+func (f *vacancyFilterSQL) where() string {
+	return strings.Join([]string{listedVacancy, f.keywords, f.region, f.experience, f.salary, f.schedule, f.company}, " AND ")
+}
+
+// orderBy resolves the requested sort. Relevance needs keywords and falls
+// back to date without them; ties are broken by id to keep pages stable.
+func (f *vacancyFilterSQL) orderBy(sort model.VacancySort) string {
+	switch sort {
+	case model.SortSalary:
+		return "COALESCE(v.salary_to_kzt, v.salary_from_kzt) DESC NULLS LAST, v.posted_date DESC, v.id DESC"
+	case model.SortDate:
+		return "v.posted_date DESC, v.id DESC"
+	default:
+		if f.tsQuery != "" {
+			return "ts_rank(v.search_vector, " + f.tsQuery + ") DESC, v.posted_date DESC, v.id DESC"
+		}
+		return "v.posted_date DESC, v.id DESC"
+	}
+}
+
+// snippet selects a highlighted description excerpt, or an empty string when
+// there is nothing to highlight.
+func (f *vacancyFilterSQL) snippet() string {
+	if f.tsQuery == "" {
+		return "''"
+	}
+	return fmt.Sprintf("ts_headline('russian', COALESCE(v.description_text, v.description, ''), %s, '%s')", f.tsQuery, vacancyHeadlineOptions)
+}
+
+// GetVacancyFacets computes every facet in a single query. The CTE evaluates
+// each filter condition once per row as a boolean column; a facet then counts
+// rows matching all conditions except its own. Keywords are not a facet and
+// restrict the base set directly. limit caps the number of values per facet.
+func (vr *VacancyRepo) GetVacancyFacets(filter model.VacancyFilter, salaryThresholds []float64, limit int) (model.VacancyFacets, error) {
+	f := buildVacancyFilter(filter)
+	thresholds := f.args.add(pq.Array(salaryThresholds))
+	limitArg := f.args.add(limit)
+
+	query := `
+		WITH base AS (
+			SELECT v.location, v.experience, v.work_schedule, c.name AS company_name, v.salary_from_kzt AS salary_from,
+			       ` + f.region + ` AS m_region,
+			       ` + f.experience + ` AS m_experience,
+			       ` + f.salary + ` AS m_salary,
+			       ` + f.schedule + ` AS m_schedule,
+			       ` + f.company + ` AS m_company
+			FROM vacancies v
+			JOIN companies c ON c.id = v.company_id
+			WHERE ` + listedVacancy + ` AND ` + f.keywords + `
+		)
+		SELECT 'total', '', COUNT(*)
+		FROM base
+		WHERE m_region AND m_experience AND m_salary AND m_schedule AND m_company
+		UNION ALL
+		(SELECT 'region', location, COUNT(*)
+		 FROM base
+		 WHERE m_experience AND m_salary AND m_schedule AND m_company AND COALESCE(location, '') <> ''
+		 GROUP BY location ORDER BY COUNT(*) DESC, location LIMIT ` + limitArg + `)
+		UNION ALL
+		(SELECT 'experience', experience, COUNT(*)
+		 FROM base
+		 WHERE m_region AND m_salary AND m_schedule AND m_company AND COALESCE(experience, '') <> ''
+		 GROUP BY experience ORDER BY COUNT(*) DESC, experience LIMIT ` + limitArg + `)
+		UNION ALL
+		(SELECT 'schedule', work_schedule, COUNT(*)
+		 FROM base
+		 WHERE m_region AND m_experience AND m_salary AND m_company AND COALESCE(work_schedule, '') <> ''
+		 GROUP BY work_schedule ORDER BY COUNT(*) DESC, work_schedule LIMIT ` + limitArg + `)
+		UNION ALL
+		(SELECT 'company', company_name, COUNT(*)
+		 FROM base
+		 WHERE m_region AND m_experience AND m_salary AND m_schedule AND COALESCE(company_name, '') <> ''
+		 GROUP BY company_name ORDER BY COUNT(*) DESC, company_name LIMIT ` + limitArg + `)
+		UNION ALL
+		(SELECT 'salary', t.threshold::text, COUNT(b.salary_from)
+		 FROM unnest(` + thresholds + `::numeric[]) AS t(threshold)
+		 LEFT JOIN base b ON b.salary_from >= t.threshold
+		      AND b.m_region AND b.m_experience AND b.m_schedule AND b.m_company
+		 GROUP BY t.threshold ORDER BY t.threshold)
+	`
+
+	rows, err := vr.DB.Query(query, f.args.values...)
+	if err != nil {
+		return model.VacancyFacets{}, fmt.Errorf("GetVacancyFacets query: %w", err)
+	}
+	defer rows.Close()
+
+	facets := model.VacancyFacets{
+		Regions:    []model.FacetCount{},
+		Experience: []model.FacetCount{},
+		Schedules:  []model.FacetCount{},
+		Companies:  []model.FacetCount{},
+		SalaryFrom: []model.SalaryBucket{},
+	}
+	for rows.Next() {
+		var facet, value string
+		var count int
+		if err := rows.Scan(&facet, &value, &count); err != nil {
+			return model.VacancyFacets{}, fmt.Errorf("GetVacancyFacets scan: %w", err)
+		}
+		fc := model.FacetCount{Value: value, Count: count}
+		switch facet {
+		case "total":
+			facets.Total = count
+		case "region":
+			facets.Regions = append(facets.Regions, fc)
+		case "experience":
+			facets.Experience = append(facets.Experience, fc)
+		case "schedule":
+			facets.Schedules = append(facets.Schedules, fc)
+		case "company":
+			facets.Companies = append(facets.Companies, fc)
+		case "salary":
+			from, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return model.VacancyFacets{}, fmt.Errorf("GetVacancyFacets salary bucket %q: %w", value, err)
+			}
+			facets.SalaryFrom = append(facets.SalaryFrom, model.SalaryBucket{From: from, Count: count})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return model.VacancyFacets{}, fmt.Errorf("GetVacancyFacets rows: %w", err)
+	}
+	return facets, nil
+}
+
+// keysetCondition restricts rows to one side of the cursor. Pages before the
+// cursor are read in ascending order and must be reversed by the caller.
+// posted_date is NOT NULL, so every row compares against the cursor.
+func keysetCondition(cursor *model.VacancyCursor, args *queryArgs) (cond, order string) {
+	if cursor == nil {
+		return "TRUE", "v.posted_date DESC, v.id DESC"
+	}
+	if cursor.Before {
+		return fmt.Sprintf("(v.posted_date, v.id) > (%s, %s)", args.add(cursor.PostedDate), args.add(cursor.ID)),
+			"v.posted_date ASC, v.id ASC"
+	}
+	return fmt.Sprintf("(v.posted_date, v.id) < (%s, %s)", args.add(cursor.PostedDate), args.add(cursor.ID)),
+		"v.posted_date DESC, v.id DESC"
+}
+
+func reverseVacancies(vacs []model.Vacancy) {
+	for i, j := 0, len(vacs)-1; i < j; i, j = i+1, j-1 {
+		vacs[i], vacs[j] = vacs[j], vacs[i]
+	}
+}
+
+// GetVacanciesByCursor lists vacancies newest first starting at the cursor.
+// With a studentID the Applied flag is filled in.
+func (vr *VacancyRepo) GetVacanciesByCursor(cursor *model.VacancyCursor, limit int, studentID *uint) ([]model.Vacancy, error) {
+	var args queryArgs
+	applied, bookmarked := "FALSE", "FALSE"
+	if studentID != nil {
+		student := args.add(*studentID)
+		applied = `EXISTS (
+                SELECT 1 FROM applications a
+                WHERE a.vacancy_id = v.id AND a.student_id = ` + student + ` AND a.status <> 'WITHDRAWN'
+            )`
+		bookmarked = `EXISTS (
+                SELECT 1 FROM vacancy_bookmarks b
+                WHERE b.vacancy_id = v.id AND b.student_id = ` + student + `
+            )`
+	}
+	cond, order := keysetCondition(cursor, &args)
+	query := `
+        SELECT 
+            v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
+            v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url,
+            v.work_schedule, v.experience,
+            ` + applied + `, ` + bookmarked + `
+        FROM vacancies v
+        WHERE ` + listedVacancy + ` AND ` + cond + `
+        ORDER BY ` + order + `
+        LIMIT ` + args.add(limit)
+
+	rows, err := vr.DB.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("GetVacanciesByCursor query: %v", err)
+	}
+	defer rows.Close()
+
+	vacancies := make([]model.Vacancy, 0, limit)
+	for rows.Next() {
+		var v model.Vacancy
+		if err := rows.Scan(
+			&v.ID, &v.Title, &v.Requirements,
+			&v.Location, &v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
+			&v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
+			&v.Applied, &v.Bookmarked,
+		); err != nil {
+			return nil, fmt.Errorf("GetVacanciesByCursor scan: %v", err)
+		}
+		vacancies = append(vacancies, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetVacanciesByCursor rows: %v", err)
+	}
+	if cursor != nil && cursor.Before {
+		reverseVacancies(vacancies)
+	}
+	return vacancies, nil
+}
+
+// GetFilteredVacanciesByCursor is the keyset variant of GetFilteredVacancies;
+// results are always ordered by date.
+func (vr *VacancyRepo) GetFilteredVacanciesByCursor(filter model.VacancyFilter, cursor *model.VacancyCursor, limit int) ([]model.Vacancy, error) {
+	f := buildVacancyFilter(filter)
+	cond, order := keysetCondition(cursor, &f.args)
+	query := `
+		SELECT v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at, 
+		       v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience,
+		       ` + f.snippet() + `, v.salary_from_kzt, v.salary_to_kzt
+		FROM vacancies v
+		JOIN companies c ON c.id = v.company_id
+		WHERE ` + f.where() + ` AND ` + cond + `
+		ORDER BY ` + order + `
+		LIMIT ` + f.args.add(limit)
+
+	rows, err := vr.DB.Query(query, f.args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("GetFilteredVacanciesByCursor query: %v", err)
+	}
+	defer rows.Close()
+
+	vacs := make([]model.Vacancy, 0, limit)
+	for rows.Next() {
+		var v model.Vacancy
+		if err := rows.Scan(
+			&v.ID, &v.Title, &v.Requirements, &v.Location, &v.PostedDate,
+			&v.EmployerID, &v.CreatedAt,
+			&v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross,
+			&v.VacancyURL, &v.WorkSchedule, &v.Experience,
+			&v.Snippet, &v.SalaryFromKZT, &v.SalaryToKZT,
+		); err != nil {
+			return nil, fmt.Errorf("GetFilteredVacanciesByCursor scan: %v", err)
+		}
+		vacs = append(vacs, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetFilteredVacanciesByCursor rows: %v", err)
+	}
+	if cursor != nil && cursor.Before {
+		reverseVacancies(vacs)
+	}
+	return vacs, nil
+}
+
+// GetFilteredVacanciesChangedBetween returns listed vacancies matching the
+// filter that were created or updated after the cursor and no later than to,
+// oldest first, so the caller can page with the position of the last one.
+// Saved searches use it to find what is new since their last run.
+func (vr *VacancyRepo) GetFilteredVacanciesChangedBetween(filter model.VacancyFilter, after model.VacancyChangeCursor, to time.Time, limit int) ([]model.Vacancy, error) {
+	f := buildVacancyFilter(filter)
+	changedAfter := `v.updated_at > ` + f.args.add(after.UpdatedAt)
+	if after.ID != 0 {
+		changedAfter = `(v.updated_at, v.id) > (` + f.args.add(after.UpdatedAt) + `, ` + f.args.add(after.ID) + `)`
+	}
+	query := `
+		SELECT v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
+		       v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience,
+		       v.salary_from_kzt, v.salary_to_kzt, v.updated_at
+		FROM vacancies v
+		JOIN companies c ON c.id = v.company_id
+		WHERE ` + f.where() + `
+		  AND ` + changedAfter + ` AND v.updated_at <= ` + f.args.add(to) + `
+		ORDER BY v.updated_at, v.id
+		LIMIT ` + f.args.add(limit)
+
+	rows, err := vr.DB.Query(query, f.args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("GetFilteredVacanciesChangedBetween query: %w", err)
+	}
+	defer rows.Close()
+
+	vacs := make([]model.Vacancy, 0)
+	for rows.Next() {
+		var v model.Vacancy
+		if err := rows.Scan(
+			&v.ID, &v.Title, &v.Requirements, &v.Location, &v.PostedDate,
+			&v.EmployerID, &v.CreatedAt,
+			&v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross,
+			&v.VacancyURL, &v.WorkSchedule, &v.Experience,
+			&v.SalaryFromKZT, &v.SalaryToKZT, &v.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("GetFilteredVacanciesChangedBetween scan: %w", err)
+		}
+		vacs = append(vacs, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetFilteredVacanciesChangedBetween rows: %w", err)
+	}
+	return vacs, nil
+}
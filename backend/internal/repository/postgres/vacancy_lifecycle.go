@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"time"
+)
+
+// ChangeVacancyStatus writes v's status and schedule if the vacancy is still
+// in status from, and returns repository.ErrVacancyStatusChanged otherwise.
+// Publishing moves posted_date to now so the vacancy surfaces as new.
+func (vr *VacancyRepo) ChangeVacancyStatus(v *model.Vacancy, from model.VacancyStatus) error {
+	const q = `
+        UPDATE vacancies
+        SET status = $1,
+            publish_at = $2,
+            expires_at = $3,
+            posted_date = CASE WHEN $6 THEN NOW() ELSE posted_date END,
+            status_changed_at = NOW(),
+            updated_at = NOW()
+        WHERE id = $4 AND status = $5 AND deleted_at IS NULL
+        RETURNING posted_date
+    `
+	rows, err := vr.DB.Query(q, v.Status, v.PublishAt, v.ExpiresAt, v.ID, from, v.Status == model.VacancyPublished)
+	if err != nil {
+		return fmt.Errorf("ChangeVacancyStatus: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("ChangeVacancyStatus rows: %w", err)
+		}
+		return repository.ErrVacancyStatusChanged
+	}
+	if err := rows.Scan(&v.PostedDate); err != nil {
+		return fmt.Errorf("ChangeVacancyStatus scan: %w", err)
+	}
+	return rows.Err()
+}
+
+// PublishScheduledVacancies publishes drafts whose publish_at has come. Those
+// without an explicit expiry get one ttl after publication; ttl <= 0 means
+// they never expire. updated_at moves too so saved searches see them as new.
+func (vr *VacancyRepo) PublishScheduledVacancies(now time.Time, ttl time.Duration) (int64, error) {
+	const q = `
+        UPDATE vacancies
+        SET status = 'PUBLISHED',
+            posted_date = $1,
+            expires_at = COALESCE(expires_at, CASE WHEN $2::float8 > 0 THEN $1 + make_interval(secs => $2::float8) END),
+            status_changed_at = $1,
+            updated_at = $1
+        WHERE status = 'DRAFT' AND publish_at <= $1 AND deleted_at IS NULL
+    `
+	res, err := vr.DB.Exec(q, now, ttl.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("PublishScheduledVacancies: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// ExpireVacancies closes published and paused vacancies past expires_at.
+func (vr *VacancyRepo) ExpireVacancies(now time.Time) (int64, error) {
+	const q = `
+        UPDATE vacancies
+        SET status = 'CLOSED', status_changed_at = $1
+        WHERE status IN ('PUBLISHED', 'PAUSED') AND expires_at <= $1 AND deleted_at IS NULL
+    `
+	res, err := vr.DB.Exec(q, now)
+	if err != nil {
+		return 0, fmt.Errorf("ExpireVacancies: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// ArchiveClosedVacancies archives vacancies that have been closed since
+// before closedBefore.
+func (vr *VacancyRepo) ArchiveClosedVacancies(closedBefore time.Time) (int64, error) {
+	const q = `
+        UPDATE vacancies
+        SET status = 'ARCHIVED', status_changed_at = NOW()
+        WHERE status = 'CLOSED' AND status_changed_at < $1 AND deleted_at IS NULL
+    `
+	res, err := vr.DB.Exec(q, closedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("ArchiveClosedVacancies: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetStaleImportedVacancies returns the source's published and paused
+// vacancies neither seen nor checked since before, least recently checked
+// first. Vacancies of claimed companies are left to their employer.
+func (vr *VacancyRepo) GetStaleImportedVacancies(source string, before time.Time, limit int) ([]model.ImportedVacancy, error) {
+	const q = `
+        SELECT id, title, external_id, status, last_seen_at
+        FROM vacancies
+        WHERE source = $1 AND last_seen_at < $2 AND employer_id IS NULL
+          AND (last_checked_at IS NULL OR last_checked_at < $2)
+          AND status IN ('PUBLISHED', 'PAUSED') AND deleted_at IS NULL
+        ORDER BY last_checked_at NULLS FIRST, last_seen_at
+        LIMIT $3
+    `
+	rows, err := vr.DB.Query(q, source, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("GetStaleImportedVacancies: %w", err)
+	}
+	defer rows.Close()
+
+	vacancies := []model.ImportedVacancy{}
+	for rows.Next() {
+		var v model.ImportedVacancy
+		if err := rows.Scan(&v.ID, &v.Title, &v.ExternalID, &v.Status, &v.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("GetStaleImportedVacancies scan: %w", err)
+		}
+		vacancies = append(vacancies, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetStaleImportedVacancies rows: %w", err)
+	}
+	return vacancies, nil
+}
+
+// MarkVacancyChecked records a liveness check of an imported vacancy; seen
+// tells whether the source still lists it.
+func (vr *VacancyRepo) MarkVacancyChecked(id uint, at time.Time, seen bool) error {
+	const q = `
+        UPDATE vacancies
+        SET last_checked_at = $1,
+            last_seen_at = CASE WHEN $3 THEN $1 ELSE last_seen_at END
+        WHERE id = $2
+    `
+	if _, err := vr.DB.Exec(q, at, id, seen); err != nil {
+		return fmt.Errorf("MarkVacancyChecked: %w", err)
+	}
+	return nil
+}
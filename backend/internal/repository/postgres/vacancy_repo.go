@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/lib/pq"
 	"strings"
 )
 
@@ -18,7 +19,7 @@ func NewVacancyRepo(db *sql.DB) repository.VacancyRepository {
 
 func (vr *VacancyRepo) CountVacancies() (int, error) {
 	var total int
-	err := vr.DB.QueryRow(`SELECT COUNT(*) FROM vacancies`).Scan(&total)
+	err := vr.DB.QueryRow(`SELECT COUNT(*) FROM vacancies v WHERE ` + listedVacancy).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("CountVacancies: %v", err)
 	}
@@ -28,11 +29,12 @@ func (vr *VacancyRepo) CountVacancies() (int, error) {
 func (vr *VacancyRepo) GetVacancies(limit, offset int) ([]model.Vacancy, error) {
 	query := `
         SELECT 
-            id, title, requirements, location, posted_date, employer_id, created_at,
-            salary_from, salary_to, salary_currency, salary_gross, vacancy_url,
-            work_schedule, experience
-        FROM vacancies
-        ORDER BY posted_date DESC
+            v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
+            v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url,
+            v.work_schedule, v.experience
+        FROM vacancies v
+        WHERE ` + listedVacancy + `
+        ORDER BY v.posted_date DESC, v.id DESC
         LIMIT $1 OFFSET $2
     `
 	rows, err := vr.DB.Query(query, limit, offset)
@@ -59,85 +61,111 @@ func (vr *VacancyRepo) GetVacancies(limit, offset int) ([]model.Vacancy, error)
 	return vacancies, nil
 }
 
-func (vr *VacancyRepo) UpsertVacancy(v *model.Vacancy) error {
+// UpsertVacancy inserts an imported vacancy or refreshes the one with the
+// same source and external id. updated_at only moves when a field actually
+// changed, which is how an update is told from an unchanged row; last_seen_at
+// always moves. A vacancy whose company was claimed belongs to the employer
+// now and is only marked seen.
+func (vr *VacancyRepo) UpsertVacancy(v *model.Vacancy) (model.UpsertResult, error) {
+	if v.Source == "" || v.ExternalID == "" {
+		return model.UpsertUnchanged, fmt.Errorf("UpsertVacancy: imported vacancy %q has no source or external id", v.Title)
+	}
 	query := `
-        WITH upsert AS (
-            INSERT INTO vacancies (
-                title, description, requirements, location, posted_date, employer_id, created_at,
-                salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience
-            )
-            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-            ON CONFLICT (vacancy_url) WHERE vacancy_url IS NOT NULL 
-            DO UPDATE SET
-                title = EXCLUDED.title,
-                description = EXCLUDED.description,
-                requirements = EXCLUDED.requirements,
-                location = EXCLUDED.location,
-                posted_date = EXCLUDED.posted_date,
-                employer_id = EXCLUDED.employer_id,
-                salary_from = EXCLUDED.salary_from,
-                salary_to = EXCLUDED.salary_to,
-                salary_currency = EXCLUDED.salary_currency,
-                salary_gross = EXCLUDED.salary_gross,
-                work_schedule = EXCLUDED.work_schedule,
-                experience = EXCLUDED.experience
-            RETURNING id
+        INSERT INTO vacancies (
+            title, description, requirements, location, posted_date, employer_id, created_at,
+            salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience,
+            salary_from_kzt, salary_to_kzt, source, external_id, company_id, description_text, last_seen_at
         )
-        SELECT id FROM upsert
-        UNION ALL
-        SELECT id FROM vacancies WHERE vacancy_url = $12 AND $12 IS NOT NULL
-        LIMIT 1
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6::int, 0), $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, NOW())
+        ON CONFLICT (source, external_id) WHERE source IS NOT NULL
+        DO UPDATE SET
+            title = EXCLUDED.title,
+            description = EXCLUDED.description,
+            description_text = EXCLUDED.description_text,
+            requirements = EXCLUDED.requirements,
+            location = EXCLUDED.location,
+            posted_date = EXCLUDED.posted_date,
+            employer_id = EXCLUDED.employer_id,
+            company_id = EXCLUDED.company_id,
+            salary_from = EXCLUDED.salary_from,
+            salary_to = EXCLUDED.salary_to,
+            salary_currency = EXCLUDED.salary_currency,
+            salary_gross = EXCLUDED.salary_gross,
+            vacancy_url = EXCLUDED.vacancy_url,
+            work_schedule = EXCLUDED.work_schedule,
+            experience = EXCLUDED.experience,
+            salary_from_kzt = EXCLUDED.salary_from_kzt,
+            salary_to_kzt = EXCLUDED.salary_to_kzt,
+            updated_at = CASE
+                WHEN (vacancies.title, vacancies.description, vacancies.requirements, vacancies.location,
+                      vacancies.salary_from, vacancies.salary_to, vacancies.salary_currency, vacancies.salary_gross,
+                      vacancies.vacancy_url, vacancies.work_schedule, vacancies.experience)
+                     IS DISTINCT FROM
+                     (EXCLUDED.title, EXCLUDED.description, EXCLUDED.requirements, EXCLUDED.location,
+                      EXCLUDED.salary_from, EXCLUDED.salary_to, EXCLUDED.salary_currency, EXCLUDED.salary_gross,
+                      EXCLUDED.vacancy_url, EXCLUDED.work_schedule, EXCLUDED.experience)
+                THEN NOW() ELSE vacancies.updated_at END,
+            last_seen_at = NOW()
+        WHERE vacancies.employer_id IS NULL
+        RETURNING id, xmax = 0 AS inserted, updated_at = NOW() AS changed
     `
 
 	var id uint
-	var err error
-
-	if !v.VacancyURL.Valid || v.VacancyURL.String == "" {
-		insertQuery := `
-            INSERT INTO vacancies (
-                title, description, requirements, location, posted_date, employer_id, created_at,
-                salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience
-            )
-            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-            RETURNING id
-        `
-		err = vr.DB.QueryRow(insertQuery, v.Title, v.Description, v.Requirements, v.Location, v.PostedDate,
-			v.EmployerID, v.CreatedAt, v.SalaryFrom, v.SalaryTo, v.SalaryCurrency,
-			v.SalaryGross, v.VacancyURL, v.WorkSchedule, v.Experience).Scan(&id)
-	} else {
-		err = vr.DB.QueryRow(query, v.Title, v.Description, v.Requirements, v.Location, v.PostedDate,
-			v.EmployerID, v.CreatedAt, v.SalaryFrom, v.SalaryTo, v.SalaryCurrency,
-			v.SalaryGross, v.VacancyURL.String, v.WorkSchedule, v.Experience).Scan(&id)
+	var inserted, changed bool
+	err := vr.DB.QueryRow(query, v.Title, v.Description, v.Requirements, v.Location, v.PostedDate,
+		v.EmployerID, v.CreatedAt, v.SalaryFrom, v.SalaryTo, v.SalaryCurrency,
+		v.SalaryGross, v.VacancyURL, v.WorkSchedule, v.Experience,
+		v.SalaryFromKZT, v.SalaryToKZT, v.Source, v.ExternalID, v.CompanyID, v.DescriptionText).Scan(&id, &inserted, &changed)
+	if err == sql.ErrNoRows {
+		const touch = `UPDATE vacancies SET last_seen_at = NOW() WHERE source = $1 AND external_id = $2 RETURNING id`
+		if err := vr.DB.QueryRow(touch, v.Source, v.ExternalID).Scan(&v.ID); err != nil {
+			return model.UpsertUnchanged, err
+		}
+		return model.UpsertOwned, nil
 	}
-
 	if err != nil {
-		return err
+		return model.UpsertUnchanged, err
 	}
 
 	v.ID = id
-	return nil
+	switch {
+	case inserted:
+		return model.UpsertCreated, nil
+	case changed:
+		return model.UpsertUpdated, nil
+	default:
+		return model.UpsertUnchanged, nil
+	}
 }
 
 func (vr *VacancyRepo) UpdateVacancy(v *model.Vacancy) error {
 	const q = `
     UPDATE vacancies SET
-      title           = $1,
-      description     = $2,
-      location        = $3,
-      salary_from     = $4,
-      salary_to       = $5,
-      salary_currency = $6,
-      salary_gross    = $7,
-      vacancy_url     = $8,
-      work_schedule   = $9,
-      experience      = $10,
-      updated_at      = NOW()
-    WHERE id = $11
+      title                = $1,
+      description          = $2,
+      location             = $3,
+      salary_from          = $4,
+      salary_to            = $5,
+      salary_currency      = $6,
+      salary_gross         = $7,
+      vacancy_url          = $8,
+      work_schedule        = $9,
+      experience           = $10,
+      salary_from_kzt      = $11,
+      salary_to_kzt        = $12,
+      requirements         = $13,
+      description_text     = $14,
+      description_markdown = NULLIF($15, ''),
+      updated_at           = NOW()
+    WHERE id = $16 AND deleted_at IS NULL
     `
 	res, err := vr.DB.Exec(q,
 		v.Title, v.Description, v.Location,
 		v.SalaryFrom, v.SalaryTo, v.SalaryCurrency, v.SalaryGross,
 		v.VacancyURL, v.WorkSchedule, v.Experience,
+		v.SalaryFromKZT, v.SalaryToKZT,
+		v.Requirements,
+		v.DescriptionText, v.DescriptionMarkdown,
 		v.ID,
 	)
 	if err != nil {
@@ -155,15 +183,22 @@ func (vr *VacancyRepo) UpdateVacancy(v *model.Vacancy) error {
 
 func (vr *VacancyRepo) GetVacancyById(id uint) (model.Vacancy, error) {
 	query := `
-		SELECT id, title, description, requirements, location, posted_date, employer_id, created_at, salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience
+		SELECT id, title, description, requirements, location, posted_date, COALESCE(employer_id, 0), company_id, created_at, salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience,
+		       status, publish_at, expires_at, deleted_at, updated_at, COALESCE(source, ''), COALESCE(external_id, ''),
+		       COALESCE(description_text, ''), COALESCE(description_markdown, '')
 		FROM vacancies
 		WHERE id = $1
     `
 	var v model.Vacancy
 	err := vr.DB.QueryRow(query, id).Scan(
 		&v.ID, &v.Title, &v.Description, &v.Requirements, &v.Location,
-		&v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
+		&v.PostedDate, &v.EmployerID, &v.CompanyID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
+		&v.Status, &v.PublishAt, &v.ExpiresAt, &v.DeletedAt, &v.UpdatedAt, &v.Source, &v.ExternalID,
+		&v.DescriptionText, &v.DescriptionMarkdown,
 	)
+	if err != nil {
+		return model.Vacancy{}, err
+	}
 	skills, err := vr.getSkillsForVacancy(v.ID)
 	if err != nil {
 		return model.Vacancy{}, fmt.Errorf("GetVacancyById: %w", err)
@@ -176,13 +211,15 @@ func (vr *VacancyRepo) GetVacancyWithDetails(id uint) (model.VacancyDetailRespon
 	query := `
         SELECT 
             v.id, v.title, v.description, v.requirements, v.location, 
-            v.posted_date, v.employer_id, v.created_at, v.salary_from, v.salary_to, 
+            v.posted_date, COALESCE(v.employer_id, 0), v.company_id, v.created_at, v.salary_from, v.salary_to, 
             v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience,
-            e.company_name
+            v.status, v.publish_at, v.expires_at, v.deleted_at, v.updated_at,
+            COALESCE(v.description_text, ''), COALESCE(v.description_markdown, ''),
+            c.name
         FROM 
             vacancies v
         JOIN 
-            employer_profiles e ON v.employer_id = e.user_id
+            companies c ON c.id = v.company_id
         WHERE 
             v.id = $1
     `
@@ -192,8 +229,10 @@ func (vr *VacancyRepo) GetVacancyWithDetails(id uint) (model.VacancyDetailRespon
 
 	err := vr.DB.QueryRow(query, id).Scan(
 		&v.ID, &v.Title, &v.Description, &v.Requirements, &v.Location,
-		&v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
+		&v.PostedDate, &v.EmployerID, &v.CompanyID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
 		&v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
+		&v.Status, &v.PublishAt, &v.ExpiresAt, &v.DeletedAt, &v.UpdatedAt,
+		&v.DescriptionText, &v.DescriptionMarkdown,
 		&result.CompanyName,
 	)
 	if err != nil {
@@ -215,14 +254,20 @@ func (vr *VacancyRepo) GetVacancyWithDetailsAndApplication(id uint, studentID ui
 	query := `
         SELECT 
             v.id, v.title, v.description, v.requirements, v.location, 
-            v.posted_date, v.employer_id, v.created_at, v.salary_from, v.salary_to, 
+            v.posted_date, COALESCE(v.employer_id, 0), v.company_id, v.created_at, v.salary_from, v.salary_to, 
             v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience,
-            e.company_name,
-            a.status
+            v.status, v.publish_at, v.expires_at, v.deleted_at, v.updated_at,
+            COALESCE(v.description_text, ''), COALESCE(v.description_markdown, ''),
+            c.name,
+            a.status,
+            EXISTS (
+                SELECT 1 FROM vacancy_bookmarks b
+                WHERE b.vacancy_id = v.id AND b.student_id = $2
+            ) AS bookmarked
         FROM 
             vacancies v
         JOIN 
-            employer_profiles e ON v.employer_id = e.user_id
+            companies c ON c.id = v.company_id
         LEFT JOIN 
             (SELECT vacancy_id, status FROM applications 
              WHERE student_id = $2 AND vacancy_id = $1
@@ -237,10 +282,13 @@ func (vr *VacancyRepo) GetVacancyWithDetailsAndApplication(id uint, studentID ui
 
 	err := vr.DB.QueryRow(query, id, studentID).Scan(
 		&v.ID, &v.Title, &v.Description, &v.Requirements, &v.Location,
-		&v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
+		&v.PostedDate, &v.EmployerID, &v.CompanyID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
 		&v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
+		&v.Status, &v.PublishAt, &v.ExpiresAt, &v.DeletedAt, &v.UpdatedAt,
+		&v.DescriptionText, &v.DescriptionMarkdown,
 		&result.CompanyName,
 		&appStatus,
+		&v.Bookmarked,
 	)
 	if err != nil {
 		return model.VacancyDetailResponse{}, fmt.Errorf("GetVacancyWithDetailsAndApplication: %w", err)
@@ -262,110 +310,30 @@ func (vr *VacancyRepo) GetVacancyWithDetailsAndApplication(id uint, studentID ui
 }
 
 func (vr *VacancyRepo) CountFilteredVacancies(filter model.VacancyFilter) (int, error) {
+	f := buildVacancyFilter(filter)
 	query := `
 		SELECT COUNT(*) 
 		FROM vacancies v
-		JOIN employer_profiles ep ON v.employer_id = ep.user_id
-		WHERE 1=1`
-	var args []interface{}
-	idx := 1
-
-	if filter.Keywords != "" {
-		query += fmt.Sprintf(" AND (v.title ILIKE $%d OR v.description ILIKE $%d)", idx, idx)
-		args = append(args, "%"+filter.Keywords+"%")
-		idx++
-	}
-	if filter.Region != "" {
-		regions := strings.Split(filter.Region, ",")
-		var conds []string
-		for _, r := range regions {
-			conds = append(conds, fmt.Sprintf("v.location ILIKE $%d", idx))
-			args = append(args, "%"+strings.TrimSpace(r)+"%")
-			idx++
-		}
-		query += " AND (" + strings.Join(conds, " OR ") + ")"
-	}
-	if filter.Experience != "" {
-		query += fmt.Sprintf(" AND v.experience = $%d", idx)
-		args = append(args, filter.Experience)
-		idx++
-	}
-	if filter.SalaryFrom > 0 {
-		query += fmt.Sprintf(" AND v.salary_from >= $%d", idx)
-		args = append(args, filter.SalaryFrom)
-		idx++
-	}
-	if filter.Schedule != "" {
-		query += fmt.Sprintf(" AND v.work_schedule = $%d", idx)
-		args = append(args, filter.Schedule)
-		idx++
-	}
-	if filter.CompanyName != "" {
-		query += fmt.Sprintf(" AND ep.company_name ILIKE $%d", idx)
-		args = append(args, "%"+filter.CompanyName+"%")
-		idx++
-	}
+		JOIN companies c ON c.id = v.company_id
+		WHERE ` + f.where()
 
 	var total int
-	err := vr.DB.QueryRow(query, args...).Scan(&total)
+	err := vr.DB.QueryRow(query, f.args.values...).Scan(&total)
 	return total, err
 }
 
 func (vr *VacancyRepo) GetFilteredVacancies(filter model.VacancyFilter, limit, offset int) ([]model.Vacancy, error) {
+	f := buildVacancyFilter(filter)
 	query := `
-		SELECT v.id, v.title, v.requirements, v.location, v.posted_date, v.employer_id, v.created_at, 
-		       v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience 
+		SELECT v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at, 
+		       v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience,
+		       ` + f.snippet() + `, v.salary_from_kzt, v.salary_to_kzt
 		FROM vacancies v
-		JOIN employer_profiles ep ON v.employer_id = ep.user_id
-		WHERE 1=1`
-	var args []interface{}
-	argIdx := 1
-
-	if filter.Keywords != "" {
-		query += fmt.Sprintf(" AND (v.title ILIKE $%d OR v.description ILIKE $%d)", argIdx, argIdx)
-		args = append(args, "%"+filter.Keywords+"%")
-		argIdx++
-	}
-	if filter.Region != "" {
-		regions := strings.Split(filter.Region, ",")
-		var regionConds []string
-		for _, region := range regions {
-			region = strings.TrimSpace(region)
-			if region != "" {
-				regionConds = append(regionConds, fmt.Sprintf("v.location ILIKE $%d", argIdx))
-				args = append(args, "%"+region+"%")
-				argIdx++
-			}
-		}
-		if len(regionConds) > 0 {
-			query += " AND (" + strings.Join(regionConds, " OR ") + ")"
-		}
-	}
-	if filter.Experience != "" {
-		query += fmt.Sprintf(" AND v.experience = $%d", argIdx)
-		args = append(args, filter.Experience)
-		argIdx++
-	}
-	if filter.SalaryFrom > 0 {
-		query += fmt.Sprintf(" AND v.salary_from >= $%d", argIdx)
-		args = append(args, filter.SalaryFrom)
-		argIdx++
-	}
-	if filter.Schedule != "" {
-		query += fmt.Sprintf(" AND v.work_schedule = $%d", argIdx)
-		args = append(args, filter.Schedule)
-		argIdx++
-	}
-	if filter.CompanyName != "" {
-		query += fmt.Sprintf(" AND ep.company_name ILIKE $%d", argIdx)
-		args = append(args, "%"+filter.CompanyName+"%")
-		argIdx++
-	}
-
-	query += fmt.Sprintf(" ORDER BY v.posted_date DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, limit, offset)
+		JOIN companies c ON c.id = v.company_id
+		WHERE ` + f.where()
+	query += fmt.Sprintf(" ORDER BY %s LIMIT %s OFFSET %s", f.orderBy(filter.Sort), f.args.add(limit), f.args.add(offset))
 
-	rows, err := vr.DB.Query(query, args...)
+	rows, err := vr.DB.Query(query, f.args.values...)
 	if err != nil {
 		return nil, fmt.Errorf("GetFilteredVacancies query: %v", err)
 	}
@@ -379,6 +347,7 @@ func (vr *VacancyRepo) GetFilteredVacancies(filter model.VacancyFilter, limit, o
 			&v.EmployerID, &v.CreatedAt,
 			&v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross,
 			&v.VacancyURL, &v.WorkSchedule, &v.Experience,
+			&v.Snippet, &v.SalaryFromKZT, &v.SalaryToKZT,
 		); err != nil {
 			return nil, fmt.Errorf("scan vacancy: %v", err)
 		}
@@ -393,8 +362,9 @@ func (vr *VacancyRepo) GetFilteredVacancies(filter model.VacancyFilter, limit, o
 func (vr *VacancyRepo) CreateVacancy(v *model.Vacancy) error {
 	query := `
 		INSERT INTO vacancies 
-		(title, description, requirements, location, posted_date, employer_id, created_at, salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		(title, description, requirements, location, posted_date, employer_id, created_at, salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience, salary_from_kzt, salary_to_kzt,
+		 status, publish_at, expires_at, company_id, description_text, description_markdown)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, NULLIF($22, ''))
 		RETURNING id
 	`
 
@@ -403,29 +373,64 @@ func (vr *VacancyRepo) CreateVacancy(v *model.Vacancy) error {
 		v.Title, v.Description, v.Requirements, v.Location, v.PostedDate,
 		v.EmployerID, v.CreatedAt, v.SalaryFrom, v.SalaryTo, v.SalaryCurrency,
 		v.SalaryGross, v.VacancyURL, v.WorkSchedule, v.Experience,
+		v.SalaryFromKZT, v.SalaryToKZT,
+		v.Status, v.PublishAt, v.ExpiresAt, v.CompanyID, v.DescriptionText, v.DescriptionMarkdown,
 	).Scan(&v.ID)
 }
 
-func (vr *VacancyRepo) InsertVacancySkill(vacancyID uint, skillName string) error {
-	var skillID uint
-	querySelect := `SELECT id FROM skills WHERE name = $1`
-	err := vr.DB.QueryRow(querySelect, skillName).Scan(&skillID)
+// RenormalizeSalaries recomputes the net-KZT salary columns of every vacancy
+// from currency_rates; must match usecase.CurrencyUsecase.Normalize.
+// Vacancies in a currency without a rate end up with NULLs. netRatio is
+// recorded as the one the columns now use.
+func (vr *VacancyRepo) RenormalizeSalaries(netRatio float64) (int64, error) {
+	const q = `
+        UPDATE vacancies v
+        SET salary_from_kzt = CASE WHEN v.salary_from > 0 AND r.rate_to_kzt IS NOT NULL
+                                   THEN v.salary_from * r.rate_to_kzt * CASE WHEN v.salary_gross THEN $1 ELSE 1 END END,
+            salary_to_kzt   = CASE WHEN v.salary_to > 0 AND r.rate_to_kzt IS NOT NULL
+                                   THEN v.salary_to * r.rate_to_kzt * CASE WHEN v.salary_gross THEN $1 ELSE 1 END END
+        FROM vacancies v2
+        LEFT JOIN currency_rates r ON r.code = COALESCE(NULLIF(UPPER(v2.salary_currency), ''), 'KZT')
+        WHERE v2.id = v.id
+    `
+	tx, err := vr.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("RenormalizeSalaries begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(q, netRatio)
+	if err != nil {
+		return 0, fmt.Errorf("RenormalizeSalaries: %w", err)
+	}
+	const ratioQ = `
+        INSERT INTO salary_normalization (net_ratio) VALUES ($1)
+        ON CONFLICT (id) DO UPDATE SET net_ratio = EXCLUDED.net_ratio, updated_at = NOW()
+    `
+	if _, err := tx.Exec(ratioQ, netRatio); err != nil {
+		return 0, fmt.Errorf("RenormalizeSalaries ratio: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("RenormalizeSalaries: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("RenormalizeSalaries commit: %w", err)
+	}
+	return n, nil
+}
+
+// GetSalaryNetRatio returns the net ratio the stored salaries were last
+// normalized with; ok is false when none was recorded.
+func (vr *VacancyRepo) GetSalaryNetRatio() (ratio float64, ok bool, err error) {
+	err = vr.DB.QueryRow(`SELECT net_ratio FROM salary_normalization WHERE id`).Scan(&ratio)
 	if err == sql.ErrNoRows {
-		queryInsert := `INSERT INTO skills (name, created_at, updated_at) VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
-		err = vr.DB.QueryRow(queryInsert, skillName).Scan(&skillID)
-		if err != nil {
-			return err
-		}
-	} else if err != nil {
-		return err
+		return 0, false, nil
 	}
-	query := `
-		INSERT INTO vacancy_skills (vacancy_id, skill_id, created_at, updated_at)
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		ON CONFLICT DO NOTHING
-	`
-	_, err = vr.DB.Exec(query, vacancyID, skillID)
-	return err
+	if err != nil {
+		return 0, false, fmt.Errorf("GetSalaryNetRatio: %w", err)
+	}
+	return ratio, true, nil
 }
 
 func (vr *VacancyRepo) getSkillsForVacancy(vacancyID uint) ([]string, error) {
@@ -469,10 +474,10 @@ func (vr *VacancyRepo) GetVacanciesByIDs(ids []uint) ([]model.Vacancy, error) {
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
 	}
 	query := fmt.Sprintf(`
-		SELECT id, title, requirements, location, posted_date, employer_id, created_at, salary_from, salary_to, salary_currency, salary_gross, vacancy_url, work_schedule, experience
-		FROM vacancies
-		WHERE id IN (%s)
-	`, strings.Join(placeholders, ","))
+		SELECT v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.company_id, v.created_at, v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url, v.work_schedule, v.experience
+		FROM vacancies v
+		WHERE v.id IN (%s) AND %s
+	`, strings.Join(placeholders, ","), listedVacancy)
 	rows, err := vr.DB.Query(query, params...)
 	if err != nil {
 		return nil, err
@@ -482,7 +487,7 @@ func (vr *VacancyRepo) GetVacanciesByIDs(ids []uint) ([]model.Vacancy, error) {
 	var vacancies []model.Vacancy
 	for rows.Next() {
 		var v model.Vacancy
-		err := rows.Scan(&v.ID, &v.Title /*&v.Description,*/, &v.Requirements, &v.Location, &v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience)
+		err := rows.Scan(&v.ID, &v.Title /*&v.Description,*/, &v.Requirements, &v.Location, &v.PostedDate, &v.EmployerID, &v.CompanyID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo, &v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience)
 		if err != nil {
 			return nil, err
 		}
@@ -494,12 +499,17 @@ func (vr *VacancyRepo) GetVacanciesByIDs(ids []uint) ([]model.Vacancy, error) {
 func (vr *VacancyRepo) GetVacanciesByEmployerID(employerID uint) ([]model.Vacancy, error) {
 	const query = `
         SELECT 
-          id, title, requirements, location, posted_date, employer_id, created_at,
-          salary_from, salary_to, salary_currency, salary_gross, vacancy_url,
-          work_schedule, experience
-        FROM vacancies
-        WHERE employer_id = $1
-        ORDER BY posted_date DESC
+          v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
+          v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url,
+          v.work_schedule, v.experience,
+          v.status, v.publish_at, v.expires_at,
+          COUNT(a.id) FILTER (WHERE a.status <> 'WITHDRAWN'),
+          COUNT(a.id) FILTER (WHERE a.status = 'WITHDRAWN')
+        FROM vacancies v
+        LEFT JOIN applications a ON a.vacancy_id = v.id
+        WHERE v.employer_id = $1 AND v.deleted_at IS NULL
+        GROUP BY v.id
+        ORDER BY v.posted_date DESC
     `
 	rows, err := vr.DB.Query(query, employerID)
 	if err != nil {
@@ -510,14 +520,18 @@ func (vr *VacancyRepo) GetVacanciesByEmployerID(employerID uint) ([]model.Vacanc
 	var vacs []model.Vacancy
 	for rows.Next() {
 		var v model.Vacancy
+		var counts model.ApplicationCounts
 		if err := rows.Scan(
 			&v.ID, &v.Title, &v.Requirements, &v.Location, &v.PostedDate,
 			&v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
 			&v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL,
 			&v.WorkSchedule, &v.Experience,
+			&v.Status, &v.PublishAt, &v.ExpiresAt,
+			&counts.Active, &counts.Withdrawn,
 		); err != nil {
 			return nil, fmt.Errorf("scan vacancy: %v", err)
 		}
+		v.Applications = &counts
 		vacs = append(vacs, v)
 	}
 	if err := rows.Err(); err != nil {
@@ -526,8 +540,15 @@ func (vr *VacancyRepo) GetVacanciesByEmployerID(employerID uint) ([]model.Vacanc
 	return vacs, nil
 }
 
+// DeleteVacancyByID soft-deletes a vacancy: it is archived and hidden
+// everywhere, while its applications and their history stay intact.
 func (vr *VacancyRepo) DeleteVacancyByID(vacancyID uint) error {
-	res, err := vr.DB.Exec(`DELETE FROM vacancies WHERE id = $1`, vacancyID)
+	const q = `
+        UPDATE vacancies
+        SET status = 'ARCHIVED', deleted_at = NOW(), status_changed_at = NOW()
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+	res, err := vr.DB.Exec(q, vacancyID)
 	if err != nil {
 		return fmt.Errorf("DeleteVacancyByID exec: %v", err)
 	}
@@ -541,36 +562,39 @@ func (vr *VacancyRepo) DeleteVacancyByID(vacancyID uint) error {
 	return nil
 }
 
+// SetSkills makes skillIDs the vacancy's skill set. Links that stay are left
+// alone, so re-importing a vacancy with the same skills writes nothing.
 func (vr *VacancyRepo) SetSkills(vacancyID uint, skillIDs []uint) error {
+	ids := make([]int64, len(skillIDs))
+	for i, id := range skillIDs {
+		ids[i] = int64(id)
+	}
+
 	tx, err := vr.DB.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	if _, err := tx.Exec(`DELETE FROM vacancy_skills WHERE vacancy_id = $1`, vacancyID); err != nil {
-		return err
-	}
-	stmt, err := tx.Prepare(`INSERT INTO vacancy_skills(vacancy_id, skill_id, created_at, updated_at) VALUES($1,$2,NOW(),NOW())`)
-	if err != nil {
-		return err
+	if _, err := tx.Exec(`DELETE FROM vacancy_skills WHERE vacancy_id = $1 AND skill_id <> ALL($2::int[])`, vacancyID, pq.Array(ids)); err != nil {
+		return fmt.Errorf("SetSkills delete: %w", err)
 	}
-	defer stmt.Close()
-
-	for _, sid := range skillIDs {
-		if _, err := stmt.Exec(vacancyID, sid); err != nil {
-			return err
-		}
+	const insert = `
+        INSERT INTO vacancy_skills (vacancy_id, skill_id, created_at, updated_at)
+        SELECT $1, unnest($2::int[]), NOW(), NOW()
+        ON CONFLICT DO NOTHING
+    `
+	if _, err := tx.Exec(insert, vacancyID, pq.Array(ids)); err != nil {
+		return fmt.Errorf("SetSkills insert: %w", err)
 	}
-
 	return tx.Commit()
 }
 
 func (vr *VacancyRepo) GetAllRegions() ([]string, error) {
 	const q = `
-      SELECT DISTINCT trim(split_part(location, ',', 1)) AS city
-      FROM vacancies
-      WHERE location IS NOT NULL AND location <> ''
+      SELECT DISTINCT trim(split_part(v.location, ',', 1)) AS city
+      FROM vacancies v
+      WHERE v.location IS NOT NULL AND v.location <> '' AND ` + listedVacancy + `
       ORDER BY city
     `
 	rows, err := vr.DB.Query(q)
@@ -596,17 +620,22 @@ func (vr *VacancyRepo) GetAllRegions() ([]string, error) {
 func (vr *VacancyRepo) GetVacanciesWithApplicationStatus(limit, offset int, studentID uint) ([]model.Vacancy, error) {
 	query := `
         SELECT 
-            v.id, v.title, v.requirements, v.location, v.posted_date, v.employer_id, v.created_at,
+            v.id, v.title, v.requirements, v.location, v.posted_date, COALESCE(v.employer_id, 0), v.created_at,
             v.salary_from, v.salary_to, v.salary_currency, v.salary_gross, v.vacancy_url,
             v.work_schedule, v.experience,
-            CASE WHEN a.vacancy_id IS NOT NULL THEN true ELSE false END AS applied
+            EXISTS (
+                SELECT 1 FROM applications a
+                WHERE a.vacancy_id = v.id AND a.student_id = $3 AND a.status <> 'WITHDRAWN'
+            ) AS applied,
+            EXISTS (
+                SELECT 1 FROM vacancy_bookmarks b
+                WHERE b.vacancy_id = v.id AND b.student_id = $3
+            ) AS bookmarked
         FROM 
             vacancies v
-        LEFT JOIN 
-            (SELECT vacancy_id, status FROM applications WHERE student_id = $3) a 
-            ON v.id = a.vacancy_id
+        WHERE ` + listedVacancy + `
         ORDER BY 
-            v.posted_date DESC
+            v.posted_date DESC, v.id DESC
         LIMIT $1 OFFSET $2
     `
 
@@ -623,7 +652,7 @@ func (vr *VacancyRepo) GetVacanciesWithApplicationStatus(limit, offset int, stud
 			&v.ID, &v.Title, &v.Requirements,
 			&v.Location, &v.PostedDate, &v.EmployerID, &v.CreatedAt, &v.SalaryFrom, &v.SalaryTo,
 			&v.SalaryCurrency, &v.SalaryGross, &v.VacancyURL, &v.WorkSchedule, &v.Experience,
-			&v.Applied,
+			&v.Applied, &v.Bookmarked,
 		); err != nil {
 			return nil, fmt.Errorf("GetVacanciesWithApplicationStatus scan: %v", err)
 		}
@@ -636,3 +665,47 @@ func (vr *VacancyRepo) GetVacanciesWithApplicationStatus(limit, offset int, stud
 
 	return vacancies, nil
 }
+
+// GetUnsanitizedDescriptions returns up to limit vacancies, with only their
+// id, description and requirements set, whose description was stored before
+// descriptions were sanitized on write; description_text is NULL for exactly
+// those.
+func (vr *VacancyRepo) GetUnsanitizedDescriptions(limit int) ([]model.Vacancy, error) {
+	const q = `
+        SELECT id, COALESCE(description, ''), COALESCE(requirements, '')
+        FROM vacancies
+        WHERE description_text IS NULL
+        ORDER BY id
+        LIMIT $1
+    `
+	rows, err := vr.DB.Query(q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("GetUnsanitizedDescriptions: %w", err)
+	}
+	defer rows.Close()
+
+	vacancies := []model.Vacancy{}
+	for rows.Next() {
+		var v model.Vacancy
+		if err := rows.Scan(&v.ID, &v.Description, &v.Requirements); err != nil {
+			return nil, fmt.Errorf("GetUnsanitizedDescriptions scan: %w", err)
+		}
+		vacancies = append(vacancies, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetUnsanitizedDescriptions rows: %w", err)
+	}
+	return vacancies, nil
+}
+
+// SetDescription replaces the description of a vacancy with its sanitized
+// HTML and plain-text versions, and its requirements with their plain text.
+// updated_at is left alone: the content is the same, only its representation
+// changed.
+func (vr *VacancyRepo) SetDescription(id uint, descriptionHTML, descriptionText, requirements string) error {
+	const q = `UPDATE vacancies SET description = $2, description_text = $3, requirements = $4 WHERE id = $1`
+	if _, err := vr.DB.Exec(q, id, descriptionHTML, descriptionText, requirements); err != nil {
+		return fmt.Errorf("SetDescription: %w", err)
+	}
+	return nil
+}
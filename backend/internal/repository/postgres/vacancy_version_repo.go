@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+const vacancyVersionColumns = `id, vacancy_id, version, snapshot, changed_by, changed_by_type, created_at`
+
+type VacancyVersionRepo struct {
+	DB *sql.DB
+}
+
+func NewVacancyVersionRepo(db *sql.DB) repository.VacancyVersionRepository {
+	return &VacancyVersionRepo{DB: db}
+}
+
+func scanVacancyVersion(row interface{ Scan(...interface{}) error }) (model.VacancyVersion, error) {
+	var v model.VacancyVersion
+	var snapshot []byte
+	var changedBy sql.NullInt64
+	if err := row.Scan(&v.ID, &v.VacancyID, &v.Version, &snapshot, &changedBy, &v.ChangedByType, &v.CreatedAt); err != nil {
+		return model.VacancyVersion{}, err
+	}
+	if err := json.Unmarshal(snapshot, &v.Snapshot); err != nil {
+		return model.VacancyVersion{}, fmt.Errorf("decode snapshot of vacancy %d v%d: %w", v.VacancyID, v.Version, err)
+	}
+	if changedBy.Valid {
+		id := uint(changedBy.Int64)
+		v.ChangedBy = &id
+	}
+	return v, nil
+}
+
+func (vr *VacancyVersionRepo) GetLatestVersion(vacancyID uint) (model.VacancyVersion, error) {
+	const q = `SELECT ` + vacancyVersionColumns + ` FROM vacancy_versions WHERE vacancy_id = $1 ORDER BY version DESC LIMIT 1`
+	v, err := scanVacancyVersion(vr.DB.QueryRow(q, vacancyID))
+	if err != nil {
+		return model.VacancyVersion{}, fmt.Errorf("GetLatestVersion: %w", err)
+	}
+	return v, nil
+}
+
+// CreateVersion appends v as the next version of its vacancy and fills in the
+// assigned number.
+func (vr *VacancyVersionRepo) CreateVersion(v *model.VacancyVersion) error {
+	snapshot, err := json.Marshal(v.Snapshot)
+	if err != nil {
+		return fmt.Errorf("CreateVersion encode: %w", err)
+	}
+	const q = `
+        INSERT INTO vacancy_versions (vacancy_id, version, snapshot, changed_by, changed_by_type, created_at)
+        SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, NOW()
+        FROM vacancy_versions
+        WHERE vacancy_id = $1
+        RETURNING id, version, created_at
+    `
+	if err := vr.DB.QueryRow(q, v.VacancyID, string(snapshot), v.ChangedBy, v.ChangedByType).Scan(&v.ID, &v.Version, &v.CreatedAt); err != nil {
+		return fmt.Errorf("CreateVersion: %w", err)
+	}
+	return nil
+}
+
+func (vr *VacancyVersionRepo) GetVersions(vacancyID uint) ([]model.VacancyVersion, error) {
+	rows, err := vr.DB.Query(`SELECT `+vacancyVersionColumns+` FROM vacancy_versions WHERE vacancy_id = $1 ORDER BY version`, vacancyID)
+	if err != nil {
+		return nil, fmt.Errorf("GetVersions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []model.VacancyVersion{}
+	for rows.Next() {
+		v, err := scanVacancyVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("GetVersions scan: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetVersions rows: %w", err)
+	}
+	return versions, nil
+}
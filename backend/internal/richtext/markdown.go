@@ -0,0 +1,214 @@
+package richtext
+
+import (
+	"golang.org/x/net/html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Markdown renders the subset of Markdown employers need for a vacancy:
+// ATX headings, paragraphs with hard breaks, "-", "*", "+" and numbered
+// lists, blockquotes, fenced code, horizontal rules, and inline code,
+// bold, italics and [links](url). Raw HTML is escaped rather than passed
+// through. Link targets are not checked here; the result is meant to go
+// through Sanitize like any other HTML.
+func Markdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	return strings.TrimSpace(b.String())
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBullet  = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	mdOrdered = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	mdRule    = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdQuote   = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdFence   = regexp.MustCompile("^\\s{0,3}```")
+	mdLink    = regexp.MustCompile(`^\[([^\]]*)\]\(\s*([^)\s]+)\s*\)`)
+)
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	var para []string
+	var list string // "ul", "ol" or "" outside a list
+	var item []string
+
+	flushPara := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + renderLines(para) + "</p>\n")
+			para = nil
+		}
+	}
+	flushItem := func() {
+		if item != nil {
+			b.WriteString("<li>" + renderLines(item) + "</li>\n")
+			item = nil
+		}
+	}
+	closeList := func() {
+		flushItem()
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openItem := func(kind, text string) {
+		flushPara()
+		flushItem()
+		if list != kind {
+			closeList()
+			b.WriteString("<" + kind + ">\n")
+			list = kind
+		}
+		item = []string{text}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushPara()
+			closeList()
+		case mdFence.MatchString(line):
+			flushPara()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !mdFence.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+		case mdRule.MatchString(line):
+			flushPara()
+			closeList()
+			b.WriteString("<hr>\n")
+		case mdHeading.MatchString(line):
+			flushPara()
+			closeList()
+			m := mdHeading.FindStringSubmatch(line)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			b.WriteString("<" + tag + ">" + renderInline(m[2]) + "</" + tag + ">\n")
+		case mdQuote.MatchString(line):
+			flushPara()
+			closeList()
+			var quoted []string
+			for ; i < len(lines) && mdQuote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, mdQuote.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+		case mdBullet.MatchString(line):
+			openItem("ul", mdBullet.FindStringSubmatch(line)[1])
+		case mdOrdered.MatchString(line):
+			openItem("ol", mdOrdered.FindStringSubmatch(line)[1])
+		case item != nil:
+			// A lazy continuation line of the current list item.
+			item = append(item, line)
+		default:
+			para = append(para, line)
+		}
+	}
+	flushPara()
+	closeList()
+}
+
+// renderLines joins the lines of a paragraph; a line ending in two spaces or
+// a backslash is a hard break.
+func renderLines(lines []string) string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " \t")
+		brk := false
+		if i < len(lines)-1 {
+			switch {
+			case strings.HasSuffix(line, "  "):
+				brk = true
+			case strings.HasSuffix(line, `\`):
+				brk = true
+				line = strings.TrimSuffix(line, `\`)
+			}
+		}
+		out[i] = renderInline(strings.TrimRight(line, " \t"))
+		if brk {
+			out[i] += "<br>"
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderInline renders code spans, emphasis and links in s, escaping
+// everything else. An opening delimiter without a match is kept literally.
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_[]()#+-.!>", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case (c == '*' || c == '_') && strings.HasPrefix(s[i:], strings.Repeat(string(c), 2)):
+			delim := s[i : i+2]
+			if end := closingDelim(s, i+2, delim); end > i+2 {
+				b.WriteString("<strong>" + renderInline(s[i+2:end]) + "</strong>")
+				i = end + 2
+				continue
+			}
+		case c == '*' || c == '_':
+			if c == '_' && i > 0 && isWordByte(s[i-1]) {
+				break
+			}
+			if end := closingDelim(s, i+1, string(c)); end > i+1 {
+				b.WriteString("<em>" + renderInline(s[i+1:end]) + "</em>")
+				i = end + 1
+				continue
+			}
+		case c == '[':
+			if m := mdLink.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(m[2]) + `">` + renderInline(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// closingDelim finds the delimiter closing an emphasis opened just before
+// from, or returns -1. Emphasis may not start or end with a space, and "_"
+// must not be followed by a letter or digit, so snake_case stays as is.
+func closingDelim(s string, from int, delim string) int {
+	if from >= len(s) || s[from] == ' ' {
+		return -1
+	}
+	for i := from + 1; i+len(delim) <= len(s); i++ {
+		if s[i:i+len(delim)] != delim || s[i-1] == ' ' {
+			continue
+		}
+		if len(delim) == 1 && i+1 < len(s) && s[i+1] == delim[0] {
+			// Part of a "**" pair, not a closing "*".
+			i++
+			continue
+		}
+		if delim[0] == '_' && i+len(delim) < len(s) && isWordByte(s[i+len(delim)]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
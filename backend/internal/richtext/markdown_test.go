@@ -0,0 +1,62 @@
+package richtext
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"paragraphs", "Hello\nworld\n\nNext", "<p>Hello\nworld</p>\n<p>Next</p>"},
+		{"hard break", "a  \nb", "<p>a<br>\nb</p>"},
+		{"heading", "## Duties ##", "<h2>Duties</h2>"},
+		{"bullet list", "- Go\n* SQL", "<ul>\n<li>Go</li>\n<li>SQL</li>\n</ul>"},
+		{"ordered list", "1. One\n2) Two", "<ol>\n<li>One</li>\n<li>Two</li>\n</ol>"},
+		{"emphasis", "**bold** and *it* and __b__ and _i_", "<p><strong>bold</strong> and <em>it</em> and <strong>b</strong> and <em>i</em></p>"},
+		{"snake_case stays", "use snake_case_names", "<p>use snake_case_names</p>"},
+		{"inline code", "run `a < b`", "<p>run <code>a &lt; b</code></p>"},
+		{"fenced code", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>"},
+		{"blockquote", "> quoted", "<blockquote>\n<p>quoted</p>\n</blockquote>"},
+		{"rule", "---", "<hr>"},
+		{"escaped markup", `\*not em\*`, "<p>*not em*</p>"},
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com">site</a></p>`},
+
+		{"raw html is escaped", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"raw html attributes are escaped", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{"raw html in a heading", "# <b>x</b>", "<h1>&lt;b&gt;x&lt;/b&gt;</h1>"},
+		{"link href is escaped", `[x](https://a.com/"onmouseover="alert(1))`,
+			`<p><a href="https://a.com/&#34;onmouseover=&#34;alert(1">x</a>)</p>`},
+		{"link title is not supported", `[x](https://a.com "title")`, `<p>[x](https://a.com &#34;title&#34;)</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.in); got != tt.want {
+				t.Errorf("Markdown(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// Markdown leaves link targets to Sanitize, which must drop unsafe ones.
+func TestSanitizeMarkdown(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"https link", "[site](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener">site</a></p>`},
+		{"javascript link", "[x](javascript:alert(1))", "<p>x)</p>"},
+		{"data link", "[x](data:text/html,<script>alert(1)</script>)", "<p>x&lt;/script&gt;)</p>"},
+		{"relative link", "[x](/admin)", "<p>x</p>"},
+		{"entity obfuscated link", "[x](jav&#x61;script:alert(1))", "<p>x)</p>"},
+		{"quotes in a link are encoded", `[x](https://a.com/"onmouseover="alert(1))`,
+			`<p><a href="https://a.com/%22onmouseover=%22alert%281" rel="nofollow noopener">x</a>)</p>`},
+		{"link title", `[x](https://a.com "onmouseover=alert(1)")`, `<p>[x](https://a.com &#34;onmouseover=alert(1)&#34;)</p>`},
+		{"raw html", `<a href="javascript:alert(1)">x</a><svg onload=alert(1)>`,
+			`<p>&lt;a href=&#34;javascript:alert(1)&#34;&gt;x&lt;/a&gt;&lt;svg onload=alert(1)&gt;</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(Markdown(tt.in)); got != tt.want {
+				t.Errorf("Sanitize(Markdown(%q))\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package richtext turns untrusted vacancy descriptions into HTML that is
+// safe to render as is, and into plain text for previews and search.
+package richtext
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"strings"
+)
+
+// allowedTags is the allow-list of elements kept by Sanitize. Any other
+// element is unwrapped: its children stay, the tag and its attributes go.
+var allowedTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Hr: true, atom.Div: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Strong: true, atom.B: true, atom.Em: true, atom.I: true, atom.U: true, atom.S: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Code: true, atom.Sub: true, atom.Sup: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tr: true, atom.Th: true, atom.Td: true,
+	atom.A: true,
+}
+
+// droppedTags are removed together with their content.
+var droppedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Noscript: true, atom.Template: true, atom.Svg: true,
+	atom.Math: true, atom.Head: true, atom.Title: true, atom.Textarea: true,
+	atom.Select: true,
+}
+
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// Sanitize parses s as an HTML fragment and re-renders only allow-listed
+// elements. No attribute survives except href on links, which must be an
+// absolute http, https or mailto URL; links get rel="nofollow noopener".
+// Plain text comes out escaped, so Sanitize is safe on any string.
+func Sanitize(s string) string {
+	nodes, err := parseFragment(s)
+	if err != nil {
+		return html.EscapeString(s)
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		writeSanitized(&b, n)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func parseFragment(s string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(s), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+}
+
+func writeSanitized(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if droppedTags[n.DataAtom] {
+		return
+	}
+
+	keep := allowedTags[n.DataAtom]
+	var href string
+	if n.DataAtom == atom.A {
+		href = safeHref(n)
+		keep = href != ""
+	}
+	if keep {
+		b.WriteString("<" + n.Data)
+		if href != "" {
+			b.WriteString(` href="` + html.EscapeString(href) + `" rel="nofollow noopener"`)
+		}
+		b.WriteString(">")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeSanitized(b, c)
+	}
+	if keep && !isVoid(n.DataAtom) {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+// safeHref returns the link target of a, or "" when it has none or uses a
+// scheme that is not allowed.
+func safeHref(a *html.Node) string {
+	for _, attr := range a.Attr {
+		if attr.Namespace != "" || attr.Key != "href" {
+			continue
+		}
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil || !allowedSchemes[strings.ToLower(u.Scheme)] {
+			return ""
+		}
+		if u.Scheme != "mailto" && u.Host == "" {
+			return ""
+		}
+		return u.String()
+	}
+	return ""
+}
+
+func isVoid(a atom.Atom) bool {
+	return a == atom.Br || a == atom.Hr
+}
@@ -0,0 +1,56 @@
+package richtext
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain text is escaped", `1 < 2 & "3"`, `1 &lt; 2 &amp; &#34;3&#34;`},
+		{"allowed tags stay", `<p>Hi <strong>there</strong></p>`, `<p>Hi <strong>there</strong></p>`},
+		{"unknown tags are unwrapped", `<span class="x">Hi</span> <font>there</font>`, `Hi there`},
+		{"void tags are not closed", `a<br/>b<hr>`, `a<br>b<hr>`},
+
+		{"https link", `<a href="https://example.com/a?b=1&amp;c=2">x</a>`,
+			`<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">x</a>`},
+		{"mailto link", `<a href="mailto:hr@example.com">mail</a>`,
+			`<a href="mailto:hr@example.com" rel="nofollow noopener">mail</a>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"mixed case javascript href", `<a href=" JaVaScRiPt:alert(1)">x</a>`, `x`},
+		{"data href", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, `x`},
+		{"vbscript href", `<a href="vbscript:msgbox(1)">x</a>`, `x`},
+		{"relative href", `<a href="/jobs/1">x</a>`, `x`},
+		{"protocol relative href", `<a href="//evil.example.com">x</a>`, `x`},
+		{"link without href", `<a name="top">x</a>`, `x`},
+		{"hex entity in scheme", `<a href="jav&#x61;script:alert(1)">x</a>`, `x`},
+		{"decimal entity in scheme", `<a href="&#106;avascript:alert(1)">x</a>`, `x`},
+		{"tab inside scheme", "<a href=\"java\tscript:alert(1)\">x</a>", `x`},
+		{"entity encoded colon", `<a href="javascript&colon;alert(1)">x</a>`, `x`},
+
+		{"script is dropped", `<script>alert(1)</script>ok`, `ok`},
+		{"style is dropped", `<style>p{}</style>ok`, `ok`},
+		{"iframe is dropped", `<iframe src="https://example.com"></iframe>ok`, `ok`},
+		{"svg is dropped", `<svg onload="alert(1)"><script>alert(1)</script><a href="https://example.com">x</a></svg>ok`, `ok`},
+		{"svg foreignObject is dropped", `<svg><foreignObject><p>x</p></foreignObject></svg>ok`, `ok`},
+		{"math is dropped", `<math><mtext><a href="javascript:alert(1)">x</a></mtext></math>ok`, `ok`},
+		{"math mglyph mutation", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`, ``},
+		{"noscript is dropped", `<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>ok`, `&#34;&gt;ok`},
+		{"template is dropped", `<template><script>alert(1)</script></template>ok`, `ok`},
+
+		{"event handlers are removed", `<p onclick="alert(1)" onmouseover="alert(2)">hi</p>`, `<p>hi</p>`},
+		{"style and class are removed", `<div style="background:url(javascript:alert(1))" class="x" id="y">hi</div>`, `<div>hi</div>`},
+		{"event handler on a link", `<a href="https://example.com" onclick="alert(1)" target="_blank">x</a>`,
+			`<a href="https://example.com" rel="nofollow noopener">x</a>`},
+		{"img with onerror", `<img src="x" onerror="alert(1)">ok`, `ok`},
+		{"body with onload", `<body onload="alert(1)">ok</body>`, `ok`},
+		{"attribute breaking out of quotes", `<p title='"><script>alert(1)</script>'>hi</p>`, `<p>hi</p>`},
+		{"unclosed tags are closed", `<p><b>hi`, `<p><b>hi</b></p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package richtext
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"strings"
+	"unicode"
+)
+
+// paragraphTags are separated from their surroundings by a blank line in
+// plain text; lineTags by a line break.
+var (
+	paragraphTags = map[atom.Atom]bool{
+		atom.P: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+		atom.H5: true, atom.H6: true, atom.Ul: true, atom.Ol: true,
+		atom.Blockquote: true, atom.Pre: true, atom.Table: true, atom.Hr: true,
+	}
+	lineTags = map[atom.Atom]bool{
+		atom.Br: true, atom.Div: true, atom.Li: true, atom.Tr: true,
+	}
+)
+
+// PlainText renders an HTML fragment as text: whitespace is collapsed,
+// blocks are split into lines and paragraphs, and list items are prefixed
+// with "- ". Content of script-like elements is dropped as in Sanitize.
+func PlainText(s string) string {
+	nodes, err := parseFragment(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	var w textWriter
+	for _, n := range nodes {
+		w.node(n)
+	}
+	return w.b.String()
+}
+
+// textWriter collapses whitespace lazily: spaces and line breaks are only
+// written once more text follows, so none are left at either end.
+type textWriter struct {
+	b        strings.Builder
+	space    bool
+	newlines int
+	// item is set between a list item's "- " and its first character, so a
+	// paragraph inside the item does not split it from its marker.
+	item bool
+}
+
+func (w *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if droppedTags[n.DataAtom] {
+		return
+	}
+
+	breaks := 0
+	switch {
+	case paragraphTags[n.DataAtom]:
+		breaks = 2
+	case lineTags[n.DataAtom]:
+		breaks = 1
+	}
+	w.lineBreak(breaks)
+	if n.DataAtom == atom.Li {
+		w.flush()
+		w.b.WriteString("- ")
+		w.item = true
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+	w.item = false
+	w.lineBreak(breaks)
+	if n.DataAtom == atom.Td || n.DataAtom == atom.Th {
+		w.space = true
+	}
+}
+
+func (w *textWriter) text(s string) {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			w.space = true
+			continue
+		}
+		w.flush()
+		w.b.WriteRune(r)
+		w.item = false
+	}
+}
+
+func (w *textWriter) lineBreak(n int) {
+	if !w.item && n > w.newlines {
+		w.newlines = n
+	}
+}
+
+// flush writes the pending separator, unless nothing has been written yet or
+// the output already ends with one.
+func (w *textWriter) flush() {
+	out := w.b.String()
+	switch {
+	case out == "":
+	case w.newlines > 0:
+		w.b.WriteString(strings.Repeat("\n", w.newlines))
+	case w.space && !strings.HasSuffix(out, " "):
+		w.b.WriteByte(' ')
+	}
+	w.space, w.newlines = false, 0
+}
@@ -0,0 +1,30 @@
+package richtext
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"empty", "", ""},
+		{"whitespace is collapsed", "  Hello \n\t <b>world</b>  ", "Hello world"},
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"headings", "<h2>Duties</h2>Code", "Duties\n\nCode"},
+		{"line breaks", "a<br>b<div>c</div>d", "a\nb\nc\nd"},
+		{"list items", "<ul><li>Go</li><li>SQL</li></ul>", "- Go\n- SQL"},
+		{"paragraph in a list item", "<ul><li><p>Go</p></li><li><p>SQL</p></li></ul>", "- Go\n\n- SQL"},
+		{"table cells", "<table><tr><td>A</td><td>B</td></tr><tr><th>C</th></tr></table>", "A B\nC"},
+		{"entities are decoded", "&lt;b&gt; &amp; &quot;q&quot;", `<b> & "q"`},
+		{"script content is dropped", "<script>alert(1)</script>Hi<style>p{}</style>", "Hi"},
+		{"svg and math content is dropped", "<svg><text>x</text></svg>Hi<math><mi>y</mi></math>", "Hi"},
+		{"attributes are ignored", `<a href="https://example.com" onclick="alert(1)">link</a>`, "link"},
+		{"text between blocks", "intro<p>para</p>outro", "intro\n\npara\n\noutro"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.in); got != tt.want {
+				t.Errorf("PlainText(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"time"
+)
+
+const defaultBookmarkAlertInterval = 5 * time.Minute
+
+// NewBookmarkAlertScheduler periodically tells students about bookmarked
+// vacancies that are about to expire or were closed.
+func NewBookmarkAlertScheduler(dbConn *sql.DB, bookmarkUsecase *usecase.BookmarkUsecase, logger *util.Logger, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = defaultBookmarkAlertInterval
+	}
+	return &PeriodicJob{
+		Name:     "bookmark alert",
+		DB:       dbConn,
+		Logger:   logger,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			expiring, closed, err := bookmarkUsecase.RunBookmarkAlerts(time.Now())
+			if err != nil {
+				return err
+			}
+			if expiring+closed > 0 {
+				logger.Infof("Bookmark alerts: %d expiring, %d closed", expiring, closed)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/db"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"sync"
+	"time"
+)
+
+// PeriodicJob runs Run at once and then every Interval until the server ctx
+// is cancelled. Each run holds a Postgres advisory lock named after the job,
+// so with several replicas only one of them runs it at a time.
+type PeriodicJob struct {
+	Name     string
+	DB       *sql.DB
+	Logger   *util.Logger
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs the job loop in the background; wg is done once it returned.
+func (j *PeriodicJob) Start(ctx context.Context, wg *sync.WaitGroup) {
+	j.Logger.Infof("Starting %s scheduler, every %s", j.Name, j.Interval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		for {
+			if err := j.RunOnce(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				j.Logger.Errorf("%s run failed: %v", j.Name, err)
+			}
+			select {
+			case <-ctx.Done():
+				j.Logger.Infof("%s scheduler stopped", j.Name)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce runs the job unless another replica is running it, in which case
+// it returns nil without doing anything.
+func (j *PeriodicJob) RunOnce(ctx context.Context) error {
+	release, ok, err := db.TryAdvisoryLock(ctx, j.DB, db.JobLockID(j.Name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		j.Logger.Debugf("%s run skipped: running on another instance", j.Name)
+		return nil
+	}
+	defer func() {
+		if err := release(); err != nil {
+			j.Logger.Errorf("Failed to release %s lock: %v", j.Name, err)
+		}
+	}()
+	return j.Run(ctx)
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"time"
+)
+
+const defaultSavedSearchInterval = 15 * time.Minute
+
+// NewSavedSearchScheduler periodically runs students' saved searches and
+// sends the email digests that are due. A failed search run does not hold
+// back the digests.
+func NewSavedSearchScheduler(dbConn *sql.DB, savedSearchUsecase *usecase.SavedSearchUsecase, logger *util.Logger, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = defaultSavedSearchInterval
+	}
+	return &PeriodicJob{
+		Name:     "saved search",
+		DB:       dbConn,
+		Logger:   logger,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			now := time.Now()
+			notified, runErr := savedSearchUsecase.RunSavedSearches(now)
+			if runErr != nil {
+				runErr = fmt.Errorf("saved searches: %w", runErr)
+			} else if notified > 0 {
+				logger.Infof("Saved searches: %d new matches notified", notified)
+			}
+
+			sent, digestErr := savedSearchUsecase.SendDigests(ctx, now)
+			if digestErr != nil {
+				digestErr = fmt.Errorf("digests: %w", digestErr)
+			} else if sent > 0 {
+				logger.Infof("Saved searches: %d digests sent", sent)
+			}
+			return errors.Join(runErr, digestErr)
+		},
+	}
+}
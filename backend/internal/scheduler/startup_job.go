@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/db"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"sync"
+	"time"
+)
+
+// startupLockRetry is how often a replica checks whether another one has
+// finished a startup job.
+const startupLockRetry = 5 * time.Second
+
+// StartOnce runs job once in the background under the advisory lock of name.
+// While another replica holds the lock it waits for that replica to finish,
+// then runs job itself, which by then usually has nothing left to do. The
+// returned channel is closed once job has returned, failed or not, or ctx is
+// cancelled; wg is done at the same time.
+func StartOnce(ctx context.Context, wg *sync.WaitGroup, dbConn *sql.DB, logger *util.Logger, name string, job func(ctx context.Context) error) <-chan struct{} {
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		if err := runOnceLocked(ctx, dbConn, logger, name, job); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Errorf("%s failed: %v", name, err)
+		}
+	}()
+	return done
+}
+
+func runOnceLocked(ctx context.Context, dbConn *sql.DB, logger *util.Logger, name string, job func(ctx context.Context) error) error {
+	for {
+		release, ok, err := db.TryAdvisoryLock(ctx, dbConn, db.JobLockID(name))
+		if err != nil {
+			return err
+		}
+		if ok {
+			defer func() {
+				if err := release(); err != nil {
+					logger.Errorf("Failed to release %s lock: %v", name, err)
+				}
+			}()
+			return job(ctx)
+		}
+		logger.Debugf("%s is running on another instance; waiting", name)
+		timer := time.NewTimer(startupLockRetry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"time"
+)
+
+const defaultLifecycleInterval = time.Minute
+
+// NewVacancyLifecycleScheduler periodically publishes scheduled vacancies,
+// closes expired ones and archives long-closed ones.
+func NewVacancyLifecycleScheduler(dbConn *sql.DB, vacUsecase *usecase.VacancyUsecase, logger *util.Logger, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = defaultLifecycleInterval
+	}
+	return &PeriodicJob{
+		Name:     "vacancy lifecycle",
+		DB:       dbConn,
+		Logger:   logger,
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			published, expired, archived, err := vacUsecase.RunVacancyLifecycle(time.Now())
+			if err != nil {
+				return err
+			}
+			if published+expired+archived > 0 {
+				logger.Infof("Vacancy lifecycle: %d published, %d expired, %d archived", published, expired, archived)
+			}
+			return nil
+		},
+	}
+}
@@ -1,49 +1,157 @@
 package scheduler
 
 import (
+	"context"
 	"database/sql"
-	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/db"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/usecase"
 	"github.com/chotamkz/career-track-backend/internal/util"
-	"strconv"
+	"sync"
 	"time"
 )
 
+var (
+	// ErrSyncInProgress is returned when this process is already syncing.
+	ErrSyncInProgress = errors.New("vacancy sync is already running")
+	// ErrSyncLocked is returned when another replica holds the sync lock.
+	ErrSyncLocked = errors.New("vacancy sync is running on another instance")
+	// ErrSyncNotReady is returned by Trigger before the scheduler's ready
+	// channel is closed.
+	ErrSyncNotReady = errors.New("vacancy sync waits for startup maintenance to finish")
+)
+
+// VacancyScheduler imports vacancies from one source every Interval. Cycles
+// never overlap within a process, and a Postgres advisory lock per source
+// keeps replicas from syncing it at the same time.
 type VacancyScheduler struct {
 	VacancyUpdater *usecase.VacancyUpdater
+	DB             *sql.DB
 	Logger         *util.Logger
-	PerPage        int
-	TotalPages     int
 	Interval       time.Duration
+
+	running sync.Mutex
+	ctx     context.Context
+	wg      *sync.WaitGroup
+	ready   <-chan struct{}
 }
 
-func NewVacancyScheduler(db *sql.DB, userRepo repository.UserRepository, vacancyRepo repository.VacancyRepository, vacUsecase *usecase.VacancyUsecase, logger *util.Logger, perPage, totalPages int, interval time.Duration) *VacancyScheduler {
-	updater := usecase.NewVacancyUpdater(userRepo, vacancyRepo, vacUsecase, logger)
+func NewVacancyScheduler(dbConn *sql.DB, updater *usecase.VacancyUpdater, logger *util.Logger, interval time.Duration) *VacancyScheduler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
 	return &VacancyScheduler{
 		VacancyUpdater: updater,
+		DB:             dbConn,
 		Logger:         logger,
-		PerPage:        perPage,
-		TotalPages:     totalPages,
 		Interval:       interval,
 	}
 }
 
-func (vs *VacancyScheduler) Start() {
-	vs.Logger.Info("Starting vacancy updater scheduler")
+// Source names the source the scheduler imports from.
+func (vs *VacancyScheduler) Source() string {
+	return vs.VacancyUpdater.Source.Name()
+}
+
+// Start runs a cycle as soon as ready is closed and then every Interval until
+// ctx is cancelled. wg is done once the loop and any cycle started by Trigger
+// have returned.
+func (vs *VacancyScheduler) Start(ctx context.Context, wg *sync.WaitGroup, ready <-chan struct{}) {
+	vs.ctx, vs.wg, vs.ready = ctx, wg, ready
+	vs.Logger.Infof("Starting %s vacancy scheduler, every %s", vs.Source(), vs.Interval)
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ready:
+		}
 		ticker := time.NewTicker(vs.Interval)
 		defer ticker.Stop()
 		for {
-			vs.Logger.Info("Starting vacancy update cycle")
-			for page := 0; page < vs.TotalPages; page++ {
-				vs.Logger.Info("Updating vacancies from HH API for page " + strconv.Itoa(page))
-				if err := vs.VacancyUpdater.UpdateVacancies(vs.PerPage, page); err != nil {
-					vs.Logger.Errorf("Error updating vacancies on page %d: %v", page, err)
+			if _, err := vs.RunOnce(ctx); err != nil {
+				switch {
+				case errors.Is(err, context.Canceled):
+				case errors.Is(err, ErrSyncLocked), errors.Is(err, ErrSyncInProgress):
+					vs.Logger.Infof("%s vacancy update cycle skipped: %v", vs.Source(), err)
+				default:
+					vs.Logger.Errorf("%s vacancy update cycle failed: %v", vs.Source(), err)
 				}
-				//time.Sleep(2 * time.Second)
 			}
-			vs.Logger.Info("Vacancy update cycle completed")
-			<-ticker.C
+			select {
+			case <-ctx.Done():
+				vs.Logger.Infof("%s vacancy scheduler stopped", vs.Source())
+				return
+			case <-ticker.C:
+			}
 		}
 	}()
 }
+
+// Trigger starts a cycle in the background, outside the regular schedule. A
+// full cycle lists everything the source offers, ignoring its watermarks.
+func (vs *VacancyScheduler) Trigger(full bool) error {
+	if vs.ctx == nil {
+		return errors.New("vacancy scheduler is not started")
+	}
+	select {
+	case <-vs.ready:
+	default:
+		return ErrSyncNotReady
+	}
+	if !vs.running.TryLock() {
+		return ErrSyncInProgress
+	}
+	vs.wg.Add(1)
+	go func() {
+		defer vs.wg.Done()
+		defer vs.running.Unlock()
+		if _, err := vs.runLocked(vs.ctx, full); err != nil && !errors.Is(err, context.Canceled) {
+			vs.Logger.Warnf("Triggered %s vacancy update cycle failed: %v", vs.Source(), err)
+		}
+	}()
+	return nil
+}
+
+// RunOnce runs a single cycle unless one is already running here or on
+// another replica.
+func (vs *VacancyScheduler) RunOnce(ctx context.Context) (model.SyncRun, error) {
+	if !vs.running.TryLock() {
+		return model.SyncRun{}, ErrSyncInProgress
+	}
+	defer vs.running.Unlock()
+	return vs.runLocked(ctx, false)
+}
+
+func (vs *VacancyScheduler) runLocked(ctx context.Context, full bool) (model.SyncRun, error) {
+	release, ok, err := db.TryAdvisoryLock(ctx, vs.DB, db.SyncLockID(vs.Source()))
+	if err != nil {
+		return model.SyncRun{}, err
+	}
+	if !ok {
+		return model.SyncRun{}, ErrSyncLocked
+	}
+	defer func() {
+		if err := release(); err != nil {
+			vs.Logger.Errorf("Failed to release %s sync lock: %v", vs.Source(), err)
+		}
+	}()
+
+	if full {
+		vs.Logger.Infof("Starting full %s vacancy resync", vs.Source())
+	} else {
+		vs.Logger.Infof("Starting %s vacancy update cycle", vs.Source())
+	}
+	run, err := vs.VacancyUpdater.Sync(ctx, full)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			vs.Logger.Infof("%s vacancy update cycle %d cancelled", vs.Source(), run.ID)
+		}
+		return run, err
+	}
+	vs.Logger.Infof("%s vacancy update cycle %d %s: %d pages, %d created, %d updated, %d unchanged, %d failed, %d closed",
+		vs.Source(), run.ID, run.Status, run.Pages, run.Created, run.Updated, run.Unchanged, run.Failed, run.Closed)
+	return run, nil
+}
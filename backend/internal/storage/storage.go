@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps uploaded files. Keys are slash-separated relative paths such
+// as "resumes/42/3f9a.pdf".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the storage backend selected by driver: "local" or "s3".
+func New(driver, localDir string, s3cfg S3Config) (Storage, error) {
+	switch driver {
+	case "", "local":
+		return NewLocalStorage(localDir)
+	case "s3":
+		return NewS3Storage(s3cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
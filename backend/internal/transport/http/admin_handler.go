@@ -0,0 +1,322 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AdminHandler struct {
+	adminUsecase   *usecase.AdminUsecase
+	authUsecase    *usecase.AuthUsecase
+	vacancyUsecase *usecase.VacancyUsecase
+	hackUsecase    *usecase.HackathonUsecase
+	logger         *util.Logger
+}
+
+func NewAdminHandler(adminUsecase *usecase.AdminUsecase, authUsecase *usecase.AuthUsecase, vacancyUsecase *usecase.VacancyUsecase, hackUsecase *usecase.HackathonUsecase, logger *util.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminUsecase:   adminUsecase,
+		authUsecase:    authUsecase,
+		vacancyUsecase: vacancyUsecase,
+		hackUsecase:    hackUsecase,
+		logger:         logger,
+	}
+}
+
+// TrackAccess updates last_access for every admin request and refreshes the
+// principal's access level from admin_profiles, so a demoted admin loses
+// rights without waiting for the token to expire.
+func (h *AdminHandler) TrackAccess(c *gin.Context) {
+	p, ok := middleware.PrincipalFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	profile, err := h.adminUsecase.TouchLastAccess(p.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin profile not found"})
+			return
+		}
+		h.logger.Errorf("Failed to load admin profile %d: %v", p.UserID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+		return
+	}
+	p.AccessLevel = profile.AccessLevel
+	c.Next()
+}
+
+func (h *AdminHandler) ListUsersHandler(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if err != nil || size < 1 || size > 100 {
+		size = 20
+	}
+
+	filter := model.UserFilter{
+		Query:    c.Query("q"),
+		UserType: model.UserType(c.Query("user_type")),
+	}
+	if bannedStr := c.Query("banned"); bannedStr != "" {
+		banned, err := strconv.ParseBool(bannedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid banned flag"})
+			return
+		}
+		filter.Banned = &banned
+	}
+
+	users, total, err := h.adminUsecase.ListUsers(filter, page, size)
+	if err != nil {
+		h.logger.Errorf("ListUsers failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":       page,
+		"size":       size,
+		"totalCount": total,
+		"users":      users,
+	})
+}
+
+func (h *AdminHandler) BanUserHandler(c *gin.Context) {
+	userID, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid ban input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.adminUsecase.BanUser(p.UserID, userID, input.Reason); err != nil {
+		h.writeModerationError(c, "BanUser", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User banned"})
+}
+
+func (h *AdminHandler) UnbanUserHandler(c *gin.Context) {
+	userID, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+	if err := h.adminUsecase.UnbanUser(userID); err != nil {
+		h.writeModerationError(c, "UnbanUser", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User unbanned"})
+}
+
+func (h *AdminHandler) DeleteUserHandler(c *gin.Context) {
+	userID, ok := parseIDParam(c, "user")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.adminUsecase.DeleteUser(p.UserID, userID); err != nil {
+		h.writeModerationError(c, "DeleteUser", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) CreateAdminHandler(c *gin.Context) {
+	var input struct {
+		Email       string                 `json:"email" binding:"required"`
+		Password    string                 `json:"password" binding:"required"`
+		AccessLevel model.AdminAccessLevel `json:"accessLevel" binding:"required"`
+		Permissions json.RawMessage        `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid admin creation input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	user := model.User{
+		Email:    input.Email,
+		Password: input.Password,
+		UserType: model.UserTypeAdmin,
+	}
+	profile := model.AdminProfile{
+		AccessLevel: input.AccessLevel,
+		Permissions: input.Permissions,
+	}
+	if err := h.authUsecase.RegisterAdmin(&user, &profile); err != nil {
+		if err == usecase.ErrAccountExists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Account already exists"})
+			return
+		}
+		h.logger.Errorf("Admin registration failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		return
+	}
+
+	user.Password = ""
+	c.JSON(http.StatusCreated, gin.H{"user": user, "profile": profile})
+}
+
+func (h *AdminHandler) UpdateVacancyHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	var in vacancyUpdateInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.logger.Errorf("Invalid input for admin vacancy update: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	vac, ok := in.toVacancy(vacancyID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown description format"})
+		return
+	}
+
+	if err := h.vacancyUsecase.AdminUpdateVacancy(&vac, in.Skills); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+			return
+		}
+		h.logger.Errorf("AdminUpdateVacancy failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vacancy"})
+		return
+	}
+	c.JSON(http.StatusOK, vac)
+}
+
+func (h *AdminHandler) DeleteVacancyHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	if err := h.vacancyUsecase.AdminDeleteVacancy(vacancyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+			return
+		}
+		h.logger.Errorf("AdminDeleteVacancy failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vacancy"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) CreateHackathonHandler(c *gin.Context) {
+	var hack model.Hackathon
+	if err := c.ShouldBindJSON(&hack); err != nil {
+		h.logger.Errorf("Invalid hackathon input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if hack.EndDate.IsZero() {
+		hack.EndDate = hack.StartDate
+	}
+	hack.CreatedAt = time.Now()
+	hack.UpdatedAt = time.Now()
+
+	if err := h.hackUsecase.CreateHackathon(&hack); err != nil {
+		h.logger.Errorf("Failed to create hackathon: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hackathon"})
+		return
+	}
+	c.JSON(http.StatusCreated, hack)
+}
+
+func (h *AdminHandler) UpdateHackathonHandler(c *gin.Context) {
+	hackID, ok := parseIDParam(c, "hackathon")
+	if !ok {
+		return
+	}
+	var hack model.Hackathon
+	if err := c.ShouldBindJSON(&hack); err != nil {
+		h.logger.Errorf("Invalid hackathon input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	hack.ID = hackID
+	if hack.EndDate.IsZero() {
+		hack.EndDate = hack.StartDate
+	}
+
+	if err := h.hackUsecase.UpdateHackathon(&hack); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hackathon not found"})
+			return
+		}
+		h.logger.Errorf("Failed to update hackathon: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hackathon"})
+		return
+	}
+	c.JSON(http.StatusOK, hack)
+}
+
+func (h *AdminHandler) DeleteHackathonHandler(c *gin.Context) {
+	hackID, ok := parseIDParam(c, "hackathon")
+	if !ok {
+		return
+	}
+	if err := h.hackUsecase.DeleteHackathon(hackID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hackathon not found"})
+			return
+		}
+		h.logger.Errorf("Failed to delete hackathon: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete hackathon"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) ApplicationStatsHandler(c *gin.Context) {
+	stats, err := h.adminUsecase.GetApplicationStats()
+	if err != nil {
+		h.logger.Errorf("GetApplicationStats failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load application statistics"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *AdminHandler) writeModerationError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrSelfModeration), errors.Is(err, usecase.ErrCannotModerateAdmin):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+	}
+}
+
+// parseIDParam reads the :id path parameter and writes a 400 response when it
+// is not a valid id.
+func parseIDParam(c *gin.Context, entity string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + entity + " id"})
+		return 0, false
+	}
+	return uint(id), true
+}
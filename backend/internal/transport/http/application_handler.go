@@ -3,6 +3,7 @@ package http
 import (
 	"errors"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
 	"github.com/chotamkz/career-track-backend/internal/usecase"
 	"github.com/chotamkz/career-track-backend/internal/util"
 	"github.com/gin-gonic/gin"
@@ -41,6 +42,7 @@ func (ah *ApplicationHandler) SubmitApplicationHandler(c *gin.Context) {
 	}
 	var input struct {
 		CoverLetter string `json:"coverLetter"`
+		ResumeID    *uint  `json:"resumeId"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		ah.logger.Errorf("Invalid input for application: %v", err)
@@ -51,6 +53,7 @@ func (ah *ApplicationHandler) SubmitApplicationHandler(c *gin.Context) {
 		StudentID:   studentID,
 		VacancyID:   uint(vacancyID),
 		CoverLetter: input.CoverLetter,
+		ResumeID:    input.ResumeID,
 	}
 	err = ah.appUsecase.SubmitApplication(&app)
 	if err != nil {
@@ -58,6 +61,18 @@ func (ah *ApplicationHandler) SubmitApplicationHandler(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "You have already applied to this vacancy"})
 			return
 		}
+		if errors.Is(err, usecase.ErrResumeNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Resume not found"})
+			return
+		}
+		if errors.Is(err, usecase.ErrVacancyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+			return
+		}
+		if errors.Is(err, usecase.ErrVacancyNotOpen) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		ah.logger.Errorf("Failed to submit application: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
 		return
@@ -75,18 +90,55 @@ func (ah *ApplicationHandler) UpdateApplicationStatusHandler(c *gin.Context) {
 	}
 	var input struct {
 		NewStatus model.ApplicationStatus `json:"newStatus"`
+		Note      string                  `json:"note"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		ah.logger.Errorf("Invalid input for updating application status: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
-	if err := ah.appUsecase.ChangeApplicationStatus(uint(appID), input.NewStatus); err != nil {
-		ah.logger.Errorf("Failed to update application status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+	p, _ := middleware.PrincipalFromContext(c)
+	change, err := ah.appUsecase.ChangeApplicationStatus(p.UserID, uint(appID), input.NewStatus, input.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidStatus), errors.Is(err, usecase.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrNotVacancyOwner):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this vacancy"})
+		case errors.Is(err, usecase.ErrApplicationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		default:
+			ah.logger.Errorf("Failed to update application status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Application status updated successfully",
+		"change":  change,
+	})
+}
+
+func (ah *ApplicationHandler) GetApplicationHistoryHandler(c *gin.Context) {
+	appID, ok := parseIDParam(c, "application")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	history, err := ah.appUsecase.GetApplicationHistory(p.UserID, p.UserType, appID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrApplicationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		case errors.Is(err, usecase.ErrNotVacancyOwner):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this vacancy"})
+		default:
+			ah.logger.Errorf("GetApplicationHistory: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load application history"})
+		}
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Application status updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"history": history})
 }
 
 func (ah *ApplicationHandler) GetStudentApplicationsHandler(c *gin.Context) {
@@ -135,5 +187,59 @@ func (ah *ApplicationHandler) GetApplicationsForVacancyHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applications": apps})
+	// Withdrawn applications are kept apart so they don't clutter the
+	// employer's pipeline but remain visible for reference.
+	active := make([]model.ApplicationWithStudent, 0, len(apps))
+	withdrawn := make([]model.ApplicationWithStudent, 0)
+	for _, app := range apps {
+		if app.Application.Status == model.StatusWithdrawn {
+			withdrawn = append(withdrawn, app)
+		} else {
+			active = append(active, app)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications": active,
+		"withdrawn":    withdrawn,
+		"counts": model.ApplicationCounts{
+			Active:    len(active),
+			Withdrawn: len(withdrawn),
+		},
+	})
+}
+
+func (ah *ApplicationHandler) WithdrawApplicationHandler(c *gin.Context) {
+	appID, ok := parseIDParam(c, "application")
+	if !ok {
+		return
+	}
+	var input struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			ah.logger.Errorf("Invalid input for withdrawing application: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	change, err := ah.appUsecase.WithdrawApplication(p.UserID, appID, input.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrApplicationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		case errors.Is(err, usecase.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": "Application can no longer be withdrawn"})
+		default:
+			ah.logger.Errorf("Failed to withdraw application: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Application withdrawn",
+		"change":  change,
+	})
 }
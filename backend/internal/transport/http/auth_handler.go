@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/usecase"
 	"github.com/chotamkz/career-track-backend/internal/util"
@@ -100,11 +101,82 @@ func (ah *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
-	token, err := ah.authUsecase.LoginUser(input.Email, input.Password)
+	tokens, err := ah.authUsecase.LoginUser(input.Email, input.Password, sessionMeta(c))
 	if err != nil {
+		if errors.Is(err, usecase.ErrAccountBanned) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is banned"})
+			return
+		}
 		ah.logger.Errorf("Login failed: %v", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokensResponse(tokens))
+}
+
+func (ah *AuthHandler) Refresh(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ah.logger.Errorf("Invalid refresh input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	tokens, err := ah.authUsecase.RefreshTokens(input.RefreshToken, sessionMeta(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRefreshTokenReused):
+			ah.logger.Warnf("Refresh token reuse detected from %s; token family revoked", c.ClientIP())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, please log in again"})
+		case errors.Is(err, usecase.ErrInvalidRefreshToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		case errors.Is(err, usecase.ErrAccountBanned):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account is banned"})
+		default:
+			ah.logger.Errorf("Token refresh failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, tokensResponse(tokens))
+}
+
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+		AllDevices   bool   `json:"allDevices"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		ah.logger.Errorf("Invalid logout input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := ah.authUsecase.Logout(input.RefreshToken, input.AllDevices); err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		ah.logger.Errorf("Logout failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func sessionMeta(c *gin.Context) usecase.SessionMeta {
+	return usecase.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// tokensResponse keeps the legacy "token" key the frontend reads on login.
+func tokensResponse(tokens usecase.AuthTokens) gin.H {
+	return gin.H{
+		"token":        tokens.AccessToken,
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+	}
 }
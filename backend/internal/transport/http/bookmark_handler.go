@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type BookmarkHandler struct {
+	bookmarkUsecase *usecase.BookmarkUsecase
+	logger          *util.Logger
+}
+
+func NewBookmarkHandler(bookmarkUsecase *usecase.BookmarkUsecase, logger *util.Logger) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkUsecase: bookmarkUsecase, logger: logger}
+}
+
+func (h *BookmarkHandler) ListBookmarksHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	bookmarks, err := h.bookmarkUsecase.ListBookmarks(p.UserID)
+	if err != nil {
+		h.logger.Errorf("ListBookmarks failed for student %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load bookmarks"})
+		return
+	}
+	c.JSON(http.StatusOK, bookmarks)
+}
+
+func (h *BookmarkHandler) BookmarkVacancyHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.bookmarkUsecase.BookmarkVacancy(p.UserID, vacancyID); err != nil {
+		h.writeBookmarkError(c, "BookmarkVacancy", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BookmarkHandler) RemoveVacancyBookmarkHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.bookmarkUsecase.RemoveVacancyBookmark(p.UserID, vacancyID); err != nil {
+		h.writeBookmarkError(c, "RemoveVacancyBookmark", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BookmarkHandler) BookmarkHackathonHandler(c *gin.Context) {
+	hackathonID, ok := parseIDParam(c, "hackathon")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.bookmarkUsecase.BookmarkHackathon(p.UserID, hackathonID); err != nil {
+		h.writeBookmarkError(c, "BookmarkHackathon", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BookmarkHandler) RemoveHackathonBookmarkHandler(c *gin.Context) {
+	hackathonID, ok := parseIDParam(c, "hackathon")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.bookmarkUsecase.RemoveHackathonBookmark(p.UserID, hackathonID); err != nil {
+		h.writeBookmarkError(c, "RemoveHackathonBookmark", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BookmarkHandler) writeBookmarkError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrVacancyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+	case errors.Is(err, usecase.ErrHackathonNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hackathon not found"})
+	case errors.Is(err, usecase.ErrVacancyNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": "Vacancy is closed"})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bookmarks"})
+	}
+}
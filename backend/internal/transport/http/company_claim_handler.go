@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CompanyClaimHandler struct {
+	claimUsecase *usecase.CompanyClaimUsecase
+	logger       *util.Logger
+}
+
+func NewCompanyClaimHandler(claimUsecase *usecase.CompanyClaimUsecase, logger *util.Logger) *CompanyClaimHandler {
+	return &CompanyClaimHandler{claimUsecase: claimUsecase, logger: logger}
+}
+
+// RequestClaimHandler mails a verification token to the company address in
+// the body.
+func (h *CompanyClaimHandler) RequestClaimHandler(c *gin.Context) {
+	companyID, ok := parseIDParam(c, "company")
+	if !ok {
+		return
+	}
+	var input struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	claim, err := h.claimUsecase.RequestClaim(c.Request.Context(), p.UserID, companyID, input.Email)
+	if err != nil {
+		h.writeClaimError(c, "RequestCompanyClaim", err)
+		return
+	}
+	c.JSON(http.StatusCreated, claim)
+}
+
+func (h *CompanyClaimHandler) VerifyClaimHandler(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	claim, err := h.claimUsecase.VerifyClaim(p.UserID, input.Token, time.Now())
+	if err != nil {
+		h.writeClaimError(c, "VerifyCompanyClaim", err)
+		return
+	}
+	c.JSON(http.StatusOK, claim)
+}
+
+func (h *CompanyClaimHandler) ListMyClaimsHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	claims, err := h.claimUsecase.ListEmployerClaims(p.UserID)
+	if err != nil {
+		h.writeClaimError(c, "ListEmployerCompanyClaims", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"claims": claims})
+}
+
+// ListClaimsHandler lists claims for review, VERIFIED ones unless ?status=
+// says otherwise; ?status=ALL lists every claim.
+func (h *CompanyClaimHandler) ListClaimsHandler(c *gin.Context) {
+	status := model.CompanyClaimStatus(strings.ToUpper(c.DefaultQuery("status", string(model.CompanyClaimVerified))))
+	if status == "ALL" {
+		status = ""
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+	claims, err := h.claimUsecase.ListClaims(status, limit)
+	if err != nil {
+		h.writeClaimError(c, "ListCompanyClaims", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"claims": claims})
+}
+
+func (h *CompanyClaimHandler) ApproveClaimHandler(c *gin.Context) {
+	h.review(c, "ApproveCompanyClaim", h.claimUsecase.ApproveClaim)
+}
+
+func (h *CompanyClaimHandler) DenyClaimHandler(c *gin.Context) {
+	h.review(c, "DenyCompanyClaim", h.claimUsecase.DenyClaim)
+}
+
+func (h *CompanyClaimHandler) review(c *gin.Context, action string, decide func(adminID, claimID uint, note string, now time.Time) (model.CompanyClaim, error)) {
+	claimID, ok := parseIDParam(c, "claim")
+	if !ok {
+		return
+	}
+	var input struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	claim, err := decide(p.UserID, claimID, input.Note, time.Now())
+	if err != nil {
+		h.writeClaimError(c, action, err)
+		return
+	}
+	c.JSON(http.StatusOK, claim)
+}
+
+func (h *CompanyClaimHandler) writeClaimError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCompanyClaim), errors.Is(err, usecase.ErrInvalidClaimToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCompanyNotFound), errors.Is(err, usecase.ErrCompanyClaimNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCompanyAlreadyClaimed), errors.Is(err, usecase.ErrCompanyClaimInReview),
+		errors.Is(err, usecase.ErrCompanyClaimStateChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process company claim"})
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/currency"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CurrencyHandler struct {
+	currencyUsecase *usecase.CurrencyUsecase
+	ratesFile       string
+	logger          *util.Logger
+}
+
+func NewCurrencyHandler(currencyUsecase *usecase.CurrencyUsecase, ratesFile string, logger *util.Logger) *CurrencyHandler {
+	return &CurrencyHandler{currencyUsecase: currencyUsecase, ratesFile: ratesFile, logger: logger}
+}
+
+func (h *CurrencyHandler) ListRatesHandler(c *gin.Context) {
+	rates, err := h.currencyUsecase.Rates()
+	if err != nil {
+		h.logger.Errorf("Failed to load currency rates: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load currency rates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"base": model.BaseCurrency, "rates": rates})
+}
+
+// UpdateRatesHandler accepts {"rates": {"USD": 505.3}} with prices in KZT.
+func (h *CurrencyHandler) UpdateRatesHandler(c *gin.Context) {
+	var input struct {
+		Rates map[string]float64 `json:"rates" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || len(input.Rates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	rates := make([]model.CurrencyRate, 0, len(input.Rates))
+	for code, rate := range input.Rates {
+		rates = append(rates, model.CurrencyRate{Code: code, RateToKZT: rate})
+	}
+	h.applyRates(c, h.currencyUsecase.SetRates(rates))
+}
+
+// ReloadRatesHandler re-imports rates from CURRENCY_RATES_FILE.
+func (h *CurrencyHandler) ReloadRatesHandler(c *gin.Context) {
+	if h.ratesFile == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "No currency rates file is configured"})
+		return
+	}
+	err := h.currencyUsecase.ImportRates(c.Request.Context(), currency.NewFileRateProvider(h.ratesFile))
+	h.applyRates(c, err)
+}
+
+func (h *CurrencyHandler) applyRates(c *gin.Context, err error) {
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Errorf("Failed to update currency rates: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update currency rates"})
+		return
+	}
+	h.ListRatesHandler(c)
+}
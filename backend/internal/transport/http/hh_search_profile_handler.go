@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/integration/hh"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HHSearchProfileHandler struct {
+	profileUsecase *usecase.HHSearchProfileUsecase
+	profilesFile   string
+	logger         *util.Logger
+}
+
+func NewHHSearchProfileHandler(profileUsecase *usecase.HHSearchProfileUsecase, profilesFile string, logger *util.Logger) *HHSearchProfileHandler {
+	return &HHSearchProfileHandler{profileUsecase: profileUsecase, profilesFile: profilesFile, logger: logger}
+}
+
+func (h *HHSearchProfileHandler) ListProfilesHandler(c *gin.Context) {
+	profiles, err := h.profileUsecase.ListProfiles()
+	if err != nil {
+		h.logger.Errorf("ListHHSearchProfiles failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load HH search profiles"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profiles": profiles})
+}
+
+// CreateProfileHandler takes a profile in the profiles file format, see
+// hh.SearchProfileSpec.
+func (h *HHSearchProfileHandler) CreateProfileHandler(c *gin.Context) {
+	var input hh.SearchProfileSpec
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	profile := input.Profile()
+	if err := h.profileUsecase.CreateProfile(&profile); err != nil {
+		h.writeProfileError(c, "CreateHHSearchProfile", err)
+		return
+	}
+	c.JSON(http.StatusCreated, profile)
+}
+
+func (h *HHSearchProfileHandler) UpdateProfileHandler(c *gin.Context) {
+	profileID, ok := parseIDParam(c, "profile")
+	if !ok {
+		return
+	}
+	var input hh.SearchProfileSpec
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	profile := input.Profile()
+	profile.ID = profileID
+	if err := h.profileUsecase.UpdateProfile(&profile); err != nil {
+		h.writeProfileError(c, "UpdateHHSearchProfile", err)
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *HHSearchProfileHandler) DeleteProfileHandler(c *gin.Context) {
+	profileID, ok := parseIDParam(c, "profile")
+	if !ok {
+		return
+	}
+	if err := h.profileUsecase.DeleteProfile(profileID); err != nil {
+		h.writeProfileError(c, "DeleteHHSearchProfile", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ReloadProfilesHandler re-imports profiles from HH_SEARCH_PROFILES_FILE.
+func (h *HHSearchProfileHandler) ReloadProfilesHandler(c *gin.Context) {
+	if h.profilesFile == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "No HH search profiles file is configured"})
+		return
+	}
+	n, err := h.profileUsecase.ImportFile(h.profilesFile)
+	if err != nil {
+		h.writeProfileError(c, "ImportHHSearchProfiles", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"imported": n})
+}
+
+func (h *HHSearchProfileHandler) writeProfileError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidHHSearchProfile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrHHSearchProfileExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrHHSearchProfileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "HH search profile not found"})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save HH search profile"})
+	}
+}
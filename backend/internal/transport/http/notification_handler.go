@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type NotificationHandler struct {
+	notificationUsecase *usecase.NotificationUsecase
+	logger              *util.Logger
+}
+
+func NewNotificationHandler(notificationUsecase *usecase.NotificationUsecase, logger *util.Logger) *NotificationHandler {
+	return &NotificationHandler{notificationUsecase: notificationUsecase, logger: logger}
+}
+
+func (h *NotificationHandler) ListNotificationsHandler(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if err != nil || size < 1 || size > 100 {
+		size = 20
+	}
+	unreadOnly := false
+	if v := c.Query("unread"); v != "" {
+		unreadOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unread flag"})
+			return
+		}
+	}
+
+	p, _ := middleware.PrincipalFromContext(c)
+	notifications, unread, err := h.notificationUsecase.ListNotifications(p.UserID, unreadOnly, page, size)
+	if err != nil {
+		h.logger.Errorf("ListNotifications failed for user %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":          page,
+		"size":          size,
+		"unread":        unread,
+		"notifications": notifications,
+	})
+}
+
+func (h *NotificationHandler) MarkReadHandler(c *gin.Context) {
+	notificationID, ok := parseIDParam(c, "notification")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.notificationUsecase.MarkRead(p.UserID, notificationID); err != nil {
+		if errors.Is(err, usecase.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+		h.logger.Errorf("MarkRead failed for notification %d: %v", notificationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllReadHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	n, err := h.notificationUsecase.MarkAllRead(p.UserID)
+	if err != nil {
+		h.logger.Errorf("MarkAllRead failed for user %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": n})
+}
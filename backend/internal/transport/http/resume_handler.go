@@ -0,0 +1,142 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// multipartOverhead leaves room for multipart boundaries and headers on top of
+// the file itself when capping the request body.
+const multipartOverhead = 1 << 20
+
+type ResumeHandler struct {
+	resumeUsecase *usecase.ResumeUsecase
+	maxBytes      int64
+	logger        *util.Logger
+}
+
+func NewResumeHandler(resumeUsecase *usecase.ResumeUsecase, maxBytes int64, logger *util.Logger) *ResumeHandler {
+	return &ResumeHandler{resumeUsecase: resumeUsecase, maxBytes: maxBytes, logger: logger}
+}
+
+func (h *ResumeHandler) UploadResumeHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+multipartOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Resume file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	makePrimary, _ := strconv.ParseBool(c.PostForm("primary"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.Errorf("Failed to open uploaded resume: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	defer file.Close()
+
+	resume, err := h.resumeUsecase.UploadResume(c.Request.Context(), p.UserID, fileHeader.Filename, fileHeader.Size, file, makePrimary)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrResumeTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrResumeEmpty), errors.Is(err, usecase.ErrResumeUnsupportedType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Errorf("UploadResume failed for student %d: %v", p.UserID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload resume"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, resume)
+}
+
+func (h *ResumeHandler) ListResumesHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	resumes, err := h.resumeUsecase.ListResumes(p.UserID)
+	if err != nil {
+		h.logger.Errorf("ListResumes failed for student %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load resumes"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"resumes": resumes})
+}
+
+func (h *ResumeHandler) SetPrimaryResumeHandler(c *gin.Context) {
+	resumeID, ok := parseIDParam(c, "resume")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.resumeUsecase.SetPrimaryResume(p.UserID, resumeID); err != nil {
+		if errors.Is(err, usecase.ErrResumeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+			return
+		}
+		h.logger.Errorf("SetPrimaryResume failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resume"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Primary resume updated"})
+}
+
+func (h *ResumeHandler) DeleteResumeHandler(c *gin.Context) {
+	resumeID, ok := parseIDParam(c, "resume")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.resumeUsecase.DeleteResume(c.Request.Context(), p.UserID, resumeID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrResumeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+		case errors.Is(err, usecase.ErrResumeInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Errorf("DeleteResume failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete resume"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ResumeHandler) DownloadResumeHandler(c *gin.Context) {
+	resumeID, ok := parseIDParam(c, "resume")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	resume, body, err := h.resumeUsecase.OpenResume(c.Request.Context(), p.UserID, p.UserType, resumeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrResumeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resume not found"})
+		case errors.Is(err, usecase.ErrResumeAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			h.logger.Errorf("OpenResume failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load resume"})
+		}
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, resume.SizeBytes, resume.ContentType, body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", resume.FileName),
+	})
+}
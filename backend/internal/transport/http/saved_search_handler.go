@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SavedSearchHandler struct {
+	savedSearchUsecase *usecase.SavedSearchUsecase
+	logger             *util.Logger
+}
+
+func NewSavedSearchHandler(savedSearchUsecase *usecase.SavedSearchUsecase, logger *util.Logger) *SavedSearchHandler {
+	return &SavedSearchHandler{savedSearchUsecase: savedSearchUsecase, logger: logger}
+}
+
+// savedSearchInput takes the filter in the same shape as the search query
+// parameters, e.g. {"name": "Go", "filter": {"keywords": "golang",
+// "salary_min": 1000, "currency": "USD"}, "ml_skills": "go sql",
+// "digest": "WEEKLY"}.
+type savedSearchInput struct {
+	Name     string                `json:"name"`
+	Filter   model.VacancyFilter   `json:"filter"`
+	MLSkills string                `json:"ml_skills"`
+	Digest   model.DigestFrequency `json:"digest"`
+}
+
+func (in savedSearchInput) savedSearch(studentID uint) model.SavedSearch {
+	return model.SavedSearch{
+		StudentID: studentID,
+		Name:      in.Name,
+		Filter:    in.Filter,
+		MLSkills:  in.MLSkills,
+		Digest:    in.Digest,
+	}
+}
+
+func (h *SavedSearchHandler) ListSavedSearchesHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	searches, err := h.savedSearchUsecase.ListSavedSearches(p.UserID)
+	if err != nil {
+		h.logger.Errorf("ListSavedSearches failed for student %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load saved searches"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"savedSearches": searches})
+}
+
+func (h *SavedSearchHandler) CreateSavedSearchHandler(c *gin.Context) {
+	var input savedSearchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid saved search input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	search := input.savedSearch(p.UserID)
+	if err := h.savedSearchUsecase.CreateSavedSearch(&search); err != nil {
+		h.writeSavedSearchError(c, "CreateSavedSearch", err)
+		return
+	}
+	c.JSON(http.StatusCreated, search)
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearchHandler(c *gin.Context) {
+	searchID, ok := parseIDParam(c, "saved search")
+	if !ok {
+		return
+	}
+	var input savedSearchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid saved search input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	search := input.savedSearch(p.UserID)
+	search.ID = searchID
+	if err := h.savedSearchUsecase.UpdateSavedSearch(&search); err != nil {
+		h.writeSavedSearchError(c, "UpdateSavedSearch", err)
+		return
+	}
+	c.JSON(http.StatusOK, search)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearchHandler(c *gin.Context) {
+	searchID, ok := parseIDParam(c, "saved search")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.savedSearchUsecase.DeleteSavedSearch(p.UserID, searchID); err != nil {
+		h.writeSavedSearchError(c, "DeleteSavedSearch", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SavedSearchHandler) writeSavedSearchError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSavedSearch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUnknownCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown currency"})
+	case errors.Is(err, usecase.ErrTooManySavedSearches):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrSavedSearchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save search"})
+	}
+}
@@ -1,20 +1,38 @@
 package http
 
 import (
+	"context"
 	"database/sql"
 	"github.com/chotamkz/career-track-backend/internal/config"
+	"github.com/chotamkz/career-track-backend/internal/currency"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/integration"
+	"github.com/chotamkz/career-track-backend/internal/integration/feed"
+	"github.com/chotamkz/career-track-backend/internal/integration/hh"
+	"github.com/chotamkz/career-track-backend/internal/mail"
 	"github.com/chotamkz/career-track-backend/internal/middleware"
 	"github.com/chotamkz/career-track-backend/internal/repository/postgres"
+	"github.com/chotamkz/career-track-backend/internal/scheduler"
+	"github.com/chotamkz/career-track-backend/internal/storage"
 	"github.com/chotamkz/career-track-backend/internal/usecase"
 	"github.com/chotamkz/career-track-backend/internal/util"
 	"github.com/gin-contrib/cors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func NewServer(cfg *config.Config, db *sql.DB, logger *util.Logger) *http.Server {
+// descriptionBackfillBatch is how many stored vacancy descriptions are
+// sanitized per query by the startup backfill.
+const descriptionBackfillBatch = 500
+
+// NewServer wires the API. Background jobs stop when ctx is cancelled and are
+// tracked by jobs, so the caller can wait for them on shutdown. The jobs hold
+// their advisory locks on connections from lockDB, so that the connections
+// they pin do not starve db, which the jobs themselves and requests query.
+func NewServer(ctx context.Context, jobs *sync.WaitGroup, cfg *config.Config, db, lockDB *sql.DB, fileStorage storage.Storage, mailer mail.Sender, logger *util.Logger) *http.Server {
 	//gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
@@ -29,58 +47,240 @@ func NewServer(cfg *config.Config, db *sql.DB, logger *util.Logger) *http.Server
 		MaxAge:           12 * time.Hour,
 	}))
 
+	auth := middleware.NewAuthenticator(cfg.JWTSecret)
+	router.Use(auth.Authenticate())
+
 	vacancyRepo := postgres.NewVacancyRepo(db)
 	skillRepo := postgres.NewSkillRepo(db)
 	employerRepo := postgres.NewEmployerRepo(db)
+	companyRepo := postgres.NewCompanyRepo(db)
+	companyClaimRepo := postgres.NewCompanyClaimRepo(db)
 	userRepo := postgres.NewUserRepo(db, logger)
 	hackathonRepo := postgres.NewHackathonRepo(db)
 	profileRepo := postgres.NewProfileRepo(db, logger)
+	appRepo := postgres.NewApplicationRepo(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepo(db)
+	resumeRepo := postgres.NewResumeRepo(db)
+	studentSkillRepo := postgres.NewStudentSkillRepo(db)
+	currencyRateRepo := postgres.NewCurrencyRateRepo(db)
+	vacancyVersionRepo := postgres.NewVacancyVersionRepo(db)
+	savedSearchRepo := postgres.NewSavedSearchRepo(db)
+	notificationRepo := postgres.NewNotificationRepo(db)
+	bookmarkRepo := postgres.NewBookmarkRepo(db)
+	syncRunRepo := postgres.NewSyncRunRepo(db)
+	hhSearchProfileRepo := postgres.NewHHSearchProfileRepo(db)
 
-	vacancyUsecase := usecase.NewVacancyUsecase(vacancyRepo, skillRepo, employerRepo, cfg.MLServiceURL)
-	appUsecase := usecase.NewApplicationUsecase(postgres.NewApplicationRepo(db), profileRepo, profileRepo, userRepo, vacancyRepo, logger)
+	currencyUsecase := usecase.NewCurrencyUsecase(currencyRateRepo, vacancyRepo, cfg.SalaryNetRatio, logger)
+	if err := currencyUsecase.Load(); err != nil {
+		logger.Errorf("Failed to load currency rates: %v", err)
+	}
+	if cfg.CurrencyRatesFile != "" {
+		if err := currencyUsecase.ImportRates(context.Background(), currency.NewFileRateProvider(cfg.CurrencyRatesFile)); err != nil {
+			logger.Errorf("Failed to import currency rates from %s: %v", cfg.CurrencyRatesFile, err)
+		}
+	}
+	vacancyHistoryUsecase := usecase.NewVacancyHistoryUsecase(vacancyVersionRepo, vacancyRepo, appRepo, logger)
+	vacancyUsecase := usecase.NewVacancyUsecase(vacancyRepo, skillRepo, companyRepo, currencyUsecase, vacancyHistoryUsecase, cfg.MLServiceURL, cfg.VacancyTTL, cfg.VacancyArchiveAfter)
+	currencyUsecase.OnRenormalize(vacancyUsecase.InvalidateCounts)
+	if err := currencyUsecase.EnsureNetRatio(); err != nil {
+		logger.Errorf("Failed to renormalize salaries for the configured net ratio: %v", err)
+	}
+	appUsecase := usecase.NewApplicationUsecase(appRepo, profileRepo, profileRepo, userRepo, vacancyRepo, resumeRepo, logger)
 	userUsecase := usecase.NewUserUsecase(postgres.NewUserRepo(db, logger))
-	employerProfileUsecase := usecase.NewEmployerProfileUsecase(profileRepo, employerRepo, logger)
-	authUsecase := usecase.NewAuthUsecase(userRepo, profileRepo, profileRepo, cfg)
+	employerProfileUsecase := usecase.NewEmployerProfileUsecase(profileRepo, employerRepo, companyRepo, logger)
+	authUsecase := usecase.NewAuthUsecase(userRepo, profileRepo, profileRepo, profileRepo, refreshTokenRepo, cfg)
+	if cfg.BootstrapAdminEmail != "" && cfg.BootstrapAdminPassword != "" {
+		created, err := authUsecase.BootstrapSuperAdmin(cfg.BootstrapAdminEmail, cfg.BootstrapAdminPassword)
+		switch {
+		case err != nil:
+			logger.Errorf("Failed to create the bootstrap super admin %s: %v", cfg.BootstrapAdminEmail, err)
+		case created:
+			logger.Infof("Created bootstrap super admin %s", cfg.BootstrapAdminEmail)
+		}
+	}
 	studentProfileUsecase := usecase.NewStudentProfileUsecase(profileRepo, logger)
 	hackathonUsecase := usecase.NewHackathonUsecase(hackathonRepo)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, profileRepo, appRepo, refreshTokenRepo, logger)
+	resumeUsecase := usecase.NewResumeUsecase(resumeRepo, fileStorage, cfg.ResumeMaxBytes, logger)
+	studentSkillUsecase := usecase.NewStudentSkillUsecase(studentSkillRepo, skillRepo)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo)
+	bookmarkUsecase := usecase.NewBookmarkUsecase(bookmarkRepo, vacancyRepo, hackathonRepo, notificationUsecase, cfg.BookmarkExpiryNotice, logger)
+	savedSearchUsecase := usecase.NewSavedSearchUsecase(savedSearchRepo, notificationRepo, userRepo, vacancyUsecase, notificationUsecase, mailer, cfg.AppURL, logger)
+	syncRunUsecase := usecase.NewSyncRunUsecase(syncRunRepo)
+	companyClaimUsecase := usecase.NewCompanyClaimUsecase(companyClaimRepo, companyRepo, notificationUsecase, mailer, cfg.AppURL, cfg.CompanyClaimTokenTTL, logger)
+	hhSearchProfileUsecase := usecase.NewHHSearchProfileUsecase(hhSearchProfileRepo)
+	if cfg.HHSearchProfilesFile != "" {
+		if _, err := hhSearchProfileUsecase.ImportFile(cfg.HHSearchProfilesFile); err != nil {
+			logger.Errorf("Failed to import HH search profiles from %s: %v", cfg.HHSearchProfilesFile, err)
+		}
+	}
 
-	vacancyHandler := NewVacancyHandler(vacancyUsecase, appUsecase, employerProfileUsecase, logger, cfg)
+	var sources []integration.VacancySource
+	if cfg.HHSyncEnabled {
+		hhClient, err := hh.NewClient(hh.ClientConfig{
+			BaseURL:       cfg.HHBaseURL,
+			UserAgent:     cfg.HHUserAgent,
+			Timeout:       cfg.HHTimeout,
+			RateLimit:     cfg.HHRateLimit,
+			MaxRetries:    cfg.HHMaxRetries,
+			DetailWorkers: cfg.HHDetailWorkers,
+		}, logger)
+		if err != nil {
+			logger.Errorf("HH sync disabled: %v", err)
+		} else {
+			sources = append(sources, hh.NewSource(hhClient, hhSearchProfileRepo, cfg.HHSyncPerPage, logger))
+		}
+	}
+	if cfg.VacancyFeedsFile != "" {
+		feeds, err := feed.LoadConfigs(cfg.VacancyFeedsFile, hh.SourceName)
+		if err != nil {
+			logger.Errorf("Vacancy feeds disabled: %v", err)
+		}
+		for _, feedCfg := range feeds {
+			sources = append(sources, feed.NewSource(feedCfg, logger))
+		}
+	}
+	vacancySchedulers := make(map[string]*scheduler.VacancyScheduler, len(sources))
+	for _, source := range sources {
+		if dir, ok := source.(integration.EmployerDirectory); ok {
+			companyClaimUsecase.AddEmployerDirectory(source.Name(), dir)
+		}
+		updater := usecase.NewVacancyUpdater(source, companyRepo, vacancyRepo, syncRunRepo, appRepo, vacancyUsecase, notificationUsecase,
+			cfg.SyncStaleAfter, cfg.SyncRecheckBatch, logger)
+		interval := cfg.HHSyncInterval
+		if s, ok := source.(integration.Scheduled); ok {
+			interval = s.Interval()
+		}
+		vacancySchedulers[source.Name()] = scheduler.NewVacancyScheduler(lockDB, updater, logger, interval)
+	}
+
+	vacancyHandler := NewVacancyHandler(vacancyUsecase, vacancyHistoryUsecase, appUsecase, employerProfileUsecase, logger, cfg)
 	employerProfileHandler := NewEmployerProfileHandler(employerProfileUsecase, userUsecase, logger)
 	authHandler := NewAuthHandler(authUsecase, logger)
 	studentProfileHandler := NewStudentProfileHandler(studentProfileUsecase, userUsecase, logger)
 	hackathonHandler := NewHackathonHandler(hackathonUsecase, logger)
 	applicationHandler := NewApplicationHandler(appUsecase, logger)
+	adminHandler := NewAdminHandler(adminUsecase, authUsecase, vacancyUsecase, hackathonUsecase, logger)
+	resumeHandler := NewResumeHandler(resumeUsecase, cfg.ResumeMaxBytes, logger)
+	studentSkillHandler := NewStudentSkillHandler(studentSkillUsecase, vacancyUsecase, logger)
+	currencyHandler := NewCurrencyHandler(currencyUsecase, cfg.CurrencyRatesFile, logger)
+	savedSearchHandler := NewSavedSearchHandler(savedSearchUsecase, logger)
+	notificationHandler := NewNotificationHandler(notificationUsecase, logger)
+	bookmarkHandler := NewBookmarkHandler(bookmarkUsecase, logger)
+	syncHandler := NewSyncHandler(syncRunUsecase, vacancySchedulers, logger)
+	companyClaimHandler := NewCompanyClaimHandler(companyClaimUsecase, logger)
+	hhSearchProfileHandler := NewHHSearchProfileHandler(hhSearchProfileUsecase, cfg.HHSearchProfilesFile, logger)
 
-	router.GET("/api/v1/vacancies", middleware.OptionalAuth(cfg.JWTSecret), vacancyHandler.ListVacanciesHandler)
-	router.GET("/api/v1/vacancies/:id", middleware.OptionalAuth(cfg.JWTSecret), vacancyHandler.DetailVacancyHandler)
+	router.GET("/api/v1/vacancies", vacancyHandler.ListVacanciesHandler)
+	router.GET("/api/v1/vacancies/:id", vacancyHandler.DetailVacancyHandler)
+	router.GET("/api/v1/vacancies/:id/history", vacancyHandler.VacancyHistoryHandler)
 	router.GET("/api/v1/vacancies/search", vacancyHandler.FilterVacanciesHandler)
-	router.POST("/api/v1/vacancies", middleware.RequireEmployer(cfg.JWTSecret), vacancyHandler.CreateVacancyHandler)
-	router.GET("/api/v1/employers/me/vacancies", middleware.RequireEmployer(cfg.JWTSecret), vacancyHandler.GetEmployerVacanciesHandler)
-	router.DELETE("/api/v1/vacancies/:id", middleware.RequireEmployer(cfg.JWTSecret), vacancyHandler.DeleteVacancyHandler)
-	router.PUT("/api/v1/vacancies/:id", middleware.RequireEmployer(cfg.JWTSecret), vacancyHandler.UpdateVacancyHandler)
+	router.GET("/api/v1/vacancies/search/facets", vacancyHandler.FacetsHandler)
+	router.POST("/api/v1/vacancies", auth.Require(model.UserTypeEmployer), vacancyHandler.CreateVacancyHandler)
+	router.GET("/api/v1/employers/me/vacancies", auth.Require(model.UserTypeEmployer), vacancyHandler.GetEmployerVacanciesHandler)
+	router.DELETE("/api/v1/vacancies/:id", auth.Require(model.UserTypeEmployer), vacancyHandler.DeleteVacancyHandler)
+	router.PUT("/api/v1/vacancies/:id", auth.Require(model.UserTypeEmployer), vacancyHandler.UpdateVacancyHandler)
+	router.PATCH("/api/v1/vacancies/:id/status", auth.Require(model.UserTypeEmployer), vacancyHandler.ChangeVacancyStatusHandler)
 	router.GET("/api/v1/vacancies/regions", vacancyHandler.GetRegionsHandler)
+	router.PUT("/api/v1/vacancies/:id/bookmark", auth.Require(model.UserTypeStudent), bookmarkHandler.BookmarkVacancyHandler)
+	router.DELETE("/api/v1/vacancies/:id/bookmark", auth.Require(model.UserTypeStudent), bookmarkHandler.RemoveVacancyBookmarkHandler)
 
 	router.POST("/api/v1/auth/register/student", authHandler.RegisterStudentHandler)
 	router.POST("/api/v1/auth/register/employer", authHandler.RegisterEmployerHandler)
 	router.POST("/api/v1/auth/login", authHandler.Login)
+	router.POST("/api/v1/auth/refresh", authHandler.Refresh)
+	router.POST("/api/v1/auth/logout", authHandler.Logout)
 
-	router.GET("/api/v1/employers/me", middleware.RequireEmployer(cfg.JWTSecret), employerProfileHandler.GetEmployerProfile)
-	router.PUT("/api/v1/employers/me", middleware.RequireEmployer(cfg.JWTSecret), employerProfileHandler.UpdateEmployerProfile)
-	router.POST("/api/v1/employers/profile", middleware.RequireEmployer(cfg.JWTSecret), employerProfileHandler.CreateEmployerProfileHandler)
+	router.GET("/api/v1/employers/me", auth.Require(model.UserTypeEmployer), employerProfileHandler.GetEmployerProfile)
+	router.PUT("/api/v1/employers/me", auth.Require(model.UserTypeEmployer), employerProfileHandler.UpdateEmployerProfile)
+	router.POST("/api/v1/employers/profile", auth.Require(model.UserTypeEmployer), employerProfileHandler.CreateEmployerProfileHandler)
 	router.GET("/api/v1/employers/names", employerProfileHandler.GetCompanyNames)
+	router.POST("/api/v1/companies/:id/claims", auth.Require(model.UserTypeEmployer), companyClaimHandler.RequestClaimHandler)
+	router.POST("/api/v1/company-claims/verify", auth.Require(model.UserTypeEmployer), companyClaimHandler.VerifyClaimHandler)
+	router.GET("/api/v1/employers/me/company-claims", auth.Require(model.UserTypeEmployer), companyClaimHandler.ListMyClaimsHandler)
 
-	router.GET("/api/v1/students/me", middleware.RequireStudent(cfg.JWTSecret), studentProfileHandler.GetStudentProfile)
-	router.PUT("/api/v1/students/me", middleware.RequireStudent(cfg.JWTSecret), studentProfileHandler.UpdateStudentProfile)
+	router.GET("/api/v1/students/me", auth.Require(model.UserTypeStudent), studentProfileHandler.GetStudentProfile)
+	router.PUT("/api/v1/students/me", auth.Require(model.UserTypeStudent), studentProfileHandler.UpdateStudentProfile)
 	router.POST("/api/v1/students/profile", studentProfileHandler.CreateStudentProfileHandler)
 
-	router.POST("/api/v1/hackathons", hackathonHandler.CreateHackathonHandler)
+	router.GET("/api/v1/students/me/resumes", auth.Require(model.UserTypeStudent), resumeHandler.ListResumesHandler)
+	router.POST("/api/v1/students/me/resumes", auth.Require(model.UserTypeStudent), resumeHandler.UploadResumeHandler)
+	router.PUT("/api/v1/students/me/resumes/:id/primary", auth.Require(model.UserTypeStudent), resumeHandler.SetPrimaryResumeHandler)
+	router.DELETE("/api/v1/students/me/resumes/:id", auth.Require(model.UserTypeStudent), resumeHandler.DeleteResumeHandler)
+	router.GET("/api/v1/students/me/skills", auth.Require(model.UserTypeStudent), studentSkillHandler.ListSkillsHandler)
+	router.POST("/api/v1/students/me/skills", auth.Require(model.UserTypeStudent), studentSkillHandler.AddSkillHandler)
+	router.PUT("/api/v1/students/me/skills/:id", auth.Require(model.UserTypeStudent), studentSkillHandler.UpdateSkillHandler)
+	router.DELETE("/api/v1/students/me/skills/:id", auth.Require(model.UserTypeStudent), studentSkillHandler.DeleteSkillHandler)
+	router.GET("/api/v1/students/me/recommendations", auth.Require(model.UserTypeStudent), studentSkillHandler.RecommendationsHandler)
+	router.GET("/api/v1/students/me/saved-searches", auth.Require(model.UserTypeStudent), savedSearchHandler.ListSavedSearchesHandler)
+	router.POST("/api/v1/students/me/saved-searches", auth.Require(model.UserTypeStudent), savedSearchHandler.CreateSavedSearchHandler)
+	router.PUT("/api/v1/students/me/saved-searches/:id", auth.Require(model.UserTypeStudent), savedSearchHandler.UpdateSavedSearchHandler)
+	router.DELETE("/api/v1/students/me/saved-searches/:id", auth.Require(model.UserTypeStudent), savedSearchHandler.DeleteSavedSearchHandler)
+	router.GET("/api/v1/students/me/bookmarks", auth.Require(model.UserTypeStudent), bookmarkHandler.ListBookmarksHandler)
+	router.GET("/api/v1/resumes/:id/download", auth.Require(model.UserTypeStudent, model.UserTypeEmployer), resumeHandler.DownloadResumeHandler)
+
 	router.GET("/api/v1/hackathons", hackathonHandler.GetHackathonsHandler)
+	// Formerly public; kept for existing clients, now for admins only like
+	// POST /admin/hackathons.
+	router.POST("/api/v1/hackathons", auth.RequireAdmin(), adminHandler.TrackAccess, adminHandler.CreateHackathonHandler)
 	router.GET("/api/v1/hackathons/:id", hackathonHandler.DetailHackathonHandler)
+	router.PUT("/api/v1/hackathons/:id/bookmark", auth.Require(model.UserTypeStudent), bookmarkHandler.BookmarkHackathonHandler)
+	router.DELETE("/api/v1/hackathons/:id/bookmark", auth.Require(model.UserTypeStudent), bookmarkHandler.RemoveHackathonBookmarkHandler)
+
+	router.POST("/api/v1/vacancies/:id/apply", auth.Require(model.UserTypeStudent), applicationHandler.SubmitApplicationHandler)
+	router.PATCH("/api/v1/vacancies/applications/:id", auth.Require(model.UserTypeEmployer), applicationHandler.UpdateApplicationStatusHandler)
+	router.GET("/api/v1/applications/me", auth.Require(model.UserTypeStudent), applicationHandler.GetStudentApplicationsHandler)
+	router.POST("/api/v1/applications/:id/withdraw", auth.Require(model.UserTypeStudent), applicationHandler.WithdrawApplicationHandler)
+	router.GET("/api/v1/applications/:id/history", auth.Require(model.UserTypeStudent, model.UserTypeEmployer), applicationHandler.GetApplicationHistoryHandler)
+	router.GET("/api/v1/vacancies/:id/applications", auth.Require(model.UserTypeEmployer), applicationHandler.GetApplicationsForVacancyHandler)
 
-	router.POST("/api/v1/vacancies/:id/apply", middleware.RequireStudent(cfg.JWTSecret), applicationHandler.SubmitApplicationHandler)
-	router.PATCH("/api/v1/vacancies/applications/:id", middleware.RequireEmployer(cfg.JWTSecret), applicationHandler.UpdateApplicationStatusHandler)
-	router.GET("/api/v1/applications/me", middleware.RequireStudent(cfg.JWTSecret), applicationHandler.GetStudentApplicationsHandler)
-	router.GET("/api/v1/vacancies/:id/applications", middleware.RequireEmployer(cfg.JWTSecret), applicationHandler.GetApplicationsForVacancyHandler)
+	router.GET("/api/v1/notifications", auth.Require(model.UserTypeStudent, model.UserTypeEmployer), notificationHandler.ListNotificationsHandler)
+	router.POST("/api/v1/notifications/:id/read", auth.Require(model.UserTypeStudent, model.UserTypeEmployer), notificationHandler.MarkReadHandler)
+	router.POST("/api/v1/notifications/read-all", auth.Require(model.UserTypeStudent, model.UserTypeEmployer), notificationHandler.MarkAllReadHandler)
+
+	admin := router.Group("/api/v1/admin", auth.RequireAdmin(), adminHandler.TrackAccess)
+	admin.GET("/users", adminHandler.ListUsersHandler)
+	admin.POST("/users/:id/ban", adminHandler.BanUserHandler)
+	admin.DELETE("/users/:id/ban", adminHandler.UnbanUserHandler)
+	admin.DELETE("/users/:id", auth.RequireAdmin(model.AccessLevelSuperAdmin), adminHandler.DeleteUserHandler)
+	admin.POST("/admins", auth.RequireAdmin(model.AccessLevelSuperAdmin), adminHandler.CreateAdminHandler)
+	admin.PUT("/vacancies/:id", adminHandler.UpdateVacancyHandler)
+	admin.DELETE("/vacancies/:id", adminHandler.DeleteVacancyHandler)
+	admin.POST("/hackathons", adminHandler.CreateHackathonHandler)
+	admin.PUT("/hackathons/:id", adminHandler.UpdateHackathonHandler)
+	admin.DELETE("/hackathons/:id", adminHandler.DeleteHackathonHandler)
+	admin.GET("/stats/applications", adminHandler.ApplicationStatsHandler)
+	admin.GET("/currency-rates", currencyHandler.ListRatesHandler)
+	admin.PUT("/currency-rates", currencyHandler.UpdateRatesHandler)
+	admin.POST("/currency-rates/reload", currencyHandler.ReloadRatesHandler)
+	admin.GET("/sync-runs", syncHandler.ListSyncRunsHandler)
+	admin.POST("/sync-runs/:source", syncHandler.TriggerSyncHandler)
+	admin.GET("/company-claims", companyClaimHandler.ListClaimsHandler)
+	admin.POST("/company-claims/:id/approve", companyClaimHandler.ApproveClaimHandler)
+	admin.POST("/company-claims/:id/deny", companyClaimHandler.DenyClaimHandler)
+	admin.GET("/hh-search-profiles", hhSearchProfileHandler.ListProfilesHandler)
+	admin.POST("/hh-search-profiles", hhSearchProfileHandler.CreateProfileHandler)
+	admin.POST("/hh-search-profiles/reload", hhSearchProfileHandler.ReloadProfilesHandler)
+	admin.PUT("/hh-search-profiles/:id", hhSearchProfileHandler.UpdateProfileHandler)
+	admin.DELETE("/hh-search-profiles/:id", hhSearchProfileHandler.DeleteProfileHandler)
+
+	// Imports compare descriptions with the stored ones, so rows written before
+	// sanitization are rewritten in the background before the first sync;
+	// otherwise it would record every one of them as changed.
+	backfilled := scheduler.StartOnce(ctx, jobs, lockDB, logger, "description backfill", func(ctx context.Context) error {
+		n, err := vacancyUsecase.SanitizeStoredDescriptions(ctx, descriptionBackfillBatch)
+		if n > 0 {
+			logger.Infof("Sanitized %d stored vacancy descriptions", n)
+		}
+		return err
+	})
+
+	scheduler.NewVacancyLifecycleScheduler(lockDB, vacancyUsecase, logger, cfg.VacancyLifecycleInterval).Start(ctx, jobs)
+	scheduler.NewSavedSearchScheduler(lockDB, savedSearchUsecase, logger, cfg.SavedSearchInterval).Start(ctx, jobs)
+	scheduler.NewBookmarkAlertScheduler(lockDB, bookmarkUsecase, logger, cfg.BookmarkAlertInterval).Start(ctx, jobs)
+	for _, vacancyScheduler := range vacancySchedulers {
+		vacancyScheduler.Start(ctx, jobs, backfilled)
+	}
 
 	return &http.Server{
 		Addr:    cfg.ServerAddress,
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type StudentSkillHandler struct {
+	skillUsecase   *usecase.StudentSkillUsecase
+	vacancyUsecase *usecase.VacancyUsecase
+	logger         *util.Logger
+}
+
+func NewStudentSkillHandler(skillUsecase *usecase.StudentSkillUsecase, vacancyUsecase *usecase.VacancyUsecase, logger *util.Logger) *StudentSkillHandler {
+	return &StudentSkillHandler{skillUsecase: skillUsecase, vacancyUsecase: vacancyUsecase, logger: logger}
+}
+
+type studentSkillInput struct {
+	ProficiencyLevel model.ProficiencyLevel `json:"proficiencyLevel"`
+	YearsExperience  float64                `json:"yearsExperience"`
+}
+
+func (h *StudentSkillHandler) ListSkillsHandler(c *gin.Context) {
+	p, _ := middleware.PrincipalFromContext(c)
+	skills, err := h.skillUsecase.ListSkills(p.UserID)
+	if err != nil {
+		h.logger.Errorf("ListSkills failed for student %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load skills"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"skills": skills})
+}
+
+func (h *StudentSkillHandler) AddSkillHandler(c *gin.Context) {
+	var input struct {
+		Name string `json:"name" binding:"required"`
+		studentSkillInput
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid student skill input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	skill, err := h.skillUsecase.AddSkill(p.UserID, input.Name, input.ProficiencyLevel, input.YearsExperience)
+	if err != nil {
+		h.writeSkillError(c, "AddSkill", err)
+		return
+	}
+	c.JSON(http.StatusCreated, skill)
+}
+
+func (h *StudentSkillHandler) UpdateSkillHandler(c *gin.Context) {
+	skillID, ok := parseIDParam(c, "skill")
+	if !ok {
+		return
+	}
+	var input studentSkillInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Errorf("Invalid student skill input: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	skill, err := h.skillUsecase.UpdateSkill(p.UserID, skillID, input.ProficiencyLevel, input.YearsExperience)
+	if err != nil {
+		h.writeSkillError(c, "UpdateSkill", err)
+		return
+	}
+	c.JSON(http.StatusOK, skill)
+}
+
+func (h *StudentSkillHandler) DeleteSkillHandler(c *gin.Context) {
+	skillID, ok := parseIDParam(c, "skill")
+	if !ok {
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+	if err := h.skillUsecase.RemoveSkill(p.UserID, skillID); err != nil {
+		h.writeSkillError(c, "RemoveSkill", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RecommendationsHandler runs the ML recommendation with the student's stored
+// skills; the usual search filters narrow the result.
+func (h *StudentSkillHandler) RecommendationsHandler(c *gin.Context) {
+	filter, ok := vacancyFilterFromQuery(c, h.logger)
+	if !ok {
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if err != nil || size < 1 || size > 100 {
+		size = 20
+	}
+
+	p, _ := middleware.PrincipalFromContext(c)
+	skills, err := h.skillUsecase.SkillsQuery(p.UserID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNoStudentSkills) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Errorf("SkillsQuery failed for student %d: %v", p.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load skills"})
+		return
+	}
+
+	recs, err := h.vacancyUsecase.GetRecommendedVacancies(filter, skills)
+	if err != nil {
+		h.logger.Errorf("Failed to get recommended vacancies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"page":      page,
+		"size":      size,
+		"vacancies": recs,
+	})
+}
+
+func (h *StudentSkillHandler) writeSkillError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidProficiency), errors.Is(err, usecase.ErrInvalidYears), errors.Is(err, usecase.ErrEmptySkillName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrStudentSkillMissing):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+	default:
+		h.logger.Errorf("%s failed: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skills"})
+	}
+}
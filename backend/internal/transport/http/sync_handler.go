@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/scheduler"
+	"github.com/chotamkz/career-track-backend/internal/usecase"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type SyncHandler struct {
+	syncRunUsecase *usecase.SyncRunUsecase
+	schedulers     map[string]*scheduler.VacancyScheduler
+	logger         *util.Logger
+}
+
+// NewSyncHandler takes the schedulers of the enabled sources by name.
+func NewSyncHandler(syncRunUsecase *usecase.SyncRunUsecase, schedulers map[string]*scheduler.VacancyScheduler, logger *util.Logger) *SyncHandler {
+	return &SyncHandler{syncRunUsecase: syncRunUsecase, schedulers: schedulers, logger: logger}
+}
+
+func (h *SyncHandler) ListSyncRunsHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+	runs, err := h.syncRunUsecase.ListSyncRuns(c.Query("source"), limit)
+	if err != nil {
+		h.logger.Errorf("ListSyncRuns failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sync runs"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"syncRuns": runs})
+}
+
+// TriggerSyncHandler starts an import from the source now; its outcome shows
+// up in the sync runs list. With ?full=true the source lists everything it
+// offers instead of what changed since its last sync.
+func (h *SyncHandler) TriggerSyncHandler(c *gin.Context) {
+	source := c.Param("source")
+	vacancyScheduler, ok := h.schedulers[source]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown or disabled vacancy source"})
+		return
+	}
+	full := false
+	if v := c.Query("full"); v != "" {
+		var err error
+		if full, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid full parameter"})
+			return
+		}
+	}
+	if err := vacancyScheduler.Trigger(full); err != nil {
+		if errors.Is(err, scheduler.ErrSyncInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, scheduler.ErrSyncNotReady) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Errorf("Failed to trigger %s sync: %v", source, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start sync"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"status": "started", "source": source, "fullResync": full})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/chotamkz/career-track-backend/internal/config"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/middleware"
 	"github.com/chotamkz/career-track-backend/internal/usecase"
 	"github.com/chotamkz/career-track-backend/internal/util"
 	"net/http"
@@ -16,15 +17,17 @@ import (
 
 type VacancyHandler struct {
 	vacancyUsecase    *usecase.VacancyUsecase
+	historyUsecase    *usecase.VacancyHistoryUsecase
 	logger            *util.Logger
 	cfg               *config.Config
 	appUsecase        *usecase.ApplicationUsecase
 	empProfileUsecase *usecase.EmployerProfileUsecase
 }
 
-func NewVacancyHandler(vacUsecase *usecase.VacancyUsecase, appUsecase *usecase.ApplicationUsecase, empProfileUsecase *usecase.EmployerProfileUsecase, logger *util.Logger, cfg *config.Config) *VacancyHandler {
+func NewVacancyHandler(vacUsecase *usecase.VacancyUsecase, historyUsecase *usecase.VacancyHistoryUsecase, appUsecase *usecase.ApplicationUsecase, empProfileUsecase *usecase.EmployerProfileUsecase, logger *util.Logger, cfg *config.Config) *VacancyHandler {
 	return &VacancyHandler{
 		vacancyUsecase:    vacUsecase,
+		historyUsecase:    historyUsecase,
 		appUsecase:        appUsecase,
 		empProfileUsecase: empProfileUsecase,
 		logger:            logger,
@@ -49,6 +52,26 @@ func (vh *VacancyHandler) ListVacanciesHandler(c *gin.Context) {
 		}
 	}
 
+	cursor, hasCursor, ok := cursorFromQuery(c)
+	if !ok {
+		return
+	}
+	if hasCursor {
+		result, err := vh.vacancyUsecase.ListVacanciesByCursor(cursor, size, studentID)
+		if err != nil {
+			vh.logger.Errorf("ListVacanciesByCursor failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vacancies"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"size":       size,
+			"vacancies":  result.Vacancies,
+			"nextCursor": result.NextCursor,
+			"prevCursor": result.PrevCursor,
+		})
+		return
+	}
+
 	vacancies, total, err := vh.vacancyUsecase.ListVacancies(page, size, studentID)
 	if err != nil {
 		vh.logger.Errorf("ListVacancies failed: %v", err)
@@ -56,14 +79,32 @@ func (vh *VacancyHandler) ListVacanciesHandler(c *gin.Context) {
 		return
 	}
 
+	result := model.NewVacancyPage(vacancies, page*size < total, page > 1)
 	c.JSON(http.StatusOK, gin.H{
 		"page":       page,
 		"size":       size,
 		"totalCount": total,
 		"vacancies":  vacancies,
+		"nextCursor": result.NextCursor,
+		"prevCursor": result.PrevCursor,
 	})
 }
 
+// cursorFromQuery reads the optional cursor parameter; ok is false when a
+// 400 response has been written.
+func cursorFromQuery(c *gin.Context) (cursor model.VacancyCursor, present, ok bool) {
+	raw := c.Query("cursor")
+	if raw == "" {
+		return model.VacancyCursor{}, false, true
+	}
+	cursor, err := model.DecodeVacancyCursor(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return model.VacancyCursor{}, false, false
+	}
+	return cursor, true, true
+}
+
 func (vh *VacancyHandler) DetailVacancyHandler(c *gin.Context) {
 	vidParam := c.Param("id")
 	vid64, err := strconv.ParseUint(vidParam, 10, 32)
@@ -73,17 +114,23 @@ func (vh *VacancyHandler) DetailVacancyHandler(c *gin.Context) {
 	}
 	vacancyID := uint(vid64)
 
-	var studentID *uint
-	if ut, exists := c.Get("userType"); exists && ut == string(model.UserTypeStudent) {
-		if uidVal, ok := c.Get("user"); ok {
-			if sid, ok2 := uidVal.(uint); ok2 {
-				studentID = &sid
-			}
+	var studentID, employerID *uint
+	if p, ok := middleware.PrincipalFromContext(c); ok {
+		uid := p.UserID
+		switch p.UserType {
+		case model.UserTypeStudent:
+			studentID = &uid
+		case model.UserTypeEmployer:
+			employerID = &uid
 		}
 	}
 
-	resp, err := vh.vacancyUsecase.GetVacancyWithDetails(vacancyID, studentID)
+	resp, err := vh.vacancyUsecase.GetVacancyWithDetails(vacancyID, studentID, employerID)
 	if err != nil {
+		if errors.Is(err, usecase.ErrVacancyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+			return
+		}
 		vh.logger.Errorf("Failed to get vacancy details %d: %v", vacancyID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vacancy details"})
 		return
@@ -92,23 +139,53 @@ func (vh *VacancyHandler) DetailVacancyHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-func (vh *VacancyHandler) FilterVacanciesHandler(c *gin.Context) {
+// vacancyFilterFromQuery reads the search filter shared by the search and
+// recommendation endpoints and writes a 400 response on invalid input.
+func vacancyFilterFromQuery(c *gin.Context, logger *util.Logger) (model.VacancyFilter, bool) {
 	filter := model.VacancyFilter{
 		Keywords:    c.Query("keywords"),
 		Region:      c.Query("region"),
 		Experience:  c.Query("experience"),
 		Schedule:    c.Query("schedule"),
 		CompanyName: c.Query("company_name"),
+		Sort:        model.VacancySort(c.Query("sort")),
+		Currency:    c.Query("currency"),
+	}
+	if !filter.Sort.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, expected relevance, date or salary"})
+		return filter, false
 	}
 	if salaryStr := c.Query("salary_from"); salaryStr != "" {
 		s, err := strconv.ParseFloat(salaryStr, 64)
 		if err != nil {
-			vh.logger.Errorf("Invalid salary_from: %v", err)
+			logger.Errorf("Invalid salary_from: %v", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid salary_from"})
-			return
+			return filter, false
 		}
 		filter.SalaryFrom = s
 	}
+	for param, dst := range map[string]*float64{"salary_min": &filter.SalaryMin, "salary_max": &filter.SalaryMax} {
+		if v := c.Query(param); v != "" {
+			s, err := strconv.ParseFloat(v, 64)
+			if err != nil || s < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return filter, false
+			}
+			*dst = s
+		}
+	}
+	if filter.SalaryMin > 0 && filter.SalaryMax > 0 && filter.SalaryMin > filter.SalaryMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "salary_min must not exceed salary_max"})
+		return filter, false
+	}
+	return filter, true
+}
+
+func (vh *VacancyHandler) FilterVacanciesHandler(c *gin.Context) {
+	filter, ok := vacancyFilterFromQuery(c, vh.logger)
+	if !ok {
+		return
+	}
 
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
@@ -119,10 +196,19 @@ func (vh *VacancyHandler) FilterVacanciesHandler(c *gin.Context) {
 		size = 20
 	}
 
+	cursor, hasCursor, ok := cursorFromQuery(c)
+	if !ok {
+		return
+	}
+
 	mlSkills := c.Query("ml_skills")
 	if mlSkills != "" {
 		recs, err := vh.vacancyUsecase.GetRecommendedVacancies(filter, mlSkills)
 		if err != nil {
+			if errors.Is(err, usecase.ErrUnknownCurrency) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown currency"})
+				return
+			}
 			vh.logger.Errorf("Failed to get recommended vacancies: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
 			return
@@ -135,35 +221,94 @@ func (vh *VacancyHandler) FilterVacanciesHandler(c *gin.Context) {
 		return
 	}
 
+	if hasCursor {
+		result, err := vh.vacancyUsecase.FilterVacanciesByCursor(filter, cursor, size)
+		if err != nil {
+			if errors.Is(err, usecase.ErrCursorUnsupportedSort) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrUnknownCurrency) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown currency"})
+				return
+			}
+			vh.logger.Errorf("Failed to filter vacancies by cursor: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to filter vacancies"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"size":       size,
+			"vacancies":  result.Vacancies,
+			"nextCursor": result.NextCursor,
+			"prevCursor": result.PrevCursor,
+		})
+		return
+	}
+
 	vacs, total, err := vh.vacancyUsecase.FilterVacancies(filter, page, size)
 	if err != nil {
+		if errors.Is(err, usecase.ErrUnknownCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown currency"})
+			return
+		}
 		vh.logger.Errorf("Failed to filter vacancies: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to filter vacancies"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"page":       page,
 		"size":       size,
 		"totalCount": total,
 		"vacancies":  vacs,
-	})
+	}
+	// Cursors only make sense for the date order; relevance and salary pages
+	// stay offset-based.
+	if filter.KeysetOrdered() {
+		result := model.NewVacancyPage(vacs, page*size < total, page > 1)
+		resp["nextCursor"] = result.NextCursor
+		resp["prevCursor"] = result.PrevCursor
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (vh *VacancyHandler) FacetsHandler(c *gin.Context) {
+	filter, ok := vacancyFilterFromQuery(c, vh.logger)
+	if !ok {
+		return
+	}
+	facets, err := vh.vacancyUsecase.GetVacancyFacets(filter)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUnknownCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown currency"})
+			return
+		}
+		vh.logger.Errorf("Failed to compute vacancy facets: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute facets"})
+		return
+	}
+	c.JSON(http.StatusOK, facets)
 }
 
 func (vh *VacancyHandler) CreateVacancyHandler(c *gin.Context) {
 	var input struct {
-		Title          string   `json:"title"`
-		Description    string   `json:"description"`
-		Requirements   string   `json:"requirements"`
-		Location       string   `json:"location"`
-		SalaryFrom     float64  `json:"salary_from"`
-		SalaryTo       float64  `json:"salary_to"`
-		SalaryCurrency string   `json:"salary_currency"`
-		SalaryGross    bool     `json:"salary_gross"`
-		VacancyURL     string   `json:"vacancy_url"`
-		WorkSchedule   string   `json:"work_schedule"`
-		Experience     string   `json:"experience"`
-		Skills         []string `json:"skills"`
+		Title             string   `json:"title"`
+		Description       string   `json:"description"`
+		DescriptionFormat string   `json:"description_format"`
+		Requirements      string   `json:"requirements"`
+		Location          string   `json:"location"`
+		SalaryFrom        float64  `json:"salary_from"`
+		SalaryTo          float64  `json:"salary_to"`
+		SalaryCurrency    string   `json:"salary_currency"`
+		SalaryGross       bool     `json:"salary_gross"`
+		VacancyURL        string   `json:"vacancy_url"`
+		WorkSchedule      string   `json:"work_schedule"`
+		Experience        string   `json:"experience"`
+		Skills            []string `json:"skills"`
+
+		Status    model.VacancyStatus `json:"status"`
+		PublishAt *time.Time          `json:"publish_at"`
+		ExpiresAt *time.Time          `json:"expires_at"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		vh.logger.Errorf("Invalid vacancy input: %v", err)
@@ -177,8 +322,11 @@ func (vh *VacancyHandler) CreateVacancyHandler(c *gin.Context) {
 	}
 
 	var vacancy model.Vacancy
+	if !setVacancyDescription(&vacancy, input.Description, input.DescriptionFormat) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown description format"})
+		return
+	}
 	vacancy.Title = input.Title
-	vacancy.Description = input.Description
 	vacancy.Requirements = input.Requirements
 	vacancy.Location = input.Location
 	vacancy.SalaryFrom = input.SalaryFrom
@@ -190,6 +338,10 @@ func (vh *VacancyHandler) CreateVacancyHandler(c *gin.Context) {
 	vacancy.Experience = input.Experience
 	vacancy.PostedDate = time.Now()
 	vacancy.CreatedAt = time.Now()
+	vacancy.Status = input.Status
+	vacancy.PublishAt = input.PublishAt
+	vacancy.ExpiresAt = input.ExpiresAt
+	vacancy.Skills = input.Skills
 
 	employerID, exists := c.Get("user")
 	if !exists {
@@ -200,20 +352,15 @@ func (vh *VacancyHandler) CreateVacancyHandler(c *gin.Context) {
 	vacancy.EmployerID = employerID.(uint)
 
 	if err := vh.vacancyUsecase.CreateVacancy(&vacancy); err != nil {
+		if errors.Is(err, usecase.ErrInvalidVacancyStatus) || errors.Is(err, usecase.ErrInvalidVacancySchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		vh.logger.Errorf("Failed to create vacancy: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vacancy"})
 		return
 	}
 
-	if len(input.Skills) > 0 {
-		if err := vh.vacancyUsecase.AddSkillsToVacancy(vacancy.ID, input.Skills); err != nil {
-			vh.logger.Errorf("Failed to add skills to vacancy: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Vacancy created, but failed to add skills"})
-			return
-		}
-		vacancy.Skills = input.Skills
-	}
-
 	c.JSON(http.StatusCreated, vacancy)
 }
 
@@ -234,9 +381,11 @@ func (vh *VacancyHandler) DeleteVacancyHandler(c *gin.Context) {
 
 	err = vh.vacancyUsecase.DeleteVacancy(employerID, vacancyID)
 	if err != nil {
-		switch err {
-		case usecase.ErrNotVacancyOwner:
+		switch {
+		case errors.Is(err, usecase.ErrNotVacancyOwner):
 			c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this vacancy"})
+		case errors.Is(err, usecase.ErrVacancyNotFound), errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vacancy"})
 			vh.logger.Errorf("DeleteVacancy failed: %v", err)
@@ -264,6 +413,62 @@ func (vh *VacancyHandler) GetEmployerVacanciesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"vacancies": vacs})
 }
 
+// Vacancy descriptions are HTML unless description_format says otherwise;
+// either way the usecase sanitizes them before they are stored.
+const (
+	descriptionFormatHTML     = "html"
+	descriptionFormatMarkdown = "markdown"
+)
+
+// setVacancyDescription puts description into the field of v matching format
+// and reports whether the format is known.
+func setVacancyDescription(v *model.Vacancy, description, format string) bool {
+	switch format {
+	case "", descriptionFormatHTML:
+		v.Description = description
+	case descriptionFormatMarkdown:
+		v.DescriptionMarkdown = description
+	default:
+		return false
+	}
+	return true
+}
+
+type vacancyUpdateInput struct {
+	Title             string   `json:"title" binding:"required"`
+	Description       string   `json:"description" binding:"required"`
+	DescriptionFormat string   `json:"description_format"`
+	Requirements      string   `json:"requirements"`
+	Location          string   `json:"location" binding:"required"`
+	SalaryFrom        float64  `json:"salary_from"`
+	SalaryTo          float64  `json:"salary_to"`
+	SalaryCurrency    string   `json:"salary_currency"`
+	SalaryGross       bool     `json:"salary_gross"`
+	VacancyURL        string   `json:"vacancy_url"`
+	WorkSchedule      string   `json:"work_schedule"`
+	Experience        string   `json:"experience"`
+	Skills            []string `json:"skills"`
+}
+
+// toVacancy builds the edited vacancy; ok is false when the description
+// format is unknown.
+func (in vacancyUpdateInput) toVacancy(id uint) (vac model.Vacancy, ok bool) {
+	vac = model.Vacancy{
+		ID:             id,
+		Title:          in.Title,
+		Requirements:   in.Requirements,
+		Location:       in.Location,
+		SalaryFrom:     in.SalaryFrom,
+		SalaryTo:       in.SalaryTo,
+		SalaryCurrency: in.SalaryCurrency,
+		SalaryGross:    in.SalaryGross,
+		VacancyURL:     sql.NullString{String: in.VacancyURL, Valid: in.VacancyURL != ""},
+		WorkSchedule:   in.WorkSchedule,
+		Experience:     in.Experience,
+	}
+	return vac, setVacancyDescription(&vac, in.Description, in.DescriptionFormat)
+}
+
 func (h *VacancyHandler) UpdateVacancyHandler(c *gin.Context) {
 	vid, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -278,45 +483,22 @@ func (h *VacancyHandler) UpdateVacancyHandler(c *gin.Context) {
 	}
 	employerID := empVal.(uint)
 
-	var in struct {
-		Title          string   `json:"title" binding:"required"`
-		Description    string   `json:"description" binding:"required"`
-		Requirements   string   `json:"requirements"`
-		Location       string   `json:"location" binding:"required"`
-		SalaryFrom     float64  `json:"salary_from"`
-		SalaryTo       float64  `json:"salary_to"`
-		SalaryCurrency string   `json:"salary_currency"`
-		SalaryGross    bool     `json:"salary_gross"`
-		VacancyURL     string   `json:"vacancy_url"`
-		WorkSchedule   string   `json:"work_schedule"`
-		Experience     string   `json:"experience"`
-		Skills         []string `json:"skills"`
-	}
+	var in vacancyUpdateInput
 	if err := c.ShouldBindJSON(&in); err != nil {
 		h.logger.Errorf("Invalid input for update vacancy: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
-
-	vac := model.Vacancy{
-		ID:             uint(vid),
-		Title:          in.Title,
-		Description:    in.Description,
-		Requirements:   in.Requirements,
-		Location:       in.Location,
-		SalaryFrom:     in.SalaryFrom,
-		SalaryTo:       in.SalaryTo,
-		SalaryCurrency: in.SalaryCurrency,
-		SalaryGross:    in.SalaryGross,
-		VacancyURL:     sql.NullString{String: in.VacancyURL, Valid: in.VacancyURL != ""},
-		WorkSchedule:   in.WorkSchedule,
-		Experience:     in.Experience,
+	vac, ok := in.toVacancy(uint(vid))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown description format"})
+		return
 	}
 
 	if err := h.vacancyUsecase.UpdateVacancy(employerID, &vac, in.Skills); err != nil {
 		if errors.Is(err, usecase.ErrNotVacancyOwner) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this vacancy"})
-		} else if err == sql.ErrNoRows {
+		} else if errors.Is(err, usecase.ErrVacancyNotFound) || errors.Is(err, sql.ErrNoRows) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
 		} else {
 			h.logger.Errorf("UpdateVacancy failed: %v", err)
@@ -328,6 +510,74 @@ func (h *VacancyHandler) UpdateVacancyHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, vac)
 }
 
+// ChangeVacancyStatusHandler publishes, pauses, closes or archives the
+// employer's vacancy, or reschedules a draft via publish_at.
+func (vh *VacancyHandler) ChangeVacancyStatusHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	var input struct {
+		Status    model.VacancyStatus `json:"status" binding:"required"`
+		PublishAt *time.Time          `json:"publish_at"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	p, _ := middleware.PrincipalFromContext(c)
+
+	vac, err := vh.vacancyUsecase.ChangeVacancyStatus(p.UserID, vacancyID, input.Status, input.PublishAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrVacancyNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+		case errors.Is(err, usecase.ErrNotVacancyOwner):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this vacancy"})
+		case errors.Is(err, usecase.ErrInvalidVacancyStatus), errors.Is(err, usecase.ErrInvalidVacancySchedule):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrInvalidVacancyTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			vh.logger.Errorf("ChangeVacancyStatus failed for vacancy %d: %v", vacancyID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change vacancy status"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, vac)
+}
+
+// VacancyHistoryHandler lists the versions of a vacancy with field-level
+// diffs between consecutive versions.
+func (vh *VacancyHandler) VacancyHistoryHandler(c *gin.Context) {
+	vacancyID, ok := parseIDParam(c, "vacancy")
+	if !ok {
+		return
+	}
+	var studentID, employerID *uint
+	if p, ok := middleware.PrincipalFromContext(c); ok {
+		uid := p.UserID
+		switch p.UserType {
+		case model.UserTypeStudent:
+			studentID = &uid
+		case model.UserTypeEmployer:
+			employerID = &uid
+		}
+	}
+
+	history, err := vh.historyUsecase.GetHistory(vacancyID, studentID, employerID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrVacancyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vacancy not found"})
+			return
+		}
+		vh.logger.Errorf("GetHistory failed for vacancy %d: %v", vacancyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vacancy history"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
 func (vh *VacancyHandler) GetRegionsHandler(c *gin.Context) {
 	regions, err := vh.vacancyUsecase.GetAllRegions()
 	if err != nil {
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/util"
+)
+
+const adminStatsTopVacancies = 10
+
+var (
+	ErrSelfModeration      = errors.New("administrators cannot moderate their own account")
+	ErrCannotModerateAdmin = errors.New("administrator accounts cannot be banned")
+)
+
+type AdminUsecase struct {
+	userRepo         repository.UserRepository
+	adminRepo        repository.AdminProfileRepository
+	appRepo          repository.ApplicationRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	logger           *util.Logger
+}
+
+func NewAdminUsecase(userRepo repository.UserRepository, adminRepo repository.AdminProfileRepository, appRepo repository.ApplicationRepository, refreshTokenRepo repository.RefreshTokenRepository, logger *util.Logger) *AdminUsecase {
+	return &AdminUsecase{
+		userRepo:         userRepo,
+		adminRepo:        adminRepo,
+		appRepo:          appRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		logger:           logger,
+	}
+}
+
+// TouchLastAccess records the admin request and returns the current admin
+// profile, which is the source of truth for the caller's access level.
+func (au *AdminUsecase) TouchLastAccess(adminID uint) (model.AdminProfile, error) {
+	profile, err := au.adminRepo.GetAdminProfile(adminID)
+	if err != nil {
+		return model.AdminProfile{}, err
+	}
+	if err := au.adminRepo.TouchLastAccess(adminID); err != nil {
+		au.logger.Errorf("Failed to update last_access for admin %d: %v", adminID, err)
+	}
+	return profile, nil
+}
+
+func (au *AdminUsecase) ListUsers(filter model.UserFilter, page, size int) ([]model.User, int, error) {
+	offset := (page - 1) * size
+	return au.userRepo.SearchUsers(filter, size, offset)
+}
+
+func (au *AdminUsecase) BanUser(adminID, userID uint, reason string) error {
+	if adminID == userID {
+		return ErrSelfModeration
+	}
+	user, err := au.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.UserType == model.UserTypeAdmin {
+		return ErrCannotModerateAdmin
+	}
+	if err := au.userRepo.SetBanned(userID, true, reason); err != nil {
+		return err
+	}
+	return au.refreshTokenRepo.RevokeAllForUser(userID)
+}
+
+func (au *AdminUsecase) UnbanUser(userID uint) error {
+	return au.userRepo.SetBanned(userID, false, "")
+}
+
+func (au *AdminUsecase) DeleteUser(adminID, userID uint) error {
+	if adminID == userID {
+		return ErrSelfModeration
+	}
+	return au.userRepo.DeleteUser(userID)
+}
+
+func (au *AdminUsecase) GetApplicationStats() (model.ApplicationStats, error) {
+	return au.appRepo.GetApplicationStats(adminStatsTopVacancies)
+}
@@ -10,8 +10,12 @@ import (
 )
 
 var (
-	ErrAlreadyApplied  = errors.New("you have already applied to this vacancy")
-	ErrNotVacancyOwner = errors.New("you do not own this vacancy")
+	ErrAlreadyApplied          = errors.New("you have already applied to this vacancy")
+	ErrNotVacancyOwner         = errors.New("you do not own this vacancy")
+	ErrInvalidStatus           = errors.New("unknown application status")
+	ErrInvalidStatusTransition = errors.New("application cannot move to this status")
+	ErrApplicationNotFound     = errors.New("application not found")
+	ErrVacancyNotOpen          = errors.New("vacancy is not accepting applications")
 )
 
 type ApplicationUsecase struct {
@@ -20,37 +24,187 @@ type ApplicationUsecase struct {
 	profileRepo  repository.StudentProfileRepository
 	employerRepo repository.EmployerProfileRepository
 	userRepo     repository.UserRepository
+	resumeRepo   repository.ResumeRepository
 	logger       *util.Logger
 }
 
-func NewApplicationUsecase(appRepo repository.ApplicationRepository, profileRepo repository.StudentProfileRepository, employerRepo repository.EmployerProfileRepository, userRepo repository.UserRepository, vacRepo repository.VacancyRepository, logger *util.Logger) *ApplicationUsecase {
+func NewApplicationUsecase(appRepo repository.ApplicationRepository, profileRepo repository.StudentProfileRepository, employerRepo repository.EmployerProfileRepository, userRepo repository.UserRepository, vacRepo repository.VacancyRepository, resumeRepo repository.ResumeRepository, logger *util.Logger) *ApplicationUsecase {
 	return &ApplicationUsecase{
 		appRepo:      appRepo,
 		vacancyRepo:  vacRepo,
 		profileRepo:  profileRepo,
 		employerRepo: employerRepo,
 		userRepo:     userRepo,
+		resumeRepo:   resumeRepo,
 		logger:       logger,
 	}
 }
 
 func (au *ApplicationUsecase) SubmitApplication(app *model.Application) error {
+	vac, err := au.vacancyRepo.GetVacancyById(app.VacancyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVacancyNotFound
+		}
+		return err
+	}
+	if vac.DeletedAt != nil || !vac.Status.Listed() {
+		return ErrVacancyNotOpen
+	}
+
 	prev, err := au.appRepo.GetByStudentAndVacancy(app.StudentID, app.VacancyID)
 	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
-	if err == nil && prev.Status != model.StatusRejected {
+	if err == nil && prev.Status != model.StatusRejected && prev.Status != model.StatusWithdrawn {
 		return ErrAlreadyApplied
 	}
 
+	resumeID, err := au.resolveResume(app.StudentID, app.ResumeID)
+	if err != nil {
+		return err
+	}
+	app.ResumeID = resumeID
+
 	app.Status = model.StatusApplied
 	app.SubmittedDate = time.Now()
 	app.UpdatedDate = time.Now()
 	return au.appRepo.CreateApplication(app)
 }
 
-func (au *ApplicationUsecase) ChangeApplicationStatus(appID uint, newStatus model.ApplicationStatus) error {
-	return au.appRepo.UpdateApplicationStatus(appID, newStatus)
+// resolveResume returns the resume to attach to a new application: the
+// requested one if it belongs to the student, otherwise the primary one.
+// A student without resumes applies without one.
+func (au *ApplicationUsecase) resolveResume(studentID uint, resumeID *uint) (*uint, error) {
+	if resumeID != nil {
+		resume, err := au.resumeRepo.GetResumeByID(*resumeID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrResumeNotFound
+			}
+			return nil, err
+		}
+		if resume.StudentID != studentID {
+			return nil, ErrResumeNotFound
+		}
+		return resumeID, nil
+	}
+	primary, err := au.resumeRepo.GetPrimaryResume(studentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &primary.ID, nil
+}
+
+// ChangeApplicationStatus moves an application along the status graph on
+// behalf of the employer owning its vacancy and records the step.
+func (au *ApplicationUsecase) ChangeApplicationStatus(employerID, appID uint, newStatus model.ApplicationStatus, note string) (model.ApplicationStatusChange, error) {
+	if !newStatus.Valid() {
+		return model.ApplicationStatusChange{}, ErrInvalidStatus
+	}
+	app, err := au.appRepo.GetApplicationByID(appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ApplicationStatusChange{}, ErrApplicationNotFound
+		}
+		return model.ApplicationStatusChange{}, err
+	}
+	vac, err := au.vacancyRepo.GetVacancyById(app.VacancyID)
+	if err != nil {
+		return model.ApplicationStatusChange{}, err
+	}
+	if vac.EmployerID != employerID {
+		return model.ApplicationStatusChange{}, ErrNotVacancyOwner
+	}
+	if !app.Status.CanTransitionTo(newStatus) {
+		return model.ApplicationStatusChange{}, ErrInvalidStatusTransition
+	}
+
+	from := app.Status
+	change := model.ApplicationStatusChange{
+		ApplicationID: app.ID,
+		FromStatus:    &from,
+		ToStatus:      newStatus,
+		ChangedBy:     &employerID,
+		ChangedByType: model.UserTypeEmployer,
+		Note:          note,
+		ChangedAt:     time.Now(),
+	}
+	if err := au.appRepo.ChangeStatus(&change); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return model.ApplicationStatusChange{}, ErrInvalidStatusTransition
+		}
+		return model.ApplicationStatusChange{}, err
+	}
+	return change, nil
+}
+
+// WithdrawApplication lets the student retract an application that is still
+// in progress.
+func (au *ApplicationUsecase) WithdrawApplication(studentID, appID uint, note string) (model.ApplicationStatusChange, error) {
+	app, err := au.appRepo.GetApplicationByID(appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ApplicationStatusChange{}, ErrApplicationNotFound
+		}
+		return model.ApplicationStatusChange{}, err
+	}
+	if app.StudentID != studentID {
+		return model.ApplicationStatusChange{}, ErrApplicationNotFound
+	}
+	if !app.Status.CanWithdraw() {
+		return model.ApplicationStatusChange{}, ErrInvalidStatusTransition
+	}
+
+	from := app.Status
+	change := model.ApplicationStatusChange{
+		ApplicationID: app.ID,
+		FromStatus:    &from,
+		ToStatus:      model.StatusWithdrawn,
+		ChangedBy:     &studentID,
+		ChangedByType: model.UserTypeStudent,
+		Note:          note,
+		ChangedAt:     time.Now(),
+	}
+	if err := au.appRepo.ChangeStatus(&change); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return model.ApplicationStatusChange{}, ErrInvalidStatusTransition
+		}
+		return model.ApplicationStatusChange{}, err
+	}
+	return change, nil
+}
+
+// GetApplicationHistory returns the status timeline to the student who applied
+// or to the employer owning the vacancy.
+func (au *ApplicationUsecase) GetApplicationHistory(userID uint, userType model.UserType, appID uint) ([]model.ApplicationStatusChange, error) {
+	app, err := au.appRepo.GetApplicationByID(appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	switch userType {
+	case model.UserTypeStudent:
+		if app.StudentID != userID {
+			return nil, ErrApplicationNotFound
+		}
+	case model.UserTypeEmployer:
+		vac, err := au.vacancyRepo.GetVacancyById(app.VacancyID)
+		if err != nil {
+			return nil, err
+		}
+		if vac.EmployerID != userID {
+			return nil, ErrNotVacancyOwner
+		}
+	default:
+		return nil, ErrApplicationNotFound
+	}
+	return au.appRepo.GetStatusHistory(appID)
 }
 
 func (au *ApplicationUsecase) GetStudentApplications(studentID uint) ([]model.ApplicationForStudent, error) {
@@ -96,6 +250,15 @@ func (au *ApplicationUsecase) GetApplicationsWithStudentProfiles(employerID, vac
 			Phone:        profile.Phone,
 			StudentEmail: user.Email,
 		}
+		if app.ResumeID != nil {
+			resume, err := au.resumeRepo.GetResumeByID(*app.ResumeID)
+			if err != nil {
+				au.logger.Errorf("cannot load resume %d: %v", *app.ResumeID, err)
+			} else {
+				item.ResumeURL = resume.FileURL
+				item.ResumeName = resume.FileName
+			}
+		}
 		result = append(result, item)
 	}
 	return result, nil
@@ -1,6 +1,11 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"github.com/chotamkz/career-track-backend/internal/config"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
@@ -13,11 +18,28 @@ import (
 
 var ErrAccountExists = errors.New("account already exists")
 var ErrInvalidCredentials = errors.New("invalid credentials")
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+var ErrAccountBanned = errors.New("account is banned")
+
+type AuthTokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
+
+// SessionMeta describes the client a refresh token is issued to.
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
 
 type AuthUsecase struct {
 	userRepo            repository.UserRepository
 	studentProfileRepo  repository.StudentProfileRepository
 	employerProfileRepo repository.EmployerProfileRepository
+	adminProfileRepo    repository.AdminProfileRepository
+	refreshTokenRepo    repository.RefreshTokenRepository
 	cfg                 *config.Config
 }
 
@@ -25,12 +47,16 @@ func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	studentRepo repository.StudentProfileRepository,
 	employerRepo repository.EmployerProfileRepository,
+	adminRepo repository.AdminProfileRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
 	cfg *config.Config,
 ) *AuthUsecase {
 	return &AuthUsecase{
 		userRepo:            userRepo,
 		studentProfileRepo:  studentRepo,
 		employerProfileRepo: employerRepo,
+		adminProfileRepo:    adminRepo,
+		refreshTokenRepo:    refreshTokenRepo,
 		cfg:                 cfg,
 	}
 }
@@ -81,25 +107,193 @@ func (a *AuthUsecase) RegisterEmployer(user *model.User, profile *model.Employer
 	return a.employerProfileRepo.CreateEmployerProfile(profile)
 }
 
-func (a *AuthUsecase) LoginUser(email, password string) (string, error) {
+func (a *AuthUsecase) RegisterAdmin(user *model.User, profile *model.AdminProfile) error {
+	if user.UserType != model.UserTypeAdmin {
+		return errors.New("invalid user type for admin registration")
+	}
+	if profile.AccessLevel != model.AccessLevelSuperAdmin && profile.AccessLevel != model.AccessLevelContentModerator {
+		return errors.New("invalid admin access level")
+	}
+	existingUser, err := a.userRepo.GetUserByEmail(user.Email)
+	if err == nil && existingUser.ID != 0 {
+		return ErrAccountExists
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+
+	if err := a.userRepo.CreateUser(user); err != nil {
+		return err
+	}
+	profile.UserID = user.ID
+	return a.adminProfileRepo.CreateAdminProfile(profile)
+}
+
+// BootstrapSuperAdmin creates a SUPER_ADMIN account with the given
+// credentials unless one exists already, so that a fresh deployment has an
+// admin who can create the others. It reports whether it created one.
+func (a *AuthUsecase) BootstrapSuperAdmin(email, password string) (bool, error) {
+	exists, err := a.adminProfileRepo.HasSuperAdmin()
+	if err != nil || exists {
+		return false, err
+	}
+	user := model.User{Email: email, Password: password, UserType: model.UserTypeAdmin}
+	profile := model.AdminProfile{AccessLevel: model.AccessLevelSuperAdmin}
+	if err := a.RegisterAdmin(&user, &profile); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *AuthUsecase) LoginUser(email, password string, meta SessionMeta) (AuthTokens, error) {
 	user, err := a.userRepo.GetUserByEmail(email)
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return AuthTokens{}, ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", ErrInvalidCredentials
+		return AuthTokens{}, ErrInvalidCredentials
+	}
+	if user.BannedAt != nil {
+		return AuthTokens{}, ErrAccountBanned
+	}
+
+	familyID, err := randomToken(16)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	refresh, rawRefresh, err := a.newRefreshToken(user.ID, familyID, meta)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if err := a.refreshTokenRepo.Create(&refresh); err != nil {
+		return AuthTokens{}, err
 	}
+	return a.issueTokens(user, familyID, rawRefresh)
+}
+
+// RefreshTokens exchanges a refresh token for a new access/refresh pair. The
+// presented token is revoked; presenting a token that was already rotated
+// is treated as theft and revokes the whole token family.
+func (a *AuthUsecase) RefreshTokens(rawRefresh string, meta SessionMeta) (AuthTokens, error) {
+	current, err := a.refreshTokenRepo.GetByHash(hashToken(rawRefresh))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuthTokens{}, ErrInvalidRefreshToken
+		}
+		return AuthTokens{}, err
+	}
+
+	if current.RevokedAt != nil {
+		if current.ReplacedBy != nil {
+			if err := a.refreshTokenRepo.RevokeFamily(current.FamilyID); err != nil {
+				return AuthTokens{}, err
+			}
+			return AuthTokens{}, ErrRefreshTokenReused
+		}
+		return AuthTokens{}, ErrInvalidRefreshToken
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	user, err := a.userRepo.GetByID(current.UserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if user.BannedAt != nil {
+		if err := a.refreshTokenRepo.RevokeAllForUser(user.ID); err != nil {
+			return AuthTokens{}, err
+		}
+		return AuthTokens{}, ErrAccountBanned
+	}
+
+	next, rawNext, err := a.newRefreshToken(user.ID, current.FamilyID, meta)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if err := a.refreshTokenRepo.Rotate(current.ID, &next); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenAlreadyRotated) {
+			if err := a.refreshTokenRepo.RevokeFamily(current.FamilyID); err != nil {
+				return AuthTokens{}, err
+			}
+			return AuthTokens{}, ErrRefreshTokenReused
+		}
+		return AuthTokens{}, err
+	}
+	return a.issueTokens(user, current.FamilyID, rawNext)
+}
+
+// Logout revokes the session the refresh token belongs to, or every session
+// of its user when allDevices is set.
+func (a *AuthUsecase) Logout(rawRefresh string, allDevices bool) error {
+	current, err := a.refreshTokenRepo.GetByHash(hashToken(rawRefresh))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	if allDevices {
+		return a.refreshTokenRepo.RevokeAllForUser(current.UserID)
+	}
+	return a.refreshTokenRepo.RevokeFamily(current.FamilyID)
+}
+
+func (a *AuthUsecase) issueTokens(user model.User, familyID, rawRefresh string) (AuthTokens, error) {
 	claims := jwt.MapClaims{
 		"user_id":  user.ID,
 		"email":    user.Email,
 		"userType": user.UserType,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(),
+		"sid":      familyID,
+		"exp":      time.Now().Add(a.cfg.AccessTokenTTL).Unix(),
+	}
+	if user.UserType == model.UserTypeAdmin {
+		profile, err := a.adminProfileRepo.GetAdminProfile(user.ID)
+		if err != nil {
+			return AuthTokens{}, err
+		}
+		claims["accessLevel"] = profile.AccessLevel
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(a.cfg.JWTSecret))
 	if err != nil {
+		return AuthTokens{}, err
+	}
+	return AuthTokens{
+		AccessToken:  tokenString,
+		RefreshToken: rawRefresh,
+		ExpiresIn:    int64(a.cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (a *AuthUsecase) newRefreshToken(userID uint, familyID string, meta SessionMeta) (model.RefreshToken, string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return model.RefreshToken{}, "", err
+	}
+	return model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(a.cfg.RefreshTokenTTL),
+		UserAgent: meta.UserAgent,
+		IPAddress: meta.IPAddress,
+	}, raw, nil
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	return tokenString, nil
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
 }
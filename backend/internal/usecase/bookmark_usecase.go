@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"time"
+)
+
+var ErrHackathonNotFound = errors.New("hackathon not found")
+
+// BookmarkUsecase lets students shortlist vacancies and hackathons and warns
+// them before a bookmarked vacancy expires and once it is closed.
+type BookmarkUsecase struct {
+	bookmarkRepo  repository.BookmarkRepository
+	vacancyRepo   repository.VacancyRepository
+	hackathonRepo repository.HackathonRepository
+	notifications *NotificationUsecase
+	expiryNotice  time.Duration
+	logger        *util.Logger
+}
+
+func NewBookmarkUsecase(bookmarkRepo repository.BookmarkRepository, vacancyRepo repository.VacancyRepository, hackathonRepo repository.HackathonRepository, notifications *NotificationUsecase, expiryNotice time.Duration, logger *util.Logger) *BookmarkUsecase {
+	return &BookmarkUsecase{
+		bookmarkRepo:  bookmarkRepo,
+		vacancyRepo:   vacancyRepo,
+		hackathonRepo: hackathonRepo,
+		notifications: notifications,
+		expiryNotice:  expiryNotice,
+		logger:        logger,
+	}
+}
+
+// BookmarkVacancy shortlists a published or paused vacancy; bookmarking one
+// twice is a no-op.
+func (bu *BookmarkUsecase) BookmarkVacancy(studentID, vacancyID uint) error {
+	vac, err := bu.vacancyRepo.GetVacancyById(vacancyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVacancyNotFound
+		}
+		return err
+	}
+	if vac.DeletedAt != nil || !vac.Status.Viewable() {
+		return ErrVacancyNotFound
+	}
+	if vac.Status != model.VacancyPublished && vac.Status != model.VacancyPaused {
+		return ErrVacancyNotOpen
+	}
+	return bu.bookmarkRepo.AddVacancyBookmark(studentID, vacancyID)
+}
+
+func (bu *BookmarkUsecase) RemoveVacancyBookmark(studentID, vacancyID uint) error {
+	return bu.bookmarkRepo.RemoveVacancyBookmark(studentID, vacancyID)
+}
+
+func (bu *BookmarkUsecase) BookmarkHackathon(studentID, hackathonID uint) error {
+	if _, err := bu.hackathonRepo.GetHackathonByID(hackathonID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrHackathonNotFound
+		}
+		return err
+	}
+	return bu.bookmarkRepo.AddHackathonBookmark(studentID, hackathonID)
+}
+
+func (bu *BookmarkUsecase) RemoveHackathonBookmark(studentID, hackathonID uint) error {
+	return bu.bookmarkRepo.RemoveHackathonBookmark(studentID, hackathonID)
+}
+
+func (bu *BookmarkUsecase) ListBookmarks(studentID uint) (model.Bookmarks, error) {
+	vacancies, err := bu.bookmarkRepo.GetBookmarkedVacancies(studentID)
+	if err != nil {
+		return model.Bookmarks{}, err
+	}
+	hackathons, err := bu.bookmarkRepo.GetBookmarkedHackathons(studentID)
+	if err != nil {
+		return model.Bookmarks{}, err
+	}
+	return model.Bookmarks{Vacancies: vacancies, Hackathons: hackathons}, nil
+}
+
+// RunBookmarkAlerts notifies bookmarkers of vacancies expiring within the
+// expiry notice and of vacancies that were closed. Each bookmark is marked
+// once its student is notified, so a failed notification is retried on the
+// next run.
+func (bu *BookmarkUsecase) RunBookmarkAlerts(now time.Time) (expiring, closed int, err error) {
+	if bu.expiryNotice > 0 {
+		alerts, err := bu.bookmarkRepo.GetExpiringVacancyBookmarks(now, now.Add(bu.expiryNotice))
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, a := range alerts {
+			if err := bu.notifyExpiring(a); err != nil {
+				bu.logger.Warnf("Expiry notice for vacancy %d to student %d failed: %v", a.VacancyID, a.StudentID, err)
+				continue
+			}
+			expiring++
+		}
+	}
+
+	alerts, err := bu.bookmarkRepo.GetClosedVacancyBookmarks()
+	if err != nil {
+		return expiring, 0, err
+	}
+	for _, a := range alerts {
+		if err := bu.notifyClosed(a, now); err != nil {
+			bu.logger.Warnf("Closure notice for vacancy %d to student %d failed: %v", a.VacancyID, a.StudentID, err)
+			continue
+		}
+		closed++
+	}
+	return expiring, closed, nil
+}
+
+func (bu *BookmarkUsecase) notifyExpiring(a model.VacancyBookmarkAlert) error {
+	vacancyID := a.VacancyID
+	_, err := bu.notifications.Notify(&model.Notification{
+		UserID:    a.StudentID,
+		Type:      model.NotificationBookmarkExpiring,
+		Title:     a.Title,
+		Body:      "A vacancy you bookmarked closes on " + a.ExpiresAt.Format("02.01.2006 15:04"),
+		VacancyID: &vacancyID,
+		DedupKey:  fmt.Sprintf("bookmark:vacancy:%d:expiring:%d", a.VacancyID, a.ExpiresAt.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	return bu.bookmarkRepo.MarkExpiryNotified(a.StudentID, a.VacancyID, *a.ExpiresAt)
+}
+
+func (bu *BookmarkUsecase) notifyClosed(a model.VacancyBookmarkAlert, now time.Time) error {
+	vacancyID := a.VacancyID
+	_, err := bu.notifications.Notify(&model.Notification{
+		UserID:    a.StudentID,
+		Type:      model.NotificationBookmarkClosed,
+		Title:     a.Title,
+		Body:      "A vacancy you bookmarked is no longer accepting applications",
+		VacancyID: &vacancyID,
+		DedupKey:  fmt.Sprintf("bookmark:vacancy:%d:closed:%d", a.VacancyID, a.PostedDate.Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	return bu.bookmarkRepo.MarkClosedNotified(a.StudentID, a.VacancyID, now)
+}
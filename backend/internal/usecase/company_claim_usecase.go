@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/integration"
+	"github.com/chotamkz/career-track-backend/internal/mail"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCompanyNotFound          = errors.New("company not found")
+	ErrCompanyClaimNotFound     = errors.New("company claim not found")
+	ErrInvalidCompanyClaim      = errors.New("invalid company claim")
+	ErrCompanyAlreadyClaimed    = errors.New("company already has an owner")
+	ErrCompanyClaimInReview     = errors.New("your claim to this company is already awaiting review")
+	ErrCompanyClaimStateChanged = errors.New("company claim is no longer open for this action")
+	ErrInvalidClaimToken        = errors.New("invalid or expired verification token")
+)
+
+const defaultClaimTokenTTL = 48 * time.Hour
+
+// freeMailDomains cannot prove that the sender works for a company.
+var freeMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "outlook.com": true,
+	"hotmail.com": true, "live.com": true, "icloud.com": true, "aol.com": true,
+	"proton.me": true, "protonmail.com": true, "gmx.com": true,
+	"mail.ru": true, "inbox.ru": true, "list.ru": true, "bk.ru": true,
+	"yandex.ru": true, "yandex.kz": true, "ya.ru": true, "rambler.ru": true,
+}
+
+// CompanyClaimUsecase lets employers take over companies imported from job
+// boards. The employer names an address at the company's domain and confirms
+// the token mailed there; an admin then approves or denies the claim. When
+// the company's website is known, the address must be at its domain.
+type CompanyClaimUsecase struct {
+	claimRepo     repository.CompanyClaimRepository
+	companyRepo   repository.CompanyRepository
+	notifications *NotificationUsecase
+	mailer        mail.Sender
+	directories   map[string]integration.EmployerDirectory
+	appURL        string
+	tokenTTL      time.Duration
+	logger        *util.Logger
+}
+
+func NewCompanyClaimUsecase(claimRepo repository.CompanyClaimRepository, companyRepo repository.CompanyRepository, notifications *NotificationUsecase, mailer mail.Sender, appURL string, tokenTTL time.Duration, logger *util.Logger) *CompanyClaimUsecase {
+	if tokenTTL <= 0 {
+		tokenTTL = defaultClaimTokenTTL
+	}
+	return &CompanyClaimUsecase{
+		claimRepo:     claimRepo,
+		companyRepo:   companyRepo,
+		notifications: notifications,
+		mailer:        mailer,
+		directories:   map[string]integration.EmployerDirectory{},
+		appURL:        strings.TrimRight(appURL, "/"),
+		tokenTTL:      tokenTTL,
+		logger:        logger,
+	}
+}
+
+// AddEmployerDirectory lets claims to the source's companies look up
+// websites the source's listings did not carry. It must be called before the
+// usecase is used.
+func (cu *CompanyClaimUsecase) AddEmployerDirectory(source string, dir integration.EmployerDirectory) {
+	cu.directories[source] = dir
+}
+
+// RequestClaim opens a claim, or renews the employer's pending one, and mails
+// the verification token to email.
+func (cu *CompanyClaimUsecase) RequestClaim(ctx context.Context, employerID, companyID uint, email string) (model.CompanyClaim, error) {
+	email, err := companyEmail(email)
+	if err != nil {
+		return model.CompanyClaim{}, err
+	}
+	company, err := cu.companyRepo.GetCompany(companyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CompanyClaim{}, ErrCompanyNotFound
+	}
+	if err != nil {
+		return model.CompanyClaim{}, err
+	}
+	if company.Source == "" {
+		return model.CompanyClaim{}, fmt.Errorf("%w: only imported companies can be claimed", ErrInvalidCompanyClaim)
+	}
+	if company.OwnerID != nil {
+		return model.CompanyClaim{}, ErrCompanyAlreadyClaimed
+	}
+	if website := cu.companyWebsite(ctx, company); website != "" && !domainMatches(email, website) {
+		return model.CompanyClaim{}, fmt.Errorf("%w: use an address at %s", ErrInvalidCompanyClaim, siteDomain(website))
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return model.CompanyClaim{}, err
+	}
+	expiresAt := time.Now().Add(cu.tokenTTL).UTC()
+	claim := model.CompanyClaim{
+		CompanyID:      companyID,
+		EmployerID:     employerID,
+		Email:          email,
+		TokenHash:      hashToken(token),
+		TokenExpiresAt: &expiresAt,
+	}
+	if err := cu.claimRepo.CreateCompanyClaim(&claim); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CompanyClaim{}, ErrCompanyClaimInReview
+		}
+		return model.CompanyClaim{}, err
+	}
+
+	if err := cu.mailer.Send(ctx, cu.verificationMessage(company, email, token, expiresAt)); err != nil {
+		return model.CompanyClaim{}, fmt.Errorf("send claim verification: %w", err)
+	}
+	return withDomainMatch(claim), nil
+}
+
+// companyWebsite returns the company's website, looking it up from the
+// company's source the first time. A failed lookup leaves it unknown: the
+// claim goes ahead and shows up for review without a domain match.
+func (cu *CompanyClaimUsecase) companyWebsite(ctx context.Context, company model.Company) string {
+	if company.Website != "" {
+		return company.Website
+	}
+	dir := cu.directories[company.Source]
+	if dir == nil {
+		return ""
+	}
+	website, err := dir.EmployerWebsite(ctx, company.ExternalID)
+	if err != nil {
+		cu.logger.Warnf("Failed to look up the website of %s company %s: %v", company.Source, company.ExternalID, err)
+		return ""
+	}
+	if siteDomain(website) == "" {
+		return ""
+	}
+	if err := cu.companyRepo.SetCompanyWebsite(company.ID, website); err != nil {
+		cu.logger.Errorf("Failed to store the website of company %d: %v", company.ID, err)
+	}
+	return website
+}
+
+func (cu *CompanyClaimUsecase) verificationMessage(company model.Company, to, token string, expiresAt time.Time) mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Someone asked to manage %s on CareerTrack and named this address to prove they work there.\n\n", company.Name)
+	fmt.Fprintf(&b, "To confirm, sign in as that employer and open:\n%s/employer/company-claims/verify?token=%s\n\n", cu.appURL, token)
+	fmt.Fprintf(&b, "The link expires on %s UTC. If you did not ask for this, ignore this email.\n", expiresAt.Format("2006-01-02 15:04"))
+	return mail.Message{
+		To:      to,
+		Subject: "Confirm your claim to " + company.Name,
+		Body:    b.String(),
+	}
+}
+
+// companyEmail normalizes email and rejects free mail providers.
+func companyEmail(email string) (string, error) {
+	addr, err := netmail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid email", ErrInvalidCompanyClaim)
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	if at < 0 {
+		return "", fmt.Errorf("%w: invalid email", ErrInvalidCompanyClaim)
+	}
+	domain := strings.ToLower(addr.Address[at+1:])
+	if freeMailDomains[domain] {
+		return "", fmt.Errorf("%w: use an address at the company's own domain", ErrInvalidCompanyClaim)
+	}
+	return addr.Address[:at+1] + domain, nil
+}
+
+// siteDomain returns the host of website without a leading "www.", or ""
+// when website is not a usable URL.
+func siteDomain(website string) string {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return ""
+	}
+	if !strings.Contains(website, "://") {
+		website = "http://" + website
+	}
+	u, err := url.Parse(website)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// domainMatches reports whether the normalized email is at the website's
+// domain or a subdomain of it, so jobs@hr.acme.kz fits acme.kz. A parent
+// domain does not match: anyone with a mailbox at hosting.kz could otherwise
+// claim a company whose site is acme.hosting.kz.
+func domainMatches(email, website string) bool {
+	site := siteDomain(website)
+	if site == "" {
+		return false
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	return domain == site || strings.HasSuffix(domain, "."+site)
+}
+
+func withDomainMatch(claim model.CompanyClaim) model.CompanyClaim {
+	claim.DomainMatch = domainMatches(claim.Email, claim.CompanyWebsite)
+	return claim
+}
+
+func withDomainMatches(claims []model.CompanyClaim) []model.CompanyClaim {
+	for i := range claims {
+		claims[i] = withDomainMatch(claims[i])
+	}
+	return claims
+}
+
+// VerifyClaim confirms the token of the employer's pending claim, which then
+// waits for an admin's review.
+func (cu *CompanyClaimUsecase) VerifyClaim(employerID uint, token string, now time.Time) (model.CompanyClaim, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return model.CompanyClaim{}, ErrInvalidClaimToken
+	}
+	claim, err := cu.claimRepo.GetCompanyClaimByToken(hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CompanyClaim{}, ErrInvalidClaimToken
+	}
+	if err != nil {
+		return model.CompanyClaim{}, err
+	}
+	if claim.EmployerID != employerID || claim.Status != model.CompanyClaimPending ||
+		claim.TokenExpiresAt == nil || now.After(*claim.TokenExpiresAt) {
+		return model.CompanyClaim{}, ErrInvalidClaimToken
+	}
+	if err := cu.claimRepo.VerifyCompanyClaim(claim.ID, now.UTC()); err != nil {
+		if errors.Is(err, repository.ErrCompanyClaimStatusChanged) {
+			return model.CompanyClaim{}, ErrInvalidClaimToken
+		}
+		return model.CompanyClaim{}, err
+	}
+	claim, err = cu.claimRepo.GetCompanyClaim(claim.ID)
+	if err != nil {
+		return model.CompanyClaim{}, err
+	}
+	return withDomainMatch(claim), nil
+}
+
+func (cu *CompanyClaimUsecase) ListEmployerClaims(employerID uint) ([]model.CompanyClaim, error) {
+	claims, err := cu.claimRepo.GetEmployerCompanyClaims(employerID)
+	if err != nil {
+		return nil, err
+	}
+	return withDomainMatches(claims), nil
+}
+
+// ListClaims lists claims with the status, all of them when status is empty.
+// Each claim says whether its email is at the company's domain, which the
+// reviewing admin should check before approving.
+func (cu *CompanyClaimUsecase) ListClaims(status model.CompanyClaimStatus, limit int) ([]model.CompanyClaim, error) {
+	if status != "" && !status.Valid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidCompanyClaim, status)
+	}
+	claims, err := cu.claimRepo.GetCompanyClaims(status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return withDomainMatches(claims), nil
+}
+
+// ApproveClaim makes the claimant the owner of the company and its
+// vacancies. Only verified claims can be approved.
+func (cu *CompanyClaimUsecase) ApproveClaim(adminID, claimID uint, note string, now time.Time) (model.CompanyClaim, error) {
+	claim, err := cu.claimRepo.ApproveCompanyClaim(claimID, adminID, strings.TrimSpace(note), now.UTC())
+	if err != nil {
+		return model.CompanyClaim{}, cu.reviewError(claimID, err)
+	}
+	cu.notifyReviewed(claim, model.NotificationCompanyClaimApproved,
+		"Your claim was approved; you can now manage the company's vacancies")
+	return withDomainMatch(claim), nil
+}
+
+func (cu *CompanyClaimUsecase) DenyClaim(adminID, claimID uint, note string, now time.Time) (model.CompanyClaim, error) {
+	claim, err := cu.claimRepo.DenyCompanyClaim(claimID, adminID, strings.TrimSpace(note), now.UTC())
+	if err != nil {
+		return model.CompanyClaim{}, cu.reviewError(claimID, err)
+	}
+	cu.notifyReviewed(claim, model.NotificationCompanyClaimDenied, "Your claim was denied")
+	return withDomainMatch(claim), nil
+}
+
+func (cu *CompanyClaimUsecase) reviewError(claimID uint, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCompanyAlreadyOwned):
+		return ErrCompanyAlreadyClaimed
+	case errors.Is(err, repository.ErrCompanyClaimStatusChanged):
+		if _, getErr := cu.claimRepo.GetCompanyClaim(claimID); errors.Is(getErr, sql.ErrNoRows) {
+			return ErrCompanyClaimNotFound
+		}
+		return ErrCompanyClaimStateChanged
+	}
+	return err
+}
+
+// notifyReviewed tells the claimant about the decision. The decision stands
+// even if the notification fails.
+func (cu *CompanyClaimUsecase) notifyReviewed(claim model.CompanyClaim, typ model.NotificationType, body string) {
+	if claim.ReviewNote != "" {
+		body += ": " + claim.ReviewNote
+	}
+	_, err := cu.notifications.Notify(&model.Notification{
+		UserID:   claim.EmployerID,
+		Type:     typ,
+		Title:    claim.CompanyName,
+		Body:     body,
+		DedupKey: fmt.Sprintf("company-claim:%d:%s", claim.ID, claim.Status),
+	})
+	if err != nil {
+		cu.logger.Errorf("Failed to notify employer %d about company claim %d: %v", claim.EmployerID, claim.ID, err)
+	}
+}
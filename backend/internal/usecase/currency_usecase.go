@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/currency"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidRate     = errors.New("currency rates must be positive")
+)
+
+// CurrencyUsecase keeps the current rates in memory and converts salaries to
+// and from net KZT, the unit salary filters and sorting work in.
+type CurrencyUsecase struct {
+	rateRepo    repository.CurrencyRateRepository
+	vacancyRepo repository.VacancyRepository
+	netRatio    float64
+	logger      *util.Logger
+
+	mu    sync.RWMutex
+	rates map[string]float64
+
+	onRenormalize func()
+}
+
+func NewCurrencyUsecase(rateRepo repository.CurrencyRateRepository, vacancyRepo repository.VacancyRepository, netRatio float64, logger *util.Logger) *CurrencyUsecase {
+	if netRatio <= 0 || netRatio > 1 {
+		netRatio = 1
+	}
+	return &CurrencyUsecase{
+		rateRepo:    rateRepo,
+		vacancyRepo: vacancyRepo,
+		netRatio:    netRatio,
+		logger:      logger,
+		rates:       map[string]float64{model.BaseCurrency: 1},
+	}
+}
+
+// Load replaces the in-memory rates with the ones stored in the database.
+func (cu *CurrencyUsecase) Load() error {
+	rates, err := cu.rateRepo.GetRates()
+	if err != nil {
+		return err
+	}
+	m := map[string]float64{model.BaseCurrency: 1}
+	for _, r := range rates {
+		m[r.Code] = r.RateToKZT
+	}
+	cu.mu.Lock()
+	cu.rates = m
+	cu.mu.Unlock()
+	return nil
+}
+
+// EnsureNetRatio renormalizes the stored salaries when they were computed
+// with a net ratio other than the configured one, e.g. after
+// SALARY_NET_RATIO changed. Rates are not touched.
+func (cu *CurrencyUsecase) EnsureNetRatio() error {
+	stored, ok, err := cu.vacancyRepo.GetSalaryNetRatio()
+	if err != nil {
+		return err
+	}
+	if ok && math.Abs(stored-cu.netRatio) < 1e-9 {
+		return nil
+	}
+	n, err := cu.vacancyRepo.RenormalizeSalaries(cu.netRatio)
+	if err != nil {
+		return err
+	}
+	if cu.onRenormalize != nil {
+		cu.onRenormalize()
+	}
+	cu.logger.Infof("Salary net ratio changed from %g to %g, renormalized %d vacancies", stored, cu.netRatio, n)
+	return nil
+}
+
+// OnRenormalize registers fn to run after the stored salaries were
+// recomputed, e.g. to drop anything cached from the old values. It must be
+// called before the usecase is in use.
+func (cu *CurrencyUsecase) OnRenormalize(fn func()) {
+	cu.onRenormalize = fn
+}
+
+func (cu *CurrencyUsecase) Rates() ([]model.CurrencyRate, error) {
+	return cu.rateRepo.GetRates()
+}
+
+// SetRates stores new rates and recomputes the normalized salary of every
+// vacancy so filters see the new values at once. Rates equal to the current
+// ones are a no-op, so re-importing the same file on startup rewrites
+// nothing; EnsureNetRatio covers a changed net ratio.
+func (cu *CurrencyUsecase) SetRates(rates []model.CurrencyRate) error {
+	for i := range rates {
+		rates[i].Code = strings.ToUpper(strings.TrimSpace(rates[i].Code))
+		if rates[i].Code == "" || rates[i].RateToKZT <= 0 || math.IsInf(rates[i].RateToKZT, 0) {
+			return ErrInvalidRate
+		}
+		if rates[i].Code == model.BaseCurrency && rates[i].RateToKZT != 1 {
+			return ErrInvalidRate
+		}
+	}
+	if cu.unchanged(rates) {
+		cu.logger.Infof("Currency rates unchanged (%d), salaries left as they are", len(rates))
+		return nil
+	}
+	if err := cu.rateRepo.UpsertRates(rates); err != nil {
+		return err
+	}
+	if err := cu.Load(); err != nil {
+		return err
+	}
+	n, err := cu.vacancyRepo.RenormalizeSalaries(cu.netRatio)
+	if err != nil {
+		return err
+	}
+	if cu.onRenormalize != nil {
+		cu.onRenormalize()
+	}
+	cu.logger.Infof("Currency rates updated (%d), renormalized %d vacancies", len(rates), n)
+	return nil
+}
+
+// unchanged reports whether every rate already has the given value.
+func (cu *CurrencyUsecase) unchanged(rates []model.CurrencyRate) bool {
+	cu.mu.RLock()
+	defer cu.mu.RUnlock()
+	for _, r := range rates {
+		if current, ok := cu.rates[r.Code]; !ok || current != r.RateToKZT {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportRates pulls rates from the provider and applies them like SetRates.
+func (cu *CurrencyUsecase) ImportRates(ctx context.Context, provider currency.RateProvider) error {
+	rates, err := provider.Rates(ctx)
+	if err != nil {
+		return fmt.Errorf("ImportRates: %w", err)
+	}
+	return cu.SetRates(rates)
+}
+
+// Known reports whether a rate exists for the currency; empty means KZT.
+func (cu *CurrencyUsecase) Known(code string) bool {
+	_, ok := cu.rate(code)
+	return ok
+}
+
+// ToKZT converts an amount in the given currency to KZT.
+func (cu *CurrencyUsecase) ToKZT(amount float64, code string) (float64, error) {
+	rate, ok := cu.rate(code)
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return amount * rate, nil
+}
+
+// Normalize fills the net-KZT salary fields of v. Gross amounts are reduced
+// by the configured net ratio; a salary in an unknown currency is left
+// unnormalized and so drops out of salary filters. The repository's
+// RenormalizeSalaries uses the same formula.
+func (cu *CurrencyUsecase) Normalize(v *model.Vacancy) {
+	v.SalaryFromKZT, v.SalaryToKZT = nil, nil
+	rate, ok := cu.rate(v.SalaryCurrency)
+	if !ok {
+		return
+	}
+	ratio := 1.0
+	if v.SalaryGross {
+		ratio = cu.netRatio
+	}
+	if v.SalaryFrom > 0 {
+		from := v.SalaryFrom * rate * ratio
+		v.SalaryFromKZT = &from
+	}
+	if v.SalaryTo > 0 {
+		to := v.SalaryTo * rate * ratio
+		v.SalaryToKZT = &to
+	}
+}
+
+// ApplyDisplay sets SalaryDisplay on each vacancy to its net salary in the
+// requested currency. Nothing is done when no currency was requested.
+func (cu *CurrencyUsecase) ApplyDisplay(vacs []model.Vacancy, code string) {
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+	rate, ok := cu.rate(code)
+	if !ok {
+		return
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for i := range vacs {
+		v := &vacs[i]
+		if v.SalaryFromKZT == nil && v.SalaryToKZT == nil {
+			continue
+		}
+		d := &model.SalaryDisplay{Currency: code}
+		if v.SalaryFromKZT != nil {
+			from := math.Round(*v.SalaryFromKZT / rate)
+			d.From = &from
+		}
+		if v.SalaryToKZT != nil {
+			to := math.Round(*v.SalaryToKZT / rate)
+			d.To = &to
+		}
+		v.SalaryDisplay = d
+	}
+}
+
+func (cu *CurrencyUsecase) rate(code string) (float64, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = model.BaseCurrency
+	}
+	cu.mu.RLock()
+	defer cu.mu.RUnlock()
+	rate, ok := cu.rates[code]
+	return rate, ok
+}
@@ -7,20 +7,26 @@ import (
 )
 
 type EmployerProfileUsecase struct {
-	repo    repository.EmployerProfileRepository
-	empRepo repository.EmployerRepository
+	repo        repository.EmployerProfileRepository
+	empRepo     repository.EmployerRepository
+	companyRepo repository.CompanyRepository
 }
 
-func NewEmployerProfileUsecase(repo repository.EmployerProfileRepository, empRepo repository.EmployerRepository, logger *util.Logger) *EmployerProfileUsecase {
-	return &EmployerProfileUsecase{repo: repo, empRepo: empRepo}
+func NewEmployerProfileUsecase(repo repository.EmployerProfileRepository, empRepo repository.EmployerRepository, companyRepo repository.CompanyRepository, logger *util.Logger) *EmployerProfileUsecase {
+	return &EmployerProfileUsecase{repo: repo, empRepo: empRepo, companyRepo: companyRepo}
 }
 
 func (epu *EmployerProfileUsecase) GetProfile(userID uint) (model.EmployerProfile, error) {
 	return epu.repo.GetEmployerProfile(userID)
 }
 
+// UpdateProfile also renames the employer's own company, which is what
+// students see on the employer's vacancies.
 func (epu *EmployerProfileUsecase) UpdateProfile(profile *model.EmployerProfile) error {
-	return epu.repo.UpdateEmployerProfile(profile)
+	if err := epu.repo.UpdateEmployerProfile(profile); err != nil {
+		return err
+	}
+	return epu.companyRepo.UpdateEmployerCompany(profile.UserID, profile.CompanyName, profile.CompanyDescription)
 }
 
 func (epu *EmployerProfileUsecase) CreateProfile(profile *model.EmployerProfile) error {
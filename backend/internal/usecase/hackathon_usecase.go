@@ -24,3 +24,11 @@ func (hu *HackathonUsecase) GetHackathons() ([]model.Hackathon, error) {
 func (hu *HackathonUsecase) GetHackathonByID(id uint) (model.Hackathon, error) {
 	return hu.hackRepo.GetHackathonByID(id)
 }
+
+func (hu *HackathonUsecase) UpdateHackathon(h *model.Hackathon) error {
+	return hu.hackRepo.UpdateHackathon(h)
+}
+
+func (hu *HackathonUsecase) DeleteHackathon(id uint) error {
+	return hu.hackRepo.DeleteHackathon(id)
+}
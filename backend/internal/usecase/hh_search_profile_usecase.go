@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/integration/hh"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxHHSearchProfileNameLen = 100
+
+var (
+	ErrHHSearchProfileNotFound = errors.New("HH search profile not found")
+	ErrHHSearchProfileExists   = errors.New("an HH search profile with this name already exists")
+	ErrInvalidHHSearchProfile  = errors.New("invalid HH search profile")
+)
+
+// HHSearchProfileUsecase manages the HH.ru searches the vacancy importer runs.
+type HHSearchProfileUsecase struct {
+	profileRepo repository.HHSearchProfileRepository
+}
+
+func NewHHSearchProfileUsecase(profileRepo repository.HHSearchProfileRepository) *HHSearchProfileUsecase {
+	return &HHSearchProfileUsecase{profileRepo: profileRepo}
+}
+
+func (pu *HHSearchProfileUsecase) ListProfiles() ([]model.HHSearchProfile, error) {
+	return pu.profileRepo.GetHHSearchProfiles(false)
+}
+
+func (pu *HHSearchProfileUsecase) CreateProfile(p *model.HHSearchProfile) error {
+	if err := validateHHSearchProfile(p); err != nil {
+		return err
+	}
+	if err := pu.ensureNameFree(p.Name, 0); err != nil {
+		return err
+	}
+	return pu.profileRepo.CreateHHSearchProfile(p)
+}
+
+func (pu *HHSearchProfileUsecase) UpdateProfile(p *model.HHSearchProfile) error {
+	if err := validateHHSearchProfile(p); err != nil {
+		return err
+	}
+	if err := pu.ensureNameFree(p.Name, p.ID); err != nil {
+		return err
+	}
+	err := pu.profileRepo.UpdateHHSearchProfile(p)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrHHSearchProfileNotFound
+	}
+	return err
+}
+
+func (pu *HHSearchProfileUsecase) DeleteProfile(id uint) error {
+	err := pu.profileRepo.DeleteHHSearchProfile(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrHHSearchProfileNotFound
+	}
+	return err
+}
+
+// ImportFile upserts the profiles of a profiles file by name. Profiles that
+// are not in the file are left alone. Nothing is written when any profile in
+// the file is invalid.
+func (pu *HHSearchProfileUsecase) ImportFile(path string) (int, error) {
+	profiles, err := hh.LoadSearchProfiles(path)
+	if err != nil {
+		return 0, err
+	}
+	for i := range profiles {
+		if err := validateHHSearchProfile(&profiles[i]); err != nil {
+			return 0, fmt.Errorf("profile %d of %s: %w", i+1, path, err)
+		}
+	}
+	for i := range profiles {
+		if err := pu.profileRepo.UpsertHHSearchProfile(&profiles[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(profiles), nil
+}
+
+func (pu *HHSearchProfileUsecase) ensureNameFree(name string, id uint) error {
+	existing, err := pu.profileRepo.GetHHSearchProfileByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return ErrHHSearchProfileExists
+	}
+	return nil
+}
+
+// validateHHSearchProfile normalizes p and rejects searches HH would refuse
+// or that are too broad to import.
+func validateHHSearchProfile(p *model.HHSearchProfile) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Text = strings.TrimSpace(p.Text)
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidHHSearchProfile)
+	}
+	if utf8.RuneCountInString(p.Name) > maxHHSearchProfileNameLen {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidHHSearchProfile, maxHHSearchProfileNameLen)
+	}
+	var err error
+	if p.Areas, err = normalizeHHIDs(p.Areas); err != nil {
+		return fmt.Errorf("%w: areas: %v", ErrInvalidHHSearchProfile, err)
+	}
+	if p.ProfessionalRoles, err = normalizeHHIDs(p.ProfessionalRoles); err != nil {
+		return fmt.Errorf("%w: professional_roles: %v", ErrInvalidHHSearchProfile, err)
+	}
+	if len(p.Areas) == 0 && len(p.ProfessionalRoles) == 0 && p.Text == "" {
+		return fmt.Errorf("%w: set at least one of areas, professional_roles or text", ErrInvalidHHSearchProfile)
+	}
+	if p.PeriodDays < 0 || p.PeriodDays > model.MaxHHSearchPeriodDays {
+		return fmt.Errorf("%w: period_days must be between 0 and %d", ErrInvalidHHSearchProfile, model.MaxHHSearchPeriodDays)
+	}
+	return nil
+}
+
+// normalizeHHIDs trims and deduplicates HH dictionary IDs, which are numeric.
+func normalizeHHIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+			return nil, fmt.Errorf("%q is not an HH id", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationUsecase struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationUsecase(notificationRepo repository.NotificationRepository) *NotificationUsecase {
+	return &NotificationUsecase{notificationRepo: notificationRepo}
+}
+
+// Notify stores an in-app notification. It reports false when n carries a
+// dedup key the user has already been notified with.
+func (nu *NotificationUsecase) Notify(n *model.Notification) (bool, error) {
+	return nu.notificationRepo.CreateNotification(n)
+}
+
+// ListNotifications returns a page of the user's notifications, newest first,
+// together with the number of unread ones.
+func (nu *NotificationUsecase) ListNotifications(userID uint, unreadOnly bool, page, size int) ([]model.Notification, int, error) {
+	notifications, err := nu.notificationRepo.GetNotifications(userID, unreadOnly, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := nu.notificationRepo.CountUnreadNotifications(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (nu *NotificationUsecase) MarkRead(userID, notificationID uint) error {
+	err := nu.notificationRepo.MarkNotificationRead(userID, notificationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotificationNotFound
+	}
+	return err
+}
+
+func (nu *NotificationUsecase) MarkAllRead(userID uint) (int64, error) {
+	return nu.notificationRepo.MarkAllNotificationsRead(userID)
+}
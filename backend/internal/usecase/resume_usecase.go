@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/storage"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"github.com/gabriel-vasile/mimetype"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	mimePDF  = "application/pdf"
+	mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// allowedResumeTypes maps accepted file extensions to the MIME type the
+// content itself must be detected as.
+var allowedResumeTypes = map[string]string{
+	".pdf":  mimePDF,
+	".docx": mimeDOCX,
+}
+
+var (
+	ErrResumeTooLarge        = errors.New("resume file is too large")
+	ErrResumeEmpty           = errors.New("resume file is empty")
+	ErrResumeUnsupportedType = errors.New("only PDF and DOCX resumes are supported")
+	ErrResumeNotFound        = errors.New("resume not found")
+	ErrResumeInUse           = errors.New("resume is attached to an application")
+	ErrResumeAccessDenied    = errors.New("you do not have access to this resume")
+)
+
+type ResumeUsecase struct {
+	resumeRepo repository.ResumeRepository
+	storage    storage.Storage
+	maxBytes   int64
+	logger     *util.Logger
+}
+
+func NewResumeUsecase(resumeRepo repository.ResumeRepository, fileStorage storage.Storage, maxBytes int64, logger *util.Logger) *ResumeUsecase {
+	return &ResumeUsecase{
+		resumeRepo: resumeRepo,
+		storage:    fileStorage,
+		maxBytes:   maxBytes,
+		logger:     logger,
+	}
+}
+
+// UploadResume validates the file by extension and by sniffed content, stores
+// it and records a new resume version for the student.
+func (ru *ResumeUsecase) UploadResume(ctx context.Context, studentID uint, fileName string, size int64, file io.ReadSeeker, makePrimary bool) (model.Resume, error) {
+	if size <= 0 {
+		return model.Resume{}, ErrResumeEmpty
+	}
+	if size > ru.maxBytes {
+		return model.Resume{}, ErrResumeTooLarge
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	expected, ok := allowedResumeTypes[ext]
+	if !ok {
+		return model.Resume{}, ErrResumeUnsupportedType
+	}
+	detected, err := mimetype.DetectReader(file)
+	if err != nil {
+		return model.Resume{}, fmt.Errorf("UploadResume detect: %w", err)
+	}
+	if !detected.Is(expected) {
+		return model.Resume{}, ErrResumeUnsupportedType
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return model.Resume{}, fmt.Errorf("UploadResume seek: %w", err)
+	}
+
+	suffix, err := randomToken(8)
+	if err != nil {
+		return model.Resume{}, err
+	}
+	key := fmt.Sprintf("resumes/%d/%s%s", studentID, suffix, ext)
+	if err := ru.storage.Put(ctx, key, file, size, expected); err != nil {
+		return model.Resume{}, fmt.Errorf("UploadResume store: %w", err)
+	}
+
+	resume := model.Resume{
+		StudentID:   studentID,
+		FileName:    filepath.Base(fileName),
+		StorageKey:  key,
+		ContentType: expected,
+		SizeBytes:   size,
+		IsPrimary:   makePrimary,
+	}
+	if err := ru.resumeRepo.CreateResume(&resume); err != nil {
+		if delErr := ru.storage.Delete(ctx, key); delErr != nil {
+			ru.logger.Errorf("Failed to remove orphaned resume object %s: %v", key, delErr)
+		}
+		return model.Resume{}, err
+	}
+	return resume, nil
+}
+
+func (ru *ResumeUsecase) ListResumes(studentID uint) ([]model.Resume, error) {
+	return ru.resumeRepo.GetResumesByStudentID(studentID)
+}
+
+func (ru *ResumeUsecase) SetPrimaryResume(studentID, resumeID uint) error {
+	if err := ru.resumeRepo.SetPrimary(studentID, resumeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrResumeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteResume removes a resume that has never been sent with an application;
+// attached resumes stay so employers keep access to what they received.
+func (ru *ResumeUsecase) DeleteResume(ctx context.Context, studentID, resumeID uint) error {
+	resume, err := ru.ownedResume(studentID, resumeID)
+	if err != nil {
+		return err
+	}
+	cnt, err := ru.resumeRepo.CountApplicationsWithResume(resumeID)
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return ErrResumeInUse
+	}
+	if err := ru.resumeRepo.DeleteResume(resumeID); err != nil {
+		return err
+	}
+	if err := ru.storage.Delete(ctx, resume.StorageKey); err != nil {
+		ru.logger.Errorf("Failed to remove resume object %s: %v", resume.StorageKey, err)
+	}
+	return nil
+}
+
+// OpenResume returns the resume and its content if the caller may read it:
+// students their own files, employers only resumes sent to their vacancies.
+func (ru *ResumeUsecase) OpenResume(ctx context.Context, userID uint, userType model.UserType, resumeID uint) (model.Resume, io.ReadCloser, error) {
+	resume, err := ru.resumeRepo.GetResumeByID(resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Resume{}, nil, ErrResumeNotFound
+		}
+		return model.Resume{}, nil, err
+	}
+
+	switch userType {
+	case model.UserTypeStudent:
+		if resume.StudentID != userID {
+			return model.Resume{}, nil, ErrResumeAccessDenied
+		}
+	case model.UserTypeEmployer:
+		shared, err := ru.resumeRepo.IsResumeSharedWithEmployer(resumeID, userID)
+		if err != nil {
+			return model.Resume{}, nil, err
+		}
+		if !shared {
+			return model.Resume{}, nil, ErrResumeAccessDenied
+		}
+	default:
+		return model.Resume{}, nil, ErrResumeAccessDenied
+	}
+
+	body, err := ru.storage.Get(ctx, resume.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return model.Resume{}, nil, ErrResumeNotFound
+		}
+		return model.Resume{}, nil, err
+	}
+	return resume, body, nil
+}
+
+func (ru *ResumeUsecase) ownedResume(studentID, resumeID uint) (model.Resume, error) {
+	resume, err := ru.resumeRepo.GetResumeByID(resumeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Resume{}, ErrResumeNotFound
+		}
+		return model.Resume{}, err
+	}
+	if resume.StudentID != studentID {
+		return model.Resume{}, ErrResumeNotFound
+	}
+	return resume, nil
+}
@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/mail"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxSavedSearches       = 20
+	maxSavedSearchNameLen  = 100
+	savedSearchMatchLimit  = 50
+	digestVacanciesPerList = 20
+)
+
+var (
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrTooManySavedSearches = fmt.Errorf("a student can keep at most %d saved searches", maxSavedSearches)
+	ErrInvalidSavedSearch   = errors.New("invalid saved search")
+)
+
+// SavedSearchUsecase stores students' vacancy searches and tells them about
+// new matches, in-app as they are found and by email as a periodic digest.
+type SavedSearchUsecase struct {
+	savedSearchRepo  repository.SavedSearchRepository
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	vacancyUsecase   *VacancyUsecase
+	notifications    *NotificationUsecase
+	mailer           mail.Sender
+	appURL           string
+	logger           *util.Logger
+}
+
+func NewSavedSearchUsecase(savedSearchRepo repository.SavedSearchRepository, notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, vacancyUsecase *VacancyUsecase, notifications *NotificationUsecase, mailer mail.Sender, appURL string, logger *util.Logger) *SavedSearchUsecase {
+	return &SavedSearchUsecase{
+		savedSearchRepo:  savedSearchRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		vacancyUsecase:   vacancyUsecase,
+		notifications:    notifications,
+		mailer:           mailer,
+		appURL:           strings.TrimRight(appURL, "/"),
+		logger:           logger,
+	}
+}
+
+func (su *SavedSearchUsecase) ListSavedSearches(studentID uint) ([]model.SavedSearch, error) {
+	return su.savedSearchRepo.GetSavedSearches(studentID)
+}
+
+func (su *SavedSearchUsecase) CreateSavedSearch(s *model.SavedSearch) error {
+	if err := su.validate(s); err != nil {
+		return err
+	}
+	n, err := su.savedSearchRepo.CountSavedSearches(s.StudentID)
+	if err != nil {
+		return err
+	}
+	if n >= maxSavedSearches {
+		return ErrTooManySavedSearches
+	}
+	return su.savedSearchRepo.CreateSavedSearch(s)
+}
+
+func (su *SavedSearchUsecase) UpdateSavedSearch(s *model.SavedSearch) error {
+	if err := su.validate(s); err != nil {
+		return err
+	}
+	err := su.savedSearchRepo.UpdateSavedSearch(s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSavedSearchNotFound
+	}
+	return err
+}
+
+func (su *SavedSearchUsecase) DeleteSavedSearch(studentID, id uint) error {
+	err := su.savedSearchRepo.DeleteSavedSearch(studentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSavedSearchNotFound
+	}
+	return err
+}
+
+// validate normalizes s and checks it the way the search endpoint checks its
+// query, so that a saved search never fails when it is run.
+func (su *SavedSearchUsecase) validate(s *model.SavedSearch) error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.MLSkills = strings.TrimSpace(s.MLSkills)
+	s.Filter.Currency = strings.ToUpper(strings.TrimSpace(s.Filter.Currency))
+	if s.Name == "" {
+		s.Name = strings.TrimSpace(s.Filter.Keywords)
+	}
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSavedSearch)
+	}
+	if utf8.RuneCountInString(s.Name) > maxSavedSearchNameLen {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidSavedSearch, maxSavedSearchNameLen)
+	}
+	if s.Digest == "" {
+		s.Digest = model.DigestDaily
+	}
+	s.Digest = model.DigestFrequency(strings.ToUpper(string(s.Digest)))
+	if !s.Digest.Valid() {
+		return fmt.Errorf("%w: digest must be one of NONE, DAILY, WEEKLY", ErrInvalidSavedSearch)
+	}
+	f := s.Filter
+	if !f.Sort.Valid() {
+		return fmt.Errorf("%w: sort must be relevance, date or salary", ErrInvalidSavedSearch)
+	}
+	if f.SalaryFrom < 0 || f.SalaryMin < 0 || f.SalaryMax < 0 {
+		return fmt.Errorf("%w: salary bounds must not be negative", ErrInvalidSavedSearch)
+	}
+	if f.SalaryMin > 0 && f.SalaryMax > 0 && f.SalaryMin > f.SalaryMax {
+		return fmt.Errorf("%w: salary_min must not exceed salary_max", ErrInvalidSavedSearch)
+	}
+	if f.Currency != "" && !su.vacancyUsecase.currency.Known(f.Currency) {
+		return ErrUnknownCurrency
+	}
+	return nil
+}
+
+// RunSavedSearches matches every saved search against vacancies created or
+// updated since its previous run and notifies the student of each new match.
+// Matches are walked page by page, so none is skipped however many there are.
+// A search whose run fails keeps its watermark and is retried next time;
+// matches already notified are deduplicated.
+func (su *SavedSearchUsecase) RunSavedSearches(now time.Time) (notified int, err error) {
+	searches, err := su.savedSearchRepo.GetAllSavedSearches()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range searches {
+		n, err := su.runSavedSearch(s, now)
+		notified += n
+		if err != nil {
+			su.logger.Warnf("Saved search %d run failed: %v", s.ID, err)
+		}
+	}
+	return notified, nil
+}
+
+func (su *SavedSearchUsecase) runSavedSearch(s model.SavedSearch, now time.Time) (int, error) {
+	notified := 0
+	cursor := model.VacancyChangeCursor{UpdatedAt: s.LastCheckedAt}
+	for {
+		vacs, next, more, err := su.vacancyUsecase.FindNewMatches(s.Filter, s.MLSkills, cursor, now, savedSearchMatchLimit)
+		if err != nil {
+			return notified, err
+		}
+		n, err := su.notifyMatches(s, vacs)
+		notified += n
+		if err != nil {
+			return notified, err
+		}
+		if !more {
+			break
+		}
+		cursor = next
+	}
+	return notified, su.savedSearchRepo.MarkSavedSearchChecked(s.ID, now)
+}
+
+// notifyMatches notifies the student of each vacancy, returning how many
+// notifications were new.
+func (su *SavedSearchUsecase) notifyMatches(s model.SavedSearch, vacs []model.Vacancy) (int, error) {
+	notified := 0
+	for _, v := range vacs {
+		vacancyID, searchID := v.ID, s.ID
+		body := fmt.Sprintf("Matches your saved search %q", s.Name)
+		if v.Location != "" {
+			body += " · " + v.Location
+		}
+		created, err := su.notifications.Notify(&model.Notification{
+			UserID:        s.StudentID,
+			Type:          model.NotificationSavedSearchMatch,
+			Title:         v.Title,
+			Body:          body,
+			VacancyID:     &vacancyID,
+			SavedSearchID: &searchID,
+			DedupKey:      fmt.Sprintf("saved-search:%d:vacancy:%d", s.ID, v.ID),
+		})
+		if err != nil {
+			return notified, err
+		}
+		if created {
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// SendDigests emails each student whose digest is due the unread matches of
+// their due saved searches, one email per student. Each digest is claimed
+// before it is sent, so replicas running this at once do not both send it.
+// Failed sends are retried on the next run.
+func (su *SavedSearchUsecase) SendDigests(ctx context.Context, now time.Time) (sent int, err error) {
+	due, err := su.savedSearchRepo.GetDigestDueSavedSearches(now)
+	if err != nil {
+		return 0, err
+	}
+	byStudent := make(map[uint][]model.SavedSearch)
+	var students []uint
+	for _, s := range due {
+		if _, ok := byStudent[s.StudentID]; !ok {
+			students = append(students, s.StudentID)
+		}
+		byStudent[s.StudentID] = append(byStudent[s.StudentID], s)
+	}
+	for _, studentID := range students {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		ok, err := su.sendDigest(ctx, studentID, byStudent[studentID], now)
+		if err != nil {
+			su.logger.Warnf("Saved search digest for student %d failed: %v", studentID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendDigest first claims the student's due searches, so that no other
+// replica mails the same digest, and gives them back when the email could not
+// be sent.
+func (su *SavedSearchUsecase) sendDigest(ctx context.Context, studentID uint, due []model.SavedSearch, now time.Time) (bool, error) {
+	dueIDs := make([]uint, len(due))
+	for i, s := range due {
+		dueIDs[i] = s.ID
+	}
+	claimedIDs, err := su.savedSearchRepo.ClaimDigests(dueIDs, now)
+	if err != nil || len(claimedIDs) == 0 {
+		return false, err
+	}
+	claimed := make(map[uint]bool, len(claimedIDs))
+	for _, id := range claimedIDs {
+		claimed[id] = true
+	}
+	var searches []model.SavedSearch
+	for _, s := range due {
+		if claimed[s.ID] {
+			searches = append(searches, s)
+		}
+	}
+	release := func(cause error) error {
+		for _, s := range searches {
+			if err := su.savedSearchRepo.ReleaseDigest(s.ID, now, s.LastDigestAt); err != nil {
+				su.logger.Errorf("Failed to release digest of saved search %d: %v", s.ID, err)
+			}
+		}
+		return cause
+	}
+
+	notes, err := su.notificationRepo.GetUnemailedNotifications(claimedIDs)
+	if err != nil {
+		return false, release(err)
+	}
+	if len(notes) == 0 {
+		return false, nil
+	}
+	user, err := su.userRepo.GetByID(studentID)
+	if err != nil {
+		return false, release(err)
+	}
+	if user.BannedAt == nil {
+		if err := su.mailer.Send(ctx, su.digestMessage(user.Email, searches, notes)); err != nil {
+			return false, release(err)
+		}
+	}
+	noteIDs := make([]uint, len(notes))
+	for i, n := range notes {
+		noteIDs[i] = n.ID
+	}
+	return user.BannedAt == nil, su.notificationRepo.MarkNotificationsEmailed(noteIDs, now)
+}
+
+// digestMessage lists new vacancies per saved search. A vacancy matched by
+// several searches is listed under the first of them only.
+func (su *SavedSearchUsecase) digestMessage(to string, searches []model.SavedSearch, notes []model.Notification) mail.Message {
+	bySearch := make(map[uint][]model.Notification)
+	listed := make(map[uint]bool)
+	for _, n := range notes {
+		if n.SavedSearchID == nil || n.VacancyID == nil || listed[*n.VacancyID] {
+			continue
+		}
+		listed[*n.VacancyID] = true
+		bySearch[*n.SavedSearchID] = append(bySearch[*n.SavedSearchID], n)
+	}
+
+	var b strings.Builder
+	b.WriteString("New vacancies matching your saved searches.\n")
+	for _, s := range searches {
+		found := bySearch[s.ID]
+		if len(found) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d new)\n", s.Name, len(found))
+		for i, n := range found {
+			if i == digestVacanciesPerList {
+				fmt.Fprintf(&b, "  …and %d more\n", len(found)-i)
+				break
+			}
+			fmt.Fprintf(&b, "  - %s\n    %s/vacancies/%d\n", n.Title, su.appURL, *n.VacancyID)
+		}
+	}
+	b.WriteString("\nManage your saved searches in your profile to change how often you get this email.\n")
+
+	return mail.Message{
+		To:      to,
+		Subject: fmt.Sprintf("%d new vacancies for your saved searches", len(listed)),
+		Body:    b.String(),
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"strings"
+)
+
+var ErrEmptySkillName = errors.New("skill name is required")
+
+// normalizeSkillName trims the name and collapses inner whitespace so that
+// "Go ", " Go" and "Go" resolve to the same skills row.
+func normalizeSkillName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// resolveSkill returns the skill with the given name, creating it on first use.
+func resolveSkill(skillRepo repository.SkillRepository, name string) (model.Skill, error) {
+	name = normalizeSkillName(name)
+	if name == "" {
+		return model.Skill{}, ErrEmptySkillName
+	}
+	s, err := skillRepo.GetByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		s = model.Skill{Name: name}
+		if err := skillRepo.Create(&s); err != nil {
+			return model.Skill{}, err
+		}
+		return s, nil
+	}
+	return s, err
+}
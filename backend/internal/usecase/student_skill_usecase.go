@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"strings"
+)
+
+const maxYearsExperience = 50
+
+var (
+	ErrInvalidProficiency  = errors.New("proficiency level must be one of BEGINNER, INTERMEDIATE, ADVANCED, EXPERT")
+	ErrInvalidYears        = errors.New("years of experience must be between 0 and 50")
+	ErrStudentSkillMissing = errors.New("skill is not in the student's profile")
+	ErrNoStudentSkills     = errors.New("add skills to your profile to get recommendations")
+)
+
+type StudentSkillUsecase struct {
+	studentSkillRepo repository.StudentSkillRepository
+	skillRepo        repository.SkillRepository
+}
+
+func NewStudentSkillUsecase(studentSkillRepo repository.StudentSkillRepository, skillRepo repository.SkillRepository) *StudentSkillUsecase {
+	return &StudentSkillUsecase{
+		studentSkillRepo: studentSkillRepo,
+		skillRepo:        skillRepo,
+	}
+}
+
+func (su *StudentSkillUsecase) ListSkills(studentID uint) ([]model.StudentSkill, error) {
+	return su.studentSkillRepo.GetStudentSkills(studentID)
+}
+
+// AddSkill attaches a skill by name, creating the skill itself if nobody has
+// used it yet. Adding a skill the student already has updates it.
+func (su *StudentSkillUsecase) AddSkill(studentID uint, name string, level model.ProficiencyLevel, years float64) (model.StudentSkill, error) {
+	level, err := validateStudentSkill(level, years)
+	if err != nil {
+		return model.StudentSkill{}, err
+	}
+	skill, err := resolveSkill(su.skillRepo, name)
+	if err != nil {
+		return model.StudentSkill{}, err
+	}
+	ss := model.StudentSkill{
+		StudentID:        studentID,
+		SkillID:          skill.ID,
+		SkillName:        skill.Name,
+		ProficiencyLevel: level,
+		YearsExperience:  years,
+	}
+	if err := su.studentSkillRepo.UpsertStudentSkill(&ss); err != nil {
+		return model.StudentSkill{}, err
+	}
+	return ss, nil
+}
+
+func (su *StudentSkillUsecase) UpdateSkill(studentID, skillID uint, level model.ProficiencyLevel, years float64) (model.StudentSkill, error) {
+	level, err := validateStudentSkill(level, years)
+	if err != nil {
+		return model.StudentSkill{}, err
+	}
+	ss, err := su.studentSkillRepo.GetStudentSkill(studentID, skillID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.StudentSkill{}, ErrStudentSkillMissing
+		}
+		return model.StudentSkill{}, err
+	}
+	ss.ProficiencyLevel = level
+	ss.YearsExperience = years
+	if err := su.studentSkillRepo.UpsertStudentSkill(&ss); err != nil {
+		return model.StudentSkill{}, err
+	}
+	return ss, nil
+}
+
+func (su *StudentSkillUsecase) RemoveSkill(studentID, skillID uint) error {
+	if err := su.studentSkillRepo.DeleteStudentSkill(studentID, skillID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStudentSkillMissing
+		}
+		return err
+	}
+	return nil
+}
+
+// SkillsQuery joins the student's stored skills into the comma-separated form
+// the ML service expects for student_skills.
+func (su *StudentSkillUsecase) SkillsQuery(studentID uint) (string, error) {
+	skills, err := su.studentSkillRepo.GetStudentSkills(studentID)
+	if err != nil {
+		return "", err
+	}
+	if len(skills) == 0 {
+		return "", ErrNoStudentSkills
+	}
+	names := make([]string, 0, len(skills))
+	for _, s := range skills {
+		names = append(names, s.SkillName)
+	}
+	return strings.Join(names, ", "), nil
+}
+
+func validateStudentSkill(level model.ProficiencyLevel, years float64) (model.ProficiencyLevel, error) {
+	if level == "" {
+		level = model.ProficiencyIntermediate
+	}
+	level = model.ProficiencyLevel(strings.ToUpper(string(level)))
+	if !level.Valid() {
+		return "", ErrInvalidProficiency
+	}
+	if years < 0 || years > maxYearsExperience {
+		return "", ErrInvalidYears
+	}
+	return level, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+)
+
+type SyncRunUsecase struct {
+	syncRunRepo repository.SyncRunRepository
+}
+
+func NewSyncRunUsecase(syncRunRepo repository.SyncRunRepository) *SyncRunUsecase {
+	return &SyncRunUsecase{syncRunRepo: syncRunRepo}
+}
+
+// ListSyncRuns returns the latest import runs, of every source when source
+// is empty.
+func (su *SyncRunUsecase) ListSyncRuns(source string, limit int) ([]model.SyncRun, error) {
+	return su.syncRunRepo.GetSyncRuns(source, limit)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/util"
+	"time"
+)
+
+// VacancyHistoryUsecase keeps a versioned snapshot of every vacancy and tells
+// applicants when the offer they applied to changes.
+type VacancyHistoryUsecase struct {
+	versionRepo repository.VacancyVersionRepository
+	vacancyRepo repository.VacancyRepository
+	appRepo     repository.ApplicationRepository
+	logger      *util.Logger
+}
+
+func NewVacancyHistoryUsecase(versionRepo repository.VacancyVersionRepository, vacancyRepo repository.VacancyRepository, appRepo repository.ApplicationRepository, logger *util.Logger) *VacancyHistoryUsecase {
+	return &VacancyHistoryUsecase{
+		versionRepo: versionRepo,
+		vacancyRepo: vacancyRepo,
+		appRepo:     appRepo,
+		logger:      logger,
+	}
+}
+
+// RecordVersion snapshots the vacancy as currently stored, skills included,
+// unless nothing changed since the latest version. changedBy is nil for
+// imports.
+func (hu *VacancyHistoryUsecase) RecordVersion(vacancyID uint, changedBy *uint, changedByType model.UserType) error {
+	vac, err := hu.vacancyRepo.GetVacancyById(vacancyID)
+	if err != nil {
+		return err
+	}
+	snapshot := model.NewVacancySnapshot(vac)
+
+	latest, err := hu.versionRepo.GetLatestVersion(vacancyID)
+	hasPrev := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if hasPrev && len(snapshot.Diff(latest.Snapshot)) == 0 {
+		return nil
+	}
+
+	version := model.VacancyVersion{
+		VacancyID:     vacancyID,
+		Snapshot:      snapshot,
+		ChangedBy:     changedBy,
+		ChangedByType: changedByType,
+	}
+	if err := hu.versionRepo.CreateVersion(&version); err != nil {
+		return err
+	}
+
+	if hasPrev && snapshot.OfferChanged(latest.Snapshot) {
+		n, err := hu.appRepo.FlagVacancyChanged(vacancyID, time.Now())
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			hu.logger.Infof("Vacancy %d offer changed in v%d, flagged %d applications", vacancyID, version.Version, n)
+		}
+	}
+	return nil
+}
+
+// GetHistory returns the versions of a vacancy with the changes each one
+// made. It is visible to whoever may see the vacancy page; students also get
+// the version they applied to.
+func (hu *VacancyHistoryUsecase) GetHistory(vacancyID uint, studentID, employerID *uint) (model.VacancyHistory, error) {
+	vac, err := hu.vacancyRepo.GetVacancyById(vacancyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.VacancyHistory{}, ErrVacancyNotFound
+		}
+		return model.VacancyHistory{}, err
+	}
+	owner := employerID != nil && *employerID == vac.EmployerID
+	if vac.DeletedAt != nil || (!owner && !vac.Status.Viewable()) {
+		return model.VacancyHistory{}, ErrVacancyNotFound
+	}
+
+	versions, err := hu.versionRepo.GetVersions(vacancyID)
+	if err != nil {
+		return model.VacancyHistory{}, err
+	}
+	for i := 1; i < len(versions); i++ {
+		versions[i].Changes = versions[i].Snapshot.Diff(versions[i-1].Snapshot)
+	}
+
+	history := model.VacancyHistory{VacancyID: vacancyID, Versions: versions}
+	if studentID != nil {
+		app, err := hu.appRepo.GetByStudentAndVacancy(*studentID, vacancyID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return model.VacancyHistory{}, err
+		}
+		if err == nil {
+			history.AppliedVersion = app.VacancyVersion
+		}
+	}
+	return history, nil
+}
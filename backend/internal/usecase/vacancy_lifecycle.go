@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
+	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"time"
+)
+
+var (
+	ErrVacancyNotFound          = errors.New("vacancy not found")
+	ErrInvalidVacancyStatus     = errors.New("unknown vacancy status")
+	ErrInvalidVacancyTransition = errors.New("vacancy cannot move to this status")
+	ErrInvalidVacancySchedule   = errors.New("publish date must be in the future and before the expiry date")
+)
+
+// scheduleNewVacancy settles the initial status of an employer-created
+// vacancy. Status may be DRAFT or PUBLISHED (the default); a future PublishAt
+// keeps the vacancy a draft until the lifecycle job publishes it.
+func (vu *VacancyUsecase) scheduleNewVacancy(v *model.Vacancy, now time.Time) error {
+	if v.Status == "" {
+		v.Status = model.VacancyPublished
+	}
+	switch v.Status {
+	case model.VacancyDraft, model.VacancyPublished:
+	default:
+		return ErrInvalidVacancyStatus
+	}
+	if v.PublishAt != nil {
+		if !v.PublishAt.After(now) {
+			return ErrInvalidVacancySchedule
+		}
+		v.Status = model.VacancyDraft
+	}
+
+	start := now
+	if v.PublishAt != nil {
+		start = *v.PublishAt
+	}
+	if v.ExpiresAt != nil && !v.ExpiresAt.After(start) {
+		return ErrInvalidVacancySchedule
+	}
+	if v.Status == model.VacancyPublished {
+		v.PostedDate = now
+		v.ExpiresAt = vu.expiryFrom(now, v.ExpiresAt)
+	}
+	return nil
+}
+
+// expiryFrom returns the explicit expiry if there is one, otherwise the
+// configured lifetime counted from start. A zero lifetime means no expiry.
+func (vu *VacancyUsecase) expiryFrom(start time.Time, explicit *time.Time) *time.Time {
+	if explicit != nil || vu.vacancyTTL <= 0 {
+		return explicit
+	}
+	exp := start.Add(vu.vacancyTTL)
+	return &exp
+}
+
+// ownedVacancy loads a vacancy the employer may manage. Soft-deleted
+// vacancies are reported as missing.
+func (vu *VacancyUsecase) ownedVacancy(employerID, vacancyID uint) (model.Vacancy, error) {
+	vac, err := vu.vacancyRepo.GetVacancyById(vacancyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Vacancy{}, ErrVacancyNotFound
+		}
+		return model.Vacancy{}, err
+	}
+	if vac.DeletedAt != nil {
+		return model.Vacancy{}, ErrVacancyNotFound
+	}
+	if vac.EmployerID != employerID {
+		return model.Vacancy{}, ErrNotVacancyOwner
+	}
+	return vac, nil
+}
+
+// ChangeVacancyStatus moves an employer's vacancy along the lifecycle. A draft
+// may stay a draft to (re)schedule or unschedule its publication. Publishing
+// gives the vacancy a fresh lifetime unless its current expiry is still
+// ahead.
+func (vu *VacancyUsecase) ChangeVacancyStatus(employerID, vacancyID uint, status model.VacancyStatus, publishAt *time.Time) (model.Vacancy, error) {
+	if !status.Valid() {
+		return model.Vacancy{}, ErrInvalidVacancyStatus
+	}
+	vac, err := vu.ownedVacancy(employerID, vacancyID)
+	if err != nil {
+		return model.Vacancy{}, err
+	}
+	from := vac.Status
+	now := time.Now()
+
+	switch {
+	case status == model.VacancyDraft && from == model.VacancyDraft:
+		if publishAt != nil && !publishAt.After(now) {
+			return model.Vacancy{}, ErrInvalidVacancySchedule
+		}
+		if publishAt != nil && vac.ExpiresAt != nil && !vac.ExpiresAt.After(*publishAt) {
+			return model.Vacancy{}, ErrInvalidVacancySchedule
+		}
+		vac.PublishAt = publishAt
+	case !from.CanTransitionTo(status):
+		return model.Vacancy{}, ErrInvalidVacancyTransition
+	case status == model.VacancyPublished:
+		vac.PublishAt = nil
+		if vac.ExpiresAt != nil && !vac.ExpiresAt.After(now) {
+			vac.ExpiresAt = nil
+		}
+		vac.ExpiresAt = vu.expiryFrom(now, vac.ExpiresAt)
+	}
+
+	vac.Status = status
+	if err := vu.vacancyRepo.ChangeVacancyStatus(&vac, from); err != nil {
+		if errors.Is(err, repository.ErrVacancyStatusChanged) {
+			return model.Vacancy{}, ErrInvalidVacancyTransition
+		}
+		return model.Vacancy{}, err
+	}
+	return vac, nil
+}
+
+// RunVacancyLifecycle publishes scheduled drafts, closes expired vacancies and
+// archives ones closed for longer than the archive delay.
+func (vu *VacancyUsecase) RunVacancyLifecycle(now time.Time) (published, expired, archived int64, err error) {
+	if published, err = vu.vacancyRepo.PublishScheduledVacancies(now, vu.vacancyTTL); err != nil {
+		return
+	}
+	if expired, err = vu.vacancyRepo.ExpireVacancies(now); err != nil {
+		return
+	}
+	if vu.archiveAfter > 0 {
+		archived, err = vu.vacancyRepo.ArchiveClosedVacancies(now.Add(-vu.archiveAfter))
+	}
+	return
+}
+
+// CloseRemovedVacancy closes a published or paused imported vacancy its
+// source no longer lists. It reports false when the vacancy was not open.
+func (vu *VacancyUsecase) CloseRemovedVacancy(vacancyID uint) (bool, error) {
+	vac, err := vu.vacancyRepo.GetVacancyById(vacancyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrVacancyNotFound
+		}
+		return false, err
+	}
+	from := vac.Status
+	if vac.DeletedAt != nil || (from != model.VacancyPublished && from != model.VacancyPaused) {
+		return false, nil
+	}
+	vac.Status = model.VacancyClosed
+	if err := vu.vacancyRepo.ChangeVacancyStatus(&vac, from); err != nil {
+		if errors.Is(err, repository.ErrVacancyStatusChanged) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -1,60 +1,282 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/domain/repository"
-	"github.com/chotamkz/career-track-backend/internal/integration/hh"
+	"github.com/chotamkz/career-track-backend/internal/integration"
 	"github.com/chotamkz/career-track-backend/internal/util"
-	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	// maxSyncRunErrors caps the error lines kept on a sync run.
+	maxSyncRunErrors = 50
+	// defaultRecheckBatch is how many stale vacancies a cycle re-checks.
+	defaultRecheckBatch = 200
+)
+
+// VacancyUpdater imports the vacancies of one source and closes imported
+// ones the source no longer lists. A vacancy the sync has not seen for
+// StaleAfter is re-checked, at most RecheckBatch per cycle; a zero
+// StaleAfter turns re-checks off.
 type VacancyUpdater struct {
-	UserRepo       repository.UserRepository
+	Source         integration.VacancySource
+	CompanyRepo    repository.CompanyRepository
 	VacancyRepo    repository.VacancyRepository
+	SyncRunRepo    repository.SyncRunRepository
+	AppRepo        repository.ApplicationRepository
 	vacancyUsecase *VacancyUsecase
+	notifications  *NotificationUsecase
+	StaleAfter     time.Duration
+	RecheckBatch   int
 	Logger         *util.Logger
 }
 
-func NewVacancyUpdater(userRepo repository.UserRepository, vacancyRepo repository.VacancyRepository, vacUsecase *VacancyUsecase, logger *util.Logger) *VacancyUpdater {
+func NewVacancyUpdater(source integration.VacancySource, companyRepo repository.CompanyRepository, vacancyRepo repository.VacancyRepository, syncRunRepo repository.SyncRunRepository, appRepo repository.ApplicationRepository, vacUsecase *VacancyUsecase, notifications *NotificationUsecase, staleAfter time.Duration, recheckBatch int, logger *util.Logger) *VacancyUpdater {
+	if recheckBatch <= 0 {
+		recheckBatch = defaultRecheckBatch
+	}
 	return &VacancyUpdater{
-		UserRepo:       userRepo,
+		Source:         source,
+		CompanyRepo:    companyRepo,
 		VacancyRepo:    vacancyRepo,
+		SyncRunRepo:    syncRunRepo,
+		AppRepo:        appRepo,
 		vacancyUsecase: vacUsecase,
+		notifications:  notifications,
+		StaleAfter:     staleAfter,
+		RecheckBatch:   recheckBatch,
 		Logger:         logger,
 	}
 }
 
-func (vu *VacancyUpdater) UpdateVacancies(perPage, page int) error {
-	hhVacancies, err := hh.FetchITVacancies(perPage, page, vu.Logger)
-	if err != nil {
-		return fmt.Errorf("failed to fetch vacancies from HH API: %v", err)
+// Sync imports what the source lists, incrementally unless full is set, and
+// then re-checks stale vacancies. The cycle is recorded in sync_runs under
+// the source's name.
+// The caller must make sure no other sync of the source runs at the same
+// time. A cancelled ctx stops the cycle between vacancies; the run is then
+// recorded as CANCELLED.
+func (vu *VacancyUpdater) Sync(ctx context.Context, full bool) (model.SyncRun, error) {
+	source := vu.Source.Name()
+	if n, err := vu.SyncRunRepo.FailInterruptedSyncRuns(source); err != nil {
+		return model.SyncRun{}, err
+	} else if n > 0 {
+		vu.Logger.Warnf("Marked %d interrupted %s sync run(s) as failed", n, source)
+	}
+
+	run := model.SyncRun{Source: source, FullResync: full}
+	if err := vu.SyncRunRepo.CreateSyncRun(&run); err != nil {
+		return run, err
+	}
+
+	s := &syncCycle{vu: vu, ctx: ctx, run: &run}
+	listErr := vu.Source.List(ctx, full, s)
+	if listErr != nil && ctx.Err() == nil {
+		vu.Logger.Errorf("Listing %s vacancies failed: %v", source, listErr)
+		s.Errorf("list: %v", listErr)
+	}
+	if ctx.Err() == nil {
+		vu.recheckStale(ctx, s, time.Now())
+	}
+
+	run.Errors = strings.Join(s.errs, "\n")
+	switch {
+	case ctx.Err() != nil:
+		run.Status = model.SyncCancelled
+	case listErr != nil, run.Pages == 0 && len(s.errs) > 0:
+		run.Status = model.SyncFailed
+	default:
+		run.Status = model.SyncSucceeded
+	}
+	if err := vu.SyncRunRepo.FinishSyncRun(&run); err != nil {
+		return run, err
+	}
+	if run.Status == model.SyncCancelled {
+		return run, ctx.Err()
+	}
+	return run, nil
+}
+
+// syncCycle is the state of one Sync cycle and the integration.Listing the
+// source reports to.
+type syncCycle struct {
+	vu   *VacancyUpdater
+	ctx  context.Context
+	run  *model.SyncRun
+	errs []string
+}
+
+func (s *syncCycle) Errorf(format string, args ...interface{}) {
+	if len(s.errs) < maxSyncRunErrors {
+		s.errs = append(s.errs, fmt.Sprintf(format, args...))
 	}
+}
 
-	for _, hhVac := range hhVacancies {
-		empID, err := strconv.ParseUint(hhVac.Employer.ID, 10, 32)
+// Page stores one page of listed vacancies. Failures of single vacancies are
+// counted, recorded and returned to the source; the only error returned is
+// ctx's.
+func (s *syncCycle) Page(items []integration.Item) ([]integration.Item, error) {
+	vu, source := s.vu, s.vu.Source.Name()
+	valid := make([]integration.Item, 0, len(items))
+	companies := make([]model.Company, 0, len(items))
+	var failed []integration.Item
+	for _, item := range items {
+		if err := s.ctx.Err(); err != nil {
+			return nil, err
+		}
+		externalID, name := item.Employer.ExternalID, item.Employer.Name
+		if externalID == "" {
+			externalID = name
+		}
+		if externalID == "" {
+			s.run.Failed++
+			// Not returned as failed: listing it again would not help, and
+			// it would hold the source's watermark back for good.
+			s.Errorf("vacancy %s: no employer", item.ExternalID)
+			continue
+		}
+		if name == "" {
+			name = externalID
+		}
+		company, err := vu.CompanyRepo.EnsureImportedCompany(source, externalID, name, item.Employer.Website)
 		if err != nil {
-			vu.Logger.Errorf("Failed to parse employer ID '%s': %v", hhVac.Employer.ID, err)
+			vu.Logger.Errorf("Failed to ensure %s company %s exists: %v", source, externalID, err)
+			s.run.Failed++
+			s.Errorf("vacancy %s: company %s: %v", item.ExternalID, externalID, err)
+			failed = append(failed, item)
 			continue
 		}
+		valid = append(valid, item)
+		companies = append(companies, company)
+	}
 
-		if err := vu.UserRepo.EnsureEmployerExists(uint(empID), hhVac.Employer.Name); err != nil {
-			vu.Logger.Errorf("Failed to ensure employer exists for ID %d: %v", uint(empID), err)
+	vacancies, mapErrs := vu.Source.Map(s.ctx, valid)
+	// Mapping cut short by cancellation leaves vacancies half-mapped; they
+	// must not overwrite the stored ones.
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i := range vacancies {
+		if mapErrs[i] != nil {
+			// A partly mapped vacancy would overwrite the stored one with
+			// blanks, so it is left to the source to list again.
+			s.run.Failed++
+			s.Errorf("vacancy %s: map: %v", valid[i].ExternalID, mapErrs[i])
+			failed = append(failed, valid[i])
 			continue
 		}
-
-		vacancy := hh.MapHHVacancyToInternal(hhVac, vu.Logger)
-		if err := vu.VacancyRepo.UpsertVacancy(&vacancy); err != nil {
+		vacancy := &vacancies[i]
+		vacancy.Source = source
+		vacancy.ExternalID = valid[i].ExternalID
+		vacancy.CompanyID = companies[i].ID
+		if owner := companies[i].OwnerID; owner != nil {
+			vacancy.EmployerID = *owner
+		}
+		result, err := vu.vacancyUsecase.UpsertVacancy(vacancy)
+		if err != nil {
 			vu.Logger.Errorf("Failed to upsert vacancy '%s': %v", vacancy.Title, err)
+			s.run.Failed++
+			s.Errorf("vacancy %s: %v", valid[i].ExternalID, err)
+			failed = append(failed, valid[i])
 			continue
 		}
+		s.run.Add(result)
 
-		if len(vacancy.Skills) > 0 {
-			if err := vu.vacancyUsecase.AddSkillsToVacancy(vacancy.ID, vacancy.Skills); err != nil {
-				vu.Logger.Errorf("Failed to add skills to vacancy '%s': %v", vacancy.Title, err)
-			}
+		vu.Logger.Info("Upserted vacancy: " + vacancy.Title)
+	}
+	s.run.Pages++
+	return failed, nil
+}
+
+// recheckStale asks the source about its open vacancies the sync has not
+// seen for StaleAfter. Those it no longer lists are closed, once every
+// student with an open application to them has been notified; a vacancy
+// whose check or notifications failed is checked again after StaleAfter.
+func (vu *VacancyUpdater) recheckStale(ctx context.Context, s *syncCycle, now time.Time) {
+	if vu.StaleAfter <= 0 {
+		return
+	}
+	stale, err := vu.VacancyRepo.GetStaleImportedVacancies(vu.Source.Name(), now.Add(-vu.StaleAfter), vu.RecheckBatch)
+	if err != nil {
+		vu.Logger.Errorf("Failed to load stale vacancies: %v", err)
+		s.Errorf("recheck: %v", err)
+		return
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	ids := make([]string, len(stale))
+	for i, v := range stale {
+		ids[i] = v.ExternalID
+	}
+	vu.Logger.Infof("Re-checking %d stale %s vacancies", len(stale), vu.Source.Name())
+
+	_, errs := vu.Source.Details(ctx, ids)
+	if ctx.Err() != nil {
+		return
+	}
+	for i, v := range stale {
+		if errs[i] == nil {
+			vu.markChecked(s, v.ID, now, true)
+			continue
 		}
+		if !errors.Is(errs[i], integration.ErrVacancyGone) {
+			vu.Logger.Errorf("Failed to re-check vacancy %d (%s %s): %v", v.ID, vu.Source.Name(), ids[i], errs[i])
+			s.Errorf("recheck vacancy %d: %v", v.ID, errs[i])
+			vu.markChecked(s, v.ID, now, false)
+			continue
+		}
+		if err := vu.closeRemoved(v); err != nil {
+			vu.Logger.Errorf("Failed to close removed vacancy %d: %v", v.ID, err)
+			s.Errorf("close vacancy %d: %v", v.ID, err)
+			vu.markChecked(s, v.ID, now, false)
+			continue
+		}
+		s.run.Closed++
+		vu.markChecked(s, v.ID, now, false)
+	}
+}
 
-		vu.Logger.Info("Upserted vacancy: " + vacancy.Title)
+func (vu *VacancyUpdater) markChecked(s *syncCycle, id uint, now time.Time, seen bool) {
+	if err := vu.VacancyRepo.MarkVacancyChecked(id, now, seen); err != nil {
+		vu.Logger.Errorf("Failed to mark vacancy %d checked: %v", id, err)
+		s.Errorf("recheck vacancy %d: %v", id, err)
+	}
+}
+
+// closeRemoved notifies the vacancy's open applicants and then closes it.
+// Notifications are deduplicated, so a retry after a partial failure does
+// not repeat them.
+func (vu *VacancyUpdater) closeRemoved(v model.ImportedVacancy) error {
+	studentIDs, err := vu.AppRepo.GetOpenApplicantIDs(v.ID)
+	if err != nil {
+		return err
+	}
+	for _, studentID := range studentIDs {
+		vacancyID := v.ID
+		_, err := vu.notifications.Notify(&model.Notification{
+			UserID:    studentID,
+			Type:      model.NotificationVacancyRemoved,
+			Title:     v.Title,
+			Body:      "A vacancy you applied to was removed from the job board it was imported from and is now closed",
+			VacancyID: &vacancyID,
+			DedupKey:  fmt.Sprintf("vacancy:%d:removed", v.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	closed, err := vu.vacancyUsecase.CloseRemovedVacancy(v.ID)
+	if err != nil {
+		return err
+	}
+	if closed {
+		vu.Logger.Infof("Closed vacancy %d removed from %s, %d applicant(s) notified", v.ID, vu.Source.Name(), len(studentIDs))
 	}
 	return nil
 }
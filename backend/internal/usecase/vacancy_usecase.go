@@ -2,11 +2,14 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/chotamkz/career-track-backend/internal/domain/model"
 	"github.com/chotamkz/career-track-backend/internal/domain/repository"
+	"github.com/chotamkz/career-track-backend/internal/richtext"
 	"github.com/patrickmn/go-cache"
 	"io/ioutil"
 	"net/http"
@@ -32,22 +35,36 @@ type MLResponse struct {
 type VacancyUsecase struct {
 	vacancyRepo  repository.VacancyRepository
 	skillRepo    repository.SkillRepository
-	employerRepo repository.EmployerRepository
+	companyRepo  repository.CompanyRepository
+	currency     *CurrencyUsecase
+	history      *VacancyHistoryUsecase
 	mlServiceURL string
+	vacancyTTL   time.Duration
+	archiveAfter time.Duration
 	countCache   *cache.Cache
 }
 
-func NewVacancyUsecase(vacRepo repository.VacancyRepository, skillRepo repository.SkillRepository, employerRepo repository.EmployerRepository, mlServiceURL string) *VacancyUsecase {
+func NewVacancyUsecase(vacRepo repository.VacancyRepository, skillRepo repository.SkillRepository, companyRepo repository.CompanyRepository, currencyUsecase *CurrencyUsecase, historyUsecase *VacancyHistoryUsecase, mlServiceURL string, vacancyTTL, archiveAfter time.Duration) *VacancyUsecase {
 	c := cache.New(1*time.Minute, 4*time.Minute)
 	return &VacancyUsecase{
 		vacancyRepo:  vacRepo,
 		skillRepo:    skillRepo,
-		employerRepo: employerRepo,
+		companyRepo:  companyRepo,
+		currency:     currencyUsecase,
+		history:      historyUsecase,
 		mlServiceURL: mlServiceURL,
+		vacancyTTL:   vacancyTTL,
+		archiveAfter: archiveAfter,
 		countCache:   c,
 	}
 }
 
+// InvalidateCounts drops the cached totals and facets, e.g. after salaries
+// were renormalized and the salary buckets moved.
+func (vu *VacancyUsecase) InvalidateCounts() {
+	vu.countCache.Flush()
+}
+
 func (vu *VacancyUsecase) ListVacancies(page, size int, studentID *uint) ([]model.Vacancy, int, error) {
 	offset := (page - 1) * size
 
@@ -79,12 +96,28 @@ func (vu *VacancyUsecase) ListVacancies(page, size int, studentID *uint) ([]mode
 	return vacancies, total, nil
 }
 
-func (vu *VacancyUsecase) GetVacancyWithDetails(id uint, studentID *uint) (model.VacancyDetailResponse, error) {
+// GetVacancyWithDetails returns the vacancy page. Drafts and archived
+// vacancies are only visible to their owner; deleted ones to nobody.
+func (vu *VacancyUsecase) GetVacancyWithDetails(id uint, studentID, employerID *uint) (model.VacancyDetailResponse, error) {
+	var resp model.VacancyDetailResponse
+	var err error
 	if studentID != nil {
-		return vu.vacancyRepo.GetVacancyWithDetailsAndApplication(id, *studentID)
+		resp, err = vu.vacancyRepo.GetVacancyWithDetailsAndApplication(id, *studentID)
+	} else {
+		resp, err = vu.vacancyRepo.GetVacancyWithDetails(id)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.VacancyDetailResponse{}, ErrVacancyNotFound
+		}
+		return model.VacancyDetailResponse{}, err
 	}
 
-	return vu.vacancyRepo.GetVacancyWithDetails(id)
+	owner := employerID != nil && *employerID == resp.Vacancy.EmployerID
+	if resp.Vacancy.DeletedAt != nil || (!owner && !resp.Vacancy.Status.Viewable()) {
+		return model.VacancyDetailResponse{}, ErrVacancyNotFound
+	}
+	return resp, nil
 }
 
 func (vu *VacancyUsecase) FilterVacancies(
@@ -98,7 +131,15 @@ func (vu *VacancyUsecase) FilterVacancies(
 	}
 	offset := (page - 1) * size
 
-	keyBytes, _ := json.Marshal(filter)
+	filter, err := vu.salaryFilterToKZT(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Sorting doesn't change the total, so all orders share one cached count.
+	countFilter := filter
+	countFilter.Sort = ""
+	keyBytes, _ := json.Marshal(countFilter)
 	cacheKey := "count:" + string(keyBytes)
 
 	var total int
@@ -114,41 +155,202 @@ func (vu *VacancyUsecase) FilterVacancies(
 	}
 
 	vacs, err := vu.vacancyRepo.GetFilteredVacancies(filter, size, offset)
-	return vacs, total, err
+	if err != nil {
+		return nil, 0, err
+	}
+	vu.currency.ApplyDisplay(vacs, filter.Currency)
+	return vacs, total, nil
 }
 
-func (vu *VacancyUsecase) CreateVacancy(v *model.Vacancy) error {
-	return vu.vacancyRepo.CreateVacancy(v)
+// salaryMatches mirrors the repository's salary conditions for vacancies
+// filtered in memory; v must already be normalized.
+func salaryMatches(v model.Vacancy, filter model.VacancyFilter) bool {
+	if filter.SalaryFrom <= 0 && filter.SalaryMin <= 0 && filter.SalaryMax <= 0 {
+		return true
+	}
+	low, high := v.SalaryFromKZT, v.SalaryToKZT
+	if low == nil {
+		low = high
+	}
+	if high == nil {
+		high = low
+	}
+	if low == nil {
+		return false
+	}
+	if filter.SalaryFrom > 0 && (v.SalaryFromKZT == nil || *v.SalaryFromKZT < filter.SalaryFrom) {
+		return false
+	}
+	if filter.SalaryMin > 0 && *high < filter.SalaryMin {
+		return false
+	}
+	if filter.SalaryMax > 0 && *low > filter.SalaryMax {
+		return false
+	}
+	return true
 }
 
-func (vu *VacancyUsecase) DeleteVacancy(employerID, vacancyID uint) error {
-	vac, err := vu.vacancyRepo.GetVacancyById(vacancyID)
+// salaryFilterToKZT converts the salary bounds of the filter from the
+// requested currency to net KZT, the unit the repository compares in.
+func (vu *VacancyUsecase) salaryFilterToKZT(filter model.VacancyFilter) (model.VacancyFilter, error) {
+	filter.Currency = strings.ToUpper(strings.TrimSpace(filter.Currency))
+	if filter.Currency == "" || filter.Currency == model.BaseCurrency {
+		return filter, nil
+	}
+	if !vu.currency.Known(filter.Currency) {
+		return filter, ErrUnknownCurrency
+	}
+	for _, amount := range []*float64{&filter.SalaryFrom, &filter.SalaryMin, &filter.SalaryMax} {
+		if *amount > 0 {
+			*amount, _ = vu.currency.ToKZT(*amount, filter.Currency)
+		}
+	}
+	return filter, nil
+}
+
+var ErrCursorUnsupportedSort = errors.New("cursor pagination is only available when sorting by date")
+
+// ListVacanciesByCursor returns the page at the cursor. One extra row is
+// fetched to learn whether the listing continues past the page.
+func (vu *VacancyUsecase) ListVacanciesByCursor(cursor model.VacancyCursor, size int, studentID *uint) (model.VacancyPage, error) {
+	vacs, err := vu.vacancyRepo.GetVacanciesByCursor(&cursor, size+1, studentID)
+	if err != nil {
+		return model.VacancyPage{}, fmt.Errorf("cannot list vacancies by cursor: %v", err)
+	}
+	return keysetPage(vacs, size, cursor.Before), nil
+}
+
+func (vu *VacancyUsecase) FilterVacanciesByCursor(filter model.VacancyFilter, cursor model.VacancyCursor, size int) (model.VacancyPage, error) {
+	if !filter.KeysetOrdered() {
+		return model.VacancyPage{}, ErrCursorUnsupportedSort
+	}
+	filter, err := vu.salaryFilterToKZT(filter)
+	if err != nil {
+		return model.VacancyPage{}, err
+	}
+	vacs, err := vu.vacancyRepo.GetFilteredVacanciesByCursor(filter, &cursor, size+1)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("vacancy not found: %w", err)
+		return model.VacancyPage{}, fmt.Errorf("filter by cursor: %v", err)
+	}
+	vu.currency.ApplyDisplay(vacs, filter.Currency)
+	return keysetPage(vacs, size, cursor.Before), nil
+}
+
+// keysetPage trims the look-ahead row and decides which cursors to hand out.
+// Coming from a cursor means there is always something on the side we came
+// from.
+func keysetPage(vacs []model.Vacancy, size int, before bool) model.VacancyPage {
+	hasMore := len(vacs) > size
+	if hasMore {
+		if before {
+			vacs = vacs[1:]
+		} else {
+			vacs = vacs[:size]
 		}
+	}
+	if before {
+		return model.NewVacancyPage(vacs, true, hasMore)
+	}
+	return model.NewVacancyPage(vacs, hasMore, true)
+}
+
+// facetSalaryThresholds are the salary_from options offered by the filter UI,
+// in net KZT.
+var facetSalaryThresholds = []float64{100000, 200000, 300000, 500000, 800000, 1000000}
+
+const facetValuesLimit = 50
+
+// GetVacancyFacets returns per-option counts for the filter. Results share
+// countCache with the filtered totals.
+func (vu *VacancyUsecase) GetVacancyFacets(filter model.VacancyFilter) (model.VacancyFacets, error) {
+	filter, err := vu.salaryFilterToKZT(filter)
+	if err != nil {
+		return model.VacancyFacets{}, err
+	}
+	filter.Sort = ""
+	keyBytes, _ := json.Marshal(filter)
+	cacheKey := "facets:" + string(keyBytes)
+
+	if x, found := vu.countCache.Get(cacheKey); found {
+		return x.(model.VacancyFacets), nil
+	}
+	facets, err := vu.vacancyRepo.GetVacancyFacets(filter, facetSalaryThresholds, facetValuesLimit)
+	if err != nil {
+		return model.VacancyFacets{}, fmt.Errorf("vacancy facets: %v", err)
+	}
+	vu.countCache.Set(cacheKey, facets, cache.DefaultExpiration)
+	return facets, nil
+}
+
+// CreateVacancy stores an employer's vacancy with its skills and records it
+// as the first version.
+func (vu *VacancyUsecase) CreateVacancy(v *model.Vacancy) error {
+	if err := vu.scheduleNewVacancy(v, time.Now()); err != nil {
 		return err
 	}
-	if vac.EmployerID != employerID {
-		return ErrNotVacancyOwner
+	companyID, err := vu.companyRepo.EnsureEmployerCompany(v.EmployerID)
+	if err != nil {
+		return err
+	}
+	v.CompanyID = companyID
+	prepareDescription(v)
+	vu.currency.Normalize(v)
+	if err := vu.vacancyRepo.CreateVacancy(v); err != nil {
+		return err
+	}
+	if err := vu.setVacancySkills(v.ID, v.Skills); err != nil {
+		return err
+	}
+	return vu.history.RecordVersion(v.ID, &v.EmployerID, model.UserTypeEmployer)
+}
+
+// UpsertVacancy stores an imported vacancy with its description sanitized and
+// salary normalized, replaces its skills with the ones the source lists, and
+// records a new version if the import changed anything.
+func (vu *VacancyUsecase) UpsertVacancy(v *model.Vacancy) (model.UpsertResult, error) {
+	prepareDescription(v)
+	vu.currency.Normalize(v)
+	result, err := vu.vacancyRepo.UpsertVacancy(v)
+	if err != nil || result == model.UpsertOwned {
+		return result, err
+	}
+	if err := vu.setVacancySkills(v.ID, v.Skills); err != nil {
+		return result, err
+	}
+	return result, vu.history.RecordVersion(v.ID, nil, "")
+}
+
+// DeleteVacancy soft-deletes the employer's vacancy; applications to it are
+// kept.
+func (vu *VacancyUsecase) DeleteVacancy(employerID, vacancyID uint) error {
+	if _, err := vu.ownedVacancy(employerID, vacancyID); err != nil {
+		return err
 	}
 	return vu.vacancyRepo.DeleteVacancyByID(vacancyID)
 }
 
-func (vu *VacancyUsecase) AddSkillsToVacancy(vacancyID uint, skills []string) error {
-	for _, s := range skills {
-		skillName := strings.TrimSpace(s)
-		if skillName == "" {
+// setVacancySkills resolves names to skills, creating missing ones, and makes
+// them the vacancy's whole skill set. Blank names are skipped.
+func (vu *VacancyUsecase) setVacancySkills(vacancyID uint, names []string) error {
+	var skillIDs []uint
+	for _, name := range names {
+		s, err := resolveSkill(vu.skillRepo, name)
+		if errors.Is(err, ErrEmptySkillName) {
 			continue
 		}
-		if err := vu.vacancyRepo.InsertVacancySkill(vacancyID, skillName); err != nil {
+		if err != nil {
 			return err
 		}
+		skillIDs = append(skillIDs, s.ID)
 	}
-	return nil
+	return vu.vacancyRepo.SetSkills(vacancyID, skillIDs)
 }
 
 func (vu *VacancyUsecase) GetRecommendedVacancies(filter model.VacancyFilter, mlSkills string) ([]model.VacancyMLResponse, error) {
+	filter, err := vu.salaryFilterToKZT(filter)
+	if err != nil {
+		return nil, err
+	}
 	mlResp, err := getMLRecommendations(mlSkills, vu.mlServiceURL)
 	if err != nil {
 		return nil, fmt.Errorf("ML service call failed: %v", err)
@@ -188,7 +390,8 @@ func (vu *VacancyUsecase) GetRecommendedVacancies(filter model.VacancyFilter, ml
 		if lcExperience != "" && !strings.Contains(lcVacExperience, lcExperience) {
 			continue
 		}
-		if filter.SalaryFrom > 0 && v.SalaryFrom < filter.SalaryFrom {
+		vu.currency.Normalize(&v)
+		if !salaryMatches(v, filter) {
 			continue
 		}
 		if lcSchedule != "" && !strings.Contains(lcVacSchedule, lcSchedule) {
@@ -197,6 +400,7 @@ func (vu *VacancyUsecase) GetRecommendedVacancies(filter model.VacancyFilter, ml
 
 		preFilteredVacancies = append(preFilteredVacancies, v)
 	}
+	vu.currency.ApplyDisplay(preFilteredVacancies, filter.Currency)
 
 	if filter.CompanyName == "" {
 		var result []model.VacancyMLResponse
@@ -221,28 +425,27 @@ func (vu *VacancyUsecase) GetRecommendedVacancies(filter model.VacancyFilter, ml
 		return result, nil
 	}
 
-	employerIDs := make([]uint, 0, len(preFilteredVacancies))
+	companyIDs := make([]uint, 0, len(preFilteredVacancies))
 	for _, v := range preFilteredVacancies {
-		employerIDs = append(employerIDs, v.EmployerID)
+		companyIDs = append(companyIDs, v.CompanyID)
 	}
 
-	employers, err := vu.employerRepo.GetEmployersByIDs(employerIDs)
+	companies, err := vu.companyRepo.GetCompaniesByIDs(companyIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch employer profiles: %v", err)
+		return nil, fmt.Errorf("failed to fetch companies: %v", err)
 	}
 
-	employerMap := make(map[uint]model.EmployerProfile)
-	for _, emp := range employers {
-		employerMap[emp.UserID] = emp
+	companyMap := make(map[uint]model.Company)
+	for _, c := range companies {
+		companyMap[c.ID] = c
 	}
 
 	lcCompanyName := strings.ToLower(filter.CompanyName)
 	var finalVacancies []model.Vacancy
 
 	for _, v := range preFilteredVacancies {
-		if emp, ok := employerMap[v.EmployerID]; ok {
-			lcEmpCompanyName := strings.ToLower(emp.CompanyName)
-			if strings.Contains(lcEmpCompanyName, lcCompanyName) {
+		if c, ok := companyMap[v.CompanyID]; ok {
+			if strings.Contains(strings.ToLower(c.Name), lcCompanyName) {
 				finalVacancies = append(finalVacancies, v)
 			}
 		}
@@ -270,6 +473,47 @@ func (vu *VacancyUsecase) GetRecommendedVacancies(filter model.VacancyFilter, ml
 	return result, nil
 }
 
+// FindNewMatches returns one page of up to limit vacancies matching the
+// filter that were created or updated after the cursor and no later than to.
+// With mlSkills set only vacancies the ML service recommends for those skills
+// are kept, so a page may come back shorter. next is the position of the last
+// vacancy of the page before that filtering, and more reports whether there
+// may be further pages.
+func (vu *VacancyUsecase) FindNewMatches(filter model.VacancyFilter, mlSkills string, after model.VacancyChangeCursor, to time.Time, limit int) (vacs []model.Vacancy, next model.VacancyChangeCursor, more bool, err error) {
+	filter, err = vu.salaryFilterToKZT(filter)
+	if err != nil {
+		return nil, after, false, err
+	}
+	vacs, err = vu.vacancyRepo.GetFilteredVacanciesChangedBetween(filter, after, to, limit)
+	if err != nil {
+		return nil, after, false, err
+	}
+	next, more = after, len(vacs) == limit
+	if len(vacs) > 0 {
+		last := vacs[len(vacs)-1]
+		next = model.VacancyChangeCursor{UpdatedAt: *last.UpdatedAt, ID: last.ID}
+	}
+	if mlSkills != "" && len(vacs) > 0 {
+		mlResp, err := getMLRecommendations(mlSkills, vu.mlServiceURL)
+		if err != nil {
+			return nil, after, false, fmt.Errorf("ML service call failed: %v", err)
+		}
+		recommended := make(map[uint]bool, len(mlResp.Recommendations))
+		for _, rec := range mlResp.Recommendations {
+			recommended[rec.VacancyID] = true
+		}
+		kept := vacs[:0]
+		for _, v := range vacs {
+			if recommended[v.ID] {
+				kept = append(kept, v)
+			}
+		}
+		vacs = kept
+	}
+	vu.currency.ApplyDisplay(vacs, filter.Currency)
+	return vacs, next, more, nil
+}
+
 func getMLRecommendations(studentSkills, mlServiceURL string) (MLResponse, error) {
 	payload := map[string]string{
 		"student_skills": studentSkills,
@@ -299,38 +543,87 @@ func (vu *VacancyUsecase) GetEmployerVacancies(employerID uint) ([]model.Vacancy
 }
 
 func (vu *VacancyUsecase) UpdateVacancy(employerID uint, vac *model.Vacancy, skillNames []string) error {
-	exist, err := vu.vacancyRepo.GetVacancyById(vac.ID)
-	if err != nil {
+	if _, err := vu.ownedVacancy(employerID, vac.ID); err != nil {
 		return err
 	}
-	if exist.EmployerID != employerID {
-		return ErrNotVacancyOwner
+	return vu.applyVacancyUpdate(vac, skillNames, &employerID, model.UserTypeEmployer)
+}
+
+// AdminUpdateVacancy edits any vacancy regardless of its owner.
+func (vu *VacancyUsecase) AdminUpdateVacancy(vac *model.Vacancy, skillNames []string) error {
+	if _, err := vu.vacancyRepo.GetVacancyById(vac.ID); err != nil {
+		return err
 	}
+	return vu.applyVacancyUpdate(vac, skillNames, nil, model.UserTypeAdmin)
+}
+
+// AdminDeleteVacancy soft-deletes any vacancy regardless of its owner.
+func (vu *VacancyUsecase) AdminDeleteVacancy(vacancyID uint) error {
+	return vu.vacancyRepo.DeleteVacancyByID(vacancyID)
+}
 
+// applyVacancyUpdate saves the edit and records it as a new version.
+func (vu *VacancyUsecase) applyVacancyUpdate(vac *model.Vacancy, skillNames []string, changedBy *uint, changedByType model.UserType) error {
+	prepareDescription(vac)
+	vu.currency.Normalize(vac)
 	if err := vu.vacancyRepo.UpdateVacancy(vac); err != nil {
 		return err
 	}
 
 	if skillNames != nil {
-		var skillIDs []uint
-		for _, name := range skillNames {
-			s, err := vu.skillRepo.GetByName(name)
-			if err == sql.ErrNoRows {
-				s = model.Skill{Name: name}
-				if err := vu.skillRepo.Create(&s); err != nil {
-					return err
-				}
-			} else if err != nil {
-				return err
-			}
-			skillIDs = append(skillIDs, s.ID)
-		}
-		if err := vu.vacancyRepo.SetSkills(vac.ID, skillIDs); err != nil {
+		if err := vu.setVacancySkills(vac.ID, skillNames); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return vu.history.RecordVersion(vac.ID, changedBy, changedByType)
+}
+
+// prepareDescription renders v.DescriptionMarkdown into v.Description when
+// set, sanitizes v.Description and derives v.DescriptionText from it.
+// Requirements are reduced to plain text: they are shown as such, but come
+// with markup such as the search highlighting in HH snippets. Every write goes
+// through it, so a stored description is always safe HTML.
+func prepareDescription(v *model.Vacancy) {
+	if v.DescriptionMarkdown != "" {
+		v.Description = richtext.Markdown(v.DescriptionMarkdown)
+	}
+	v.Description = richtext.Sanitize(v.Description)
+	v.DescriptionText = richtext.PlainText(v.Description)
+	v.Requirements = requirementsText(v.Requirements)
+}
+
+// requirementsText reduces requirements to plain text, keeping the line
+// breaks employers type, which PlainText would otherwise collapse.
+func requirementsText(s string) string {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "<br>")
+	return richtext.PlainText(richtext.Sanitize(s))
+}
+
+// SanitizeStoredDescriptions sanitizes, batch by batch, the descriptions and
+// requirements stored before sanitization on write, and returns how many
+// vacancies it rewrote.
+func (vu *VacancyUsecase) SanitizeStoredDescriptions(ctx context.Context, batch int) (int, error) {
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		vacs, err := vu.vacancyRepo.GetUnsanitizedDescriptions(batch)
+		if err != nil {
+			return total, err
+		}
+		for i := range vacs {
+			prepareDescription(&vacs[i])
+			if err := vu.vacancyRepo.SetDescription(vacs[i].ID, vacs[i].Description, vacs[i].DescriptionText, vacs[i].Requirements); err != nil {
+				return total, err
+			}
+		}
+		total += len(vacs)
+		if len(vacs) < batch {
+			return total, nil
+		}
+	}
 }
 
 func (vu *VacancyUsecase) GetAllRegions() ([]string, error) {
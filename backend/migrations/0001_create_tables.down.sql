@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS hackathons;
+DROP TABLE IF EXISTS resumes;
+DROP TABLE IF EXISTS student_skills;
+DROP TABLE IF EXISTS vacancy_skills;
+DROP TABLE IF EXISTS skills;
+DROP TABLE IF EXISTS applications;
+DROP INDEX IF EXISTS idx_vacancy_url_not_null;
+DROP TABLE IF EXISTS vacancies;
+DROP TABLE IF EXISTS admin_profiles;
+DROP TABLE IF EXISTS employer_profiles;
+DROP TABLE IF EXISTS student_profiles;
+DROP TABLE IF EXISTS users;
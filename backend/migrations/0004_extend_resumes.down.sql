@@ -0,0 +1,10 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS resume_id;
+
+DROP INDEX IF EXISTS idx_resumes_one_primary;
+DROP INDEX IF EXISTS idx_resumes_student_id;
+
+ALTER TABLE resumes DROP COLUMN IF EXISTS is_primary;
+ALTER TABLE resumes DROP COLUMN IF EXISTS version;
+ALTER TABLE resumes DROP COLUMN IF EXISTS size_bytes;
+ALTER TABLE resumes DROP COLUMN IF EXISTS content_type;
+ALTER TABLE resumes DROP COLUMN IF EXISTS storage_key;
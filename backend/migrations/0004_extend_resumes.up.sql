@@ -0,0 +1,10 @@
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS storage_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS content_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS size_bytes BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE resumes ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_resumes_student_id ON resumes(student_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resumes_one_primary ON resumes(student_id) WHERE is_primary;
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS resume_id INT REFERENCES resumes(id) ON DELETE SET NULL;
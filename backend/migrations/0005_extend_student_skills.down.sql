@@ -0,0 +1,4 @@
+ALTER TABLE student_skills DROP CONSTRAINT IF EXISTS student_skills_years_experience_check;
+ALTER TABLE student_skills DROP CONSTRAINT IF EXISTS student_skills_proficiency_level_check;
+ALTER TABLE student_skills DROP COLUMN IF EXISTS years_experience;
+ALTER TABLE student_skills DROP COLUMN IF EXISTS proficiency_level;
@@ -0,0 +1,10 @@
+ALTER TABLE student_skills ADD COLUMN IF NOT EXISTS proficiency_level VARCHAR(20) NOT NULL DEFAULT 'INTERMEDIATE';
+ALTER TABLE student_skills ADD COLUMN IF NOT EXISTS years_experience NUMERIC(4,1) NOT NULL DEFAULT 0;
+
+ALTER TABLE student_skills DROP CONSTRAINT IF EXISTS student_skills_proficiency_level_check;
+ALTER TABLE student_skills ADD CONSTRAINT student_skills_proficiency_level_check
+    CHECK (proficiency_level IN ('BEGINNER', 'INTERMEDIATE', 'ADVANCED', 'EXPERT'));
+
+ALTER TABLE student_skills DROP CONSTRAINT IF EXISTS student_skills_years_experience_check;
+ALTER TABLE student_skills ADD CONSTRAINT student_skills_years_experience_check
+    CHECK (years_experience >= 0 AND years_experience <= 50);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS application_status_history (
+    id SERIAL PRIMARY KEY,
+    application_id INT NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    changed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    changed_by_type VARCHAR(20) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_status_history_app
+    ON application_status_history(application_id, changed_at);
+
+-- Existing applications get a synthetic timeline: the submission and, when
+-- the status has moved since, a single step to the current status.
+INSERT INTO application_status_history (application_id, from_status, to_status, changed_by, changed_by_type, changed_at)
+SELECT id, NULL, 'APPLIED', student_id, 'STUDENT', submitted_date
+FROM applications;
+
+INSERT INTO application_status_history (application_id, from_status, to_status, changed_at)
+SELECT id, 'APPLIED', status, updated_date
+FROM applications
+WHERE status <> 'APPLIED';
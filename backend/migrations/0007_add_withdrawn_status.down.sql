@@ -0,0 +1,7 @@
+-- The old constraint has no WITHDRAWN; such applications are closed as REJECTED.
+UPDATE applications SET status = 'REJECTED' WHERE status = 'WITHDRAWN';
+
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+ALTER TABLE applications ADD CONSTRAINT applications_status_check CHECK (
+    status IN ('APPLIED', 'CV_SCREENING', 'INTERVIEW_SCHEDULED', 'INTERVIEW_COMPLETED', 'OFFER_EXTENDED', 'ACCEPTED', 'REJECTED')
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_vacancies_search_vector;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS search_vector;
+
+DROP TRIGGER IF EXISTS trg_skill_rename ON skills;
+DROP FUNCTION IF EXISTS skill_rename_trigger();
+DROP TRIGGER IF EXISTS trg_vacancy_skills_text ON vacancy_skills;
+DROP FUNCTION IF EXISTS vacancy_skills_text_trigger();
+DROP FUNCTION IF EXISTS refresh_vacancy_skills_text(INT);
+
+ALTER TABLE vacancies DROP COLUMN IF EXISTS skills_text;
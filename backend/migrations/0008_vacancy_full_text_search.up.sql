@@ -0,0 +1,73 @@
+-- Skill names are denormalized into vacancies.skills_text so that the
+-- generated search vector can cover them.
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS skills_text TEXT NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION refresh_vacancy_skills_text(p_vacancy_id INT) RETURNS VOID AS $$
+BEGIN
+    UPDATE vacancies
+    SET skills_text = COALESCE((
+        SELECT string_agg(s.name, ' ' ORDER BY s.name)
+        FROM vacancy_skills vs
+        JOIN skills s ON s.id = vs.skill_id
+        WHERE vs.vacancy_id = p_vacancy_id
+    ), '')
+    WHERE id = p_vacancy_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION vacancy_skills_text_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM refresh_vacancy_skills_text(NEW.vacancy_id);
+    END IF;
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        PERFORM refresh_vacancy_skills_text(OLD.vacancy_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_vacancy_skills_text ON vacancy_skills;
+CREATE TRIGGER trg_vacancy_skills_text
+    AFTER INSERT OR UPDATE OR DELETE ON vacancy_skills
+    FOR EACH ROW EXECUTE FUNCTION vacancy_skills_text_trigger();
+
+CREATE OR REPLACE FUNCTION skill_rename_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_vacancy_skills_text(vs.vacancy_id)
+    FROM vacancy_skills vs
+    WHERE vs.skill_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_skill_rename ON skills;
+CREATE TRIGGER trg_skill_rename
+    AFTER UPDATE OF name ON skills
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION skill_rename_trigger();
+
+UPDATE vacancies v
+SET skills_text = sk.names
+FROM (
+    SELECT vs.vacancy_id, string_agg(s.name, ' ' ORDER BY s.name) AS names
+    FROM vacancy_skills vs
+    JOIN skills s ON s.id = vs.skill_id
+    GROUP BY vs.vacancy_id
+) sk
+WHERE sk.vacancy_id = v.id;
+
+-- Vacancies are written in both Russian and English, so every field is
+-- indexed with both configurations. Weights: title > skills > requirements > description.
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('russian', skills_text), 'B') ||
+    setweight(to_tsvector('english', skills_text), 'B') ||
+    setweight(to_tsvector('russian', COALESCE(requirements, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(requirements, '')), 'C') ||
+    setweight(to_tsvector('russian', COALESCE(description, '')), 'D') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'D')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_vacancies_search_vector ON vacancies USING GIN (search_vector);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_vacancies_posted_date_id;
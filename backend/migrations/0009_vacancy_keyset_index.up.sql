@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_vacancies_posted_date_id ON vacancies(posted_date DESC, id DESC);
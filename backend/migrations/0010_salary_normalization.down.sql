@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_vacancies_salary_to_kzt;
+DROP INDEX IF EXISTS idx_vacancies_salary_from_kzt;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS salary_to_kzt;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS salary_from_kzt;
+DROP TABLE IF EXISTS currency_rates;
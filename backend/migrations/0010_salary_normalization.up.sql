@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS currency_rates (
+    code VARCHAR(3) PRIMARY KEY,
+    rate_to_kzt NUMERIC NOT NULL CHECK (rate_to_kzt > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Starting values only; real rates are loaded through the admin API or the
+-- CURRENCY_RATES_FILE provider. HH reports roubles as RUR.
+INSERT INTO currency_rates (code, rate_to_kzt) VALUES
+    ('KZT', 1),
+    ('USD', 500),
+    ('EUR', 540),
+    ('RUB', 5.5),
+    ('RUR', 5.5)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS salary_from_kzt NUMERIC;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS salary_to_kzt NUMERIC;
+
+-- 0.81 is the default SALARY_NET_RATIO; the application recomputes these
+-- columns whenever rates are reloaded.
+UPDATE vacancies v
+SET salary_from_kzt = CASE WHEN v.salary_from > 0 THEN v.salary_from * r.rate_to_kzt * CASE WHEN v.salary_gross THEN 0.81 ELSE 1 END END,
+    salary_to_kzt   = CASE WHEN v.salary_to > 0 THEN v.salary_to * r.rate_to_kzt * CASE WHEN v.salary_gross THEN 0.81 ELSE 1 END END
+FROM currency_rates r
+WHERE r.code = COALESCE(NULLIF(UPPER(v.salary_currency), ''), 'KZT');
+
+CREATE INDEX IF NOT EXISTS idx_vacancies_salary_from_kzt ON vacancies(salary_from_kzt);
+CREATE INDEX IF NOT EXISTS idx_vacancies_salary_to_kzt ON vacancies(salary_to_kzt);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_vacancies_expires_at;
+DROP INDEX IF EXISTS idx_vacancies_publish_at;
+DROP INDEX IF EXISTS idx_vacancies_listed_posted;
+
+-- Soft-deleted vacancies did not exist before this migration.
+DELETE FROM vacancies WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE vacancies DROP CONSTRAINT IF EXISTS vacancies_status_check;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS status;
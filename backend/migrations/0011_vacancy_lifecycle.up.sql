@@ -0,0 +1,23 @@
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'PUBLISHED';
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+ALTER TABLE vacancies DROP CONSTRAINT IF EXISTS vacancies_status_check;
+ALTER TABLE vacancies ADD CONSTRAINT vacancies_status_check CHECK (
+    status IN ('DRAFT', 'PUBLISHED', 'PAUSED', 'CLOSED', 'ARCHIVED')
+);
+
+-- Public listings only ever read published, non-deleted rows.
+CREATE INDEX IF NOT EXISTS idx_vacancies_listed_posted
+    ON vacancies (posted_date DESC, id DESC)
+    WHERE status = 'PUBLISHED' AND deleted_at IS NULL;
+
+-- Lookups for the lifecycle job.
+CREATE INDEX IF NOT EXISTS idx_vacancies_publish_at
+    ON vacancies (publish_at)
+    WHERE status = 'DRAFT' AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_vacancies_expires_at
+    ON vacancies (expires_at)
+    WHERE status IN ('PUBLISHED', 'PAUSED') AND deleted_at IS NULL;
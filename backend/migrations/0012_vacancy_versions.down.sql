@@ -0,0 +1,3 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS vacancy_changed_at;
+ALTER TABLE applications DROP COLUMN IF EXISTS vacancy_version;
+DROP TABLE IF EXISTS vacancy_versions;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS vacancy_versions (
+    id SERIAL PRIMARY KEY,
+    vacancy_id INT NOT NULL REFERENCES vacancies(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    snapshot JSONB NOT NULL,
+    changed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    changed_by_type VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (vacancy_id, version)
+);
+
+-- vacancy_version is the version a student applied to; vacancy_changed_at is
+-- set when the offer changes afterwards.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS vacancy_version INT;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS vacancy_changed_at TIMESTAMP;
+
+-- Every existing vacancy starts from its current state as version 1. The keys
+-- must match model.VacancySnapshot.
+INSERT INTO vacancy_versions (vacancy_id, version, snapshot, created_at)
+SELECT v.id, 1,
+       jsonb_build_object(
+           'title', v.title,
+           'description', COALESCE(v.description, ''),
+           'requirements', COALESCE(v.requirements, ''),
+           'location', v.location,
+           'salary_from', COALESCE(v.salary_from, 0),
+           'salary_to', COALESCE(v.salary_to, 0),
+           'salary_currency', COALESCE(v.salary_currency, ''),
+           'salary_gross', COALESCE(v.salary_gross, FALSE),
+           'vacancy_url', COALESCE(v.vacancy_url, ''),
+           'work_schedule', COALESCE(v.work_schedule, ''),
+           'experience', COALESCE(v.experience, ''),
+           'skills', COALESCE((
+               SELECT jsonb_agg(s.name ORDER BY s.name)
+               FROM vacancy_skills vs
+               JOIN skills s ON s.id = vs.skill_id
+               WHERE vs.vacancy_id = v.id
+           ), '[]'::jsonb)
+       ),
+       COALESCE(v.updated_at, v.created_at, CURRENT_TIMESTAMP)
+FROM vacancies v
+ON CONFLICT (vacancy_id, version) DO NOTHING;
+
+UPDATE applications SET vacancy_version = 1 WHERE vacancy_version IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_vacancies_updated_at;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS saved_searches;
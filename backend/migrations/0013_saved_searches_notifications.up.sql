@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ml_skills TEXT NOT NULL DEFAULT '',
+    digest VARCHAR(10) NOT NULL DEFAULT 'DAILY' CHECK (digest IN ('NONE', 'DAILY', 'WEEKLY')),
+    last_checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_digest_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_student ON saved_searches (student_id);
+
+-- dedup_key lets producers make a notification idempotent per user; rows
+-- without one are never deduplicated.
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(40) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    vacancy_id INT REFERENCES vacancies(id) ON DELETE CASCADE,
+    saved_search_id INT REFERENCES saved_searches(id) ON DELETE CASCADE,
+    dedup_key VARCHAR(200),
+    read_at TIMESTAMP,
+    emailed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, dedup_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Saved searches pick up vacancies created or changed since their last run.
+CREATE INDEX IF NOT EXISTS idx_vacancies_updated_at ON vacancies (updated_at);
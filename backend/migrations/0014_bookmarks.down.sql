@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS hackathon_bookmarks;
+DROP TABLE IF EXISTS vacancy_bookmarks;
@@ -0,0 +1,20 @@
+-- expiry_notified_for is the expires_at the student was last warned about,
+-- so extending a vacancy warns again. closed_notified_at is compared with
+-- posted_date, which moves when a closed vacancy is published again.
+CREATE TABLE IF NOT EXISTS vacancy_bookmarks (
+    student_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vacancy_id INT NOT NULL REFERENCES vacancies(id) ON DELETE CASCADE,
+    expiry_notified_for TIMESTAMP,
+    closed_notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (student_id, vacancy_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vacancy_bookmarks_vacancy ON vacancy_bookmarks (vacancy_id);
+
+CREATE TABLE IF NOT EXISTS hackathon_bookmarks (
+    student_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hackathon_id INT NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (student_id, hackathon_id)
+);
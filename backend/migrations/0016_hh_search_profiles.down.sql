@@ -0,0 +1 @@
+DROP TABLE IF EXISTS hh_search_profiles;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS hh_search_profiles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    areas TEXT[] NOT NULL DEFAULT '{}',
+    professional_roles TEXT[] NOT NULL DEFAULT '{}',
+    text TEXT NOT NULL DEFAULT '',
+    only_with_salary BOOLEAN NOT NULL DEFAULT FALSE,
+    period_days INT NOT NULL DEFAULT 0 CHECK (period_days BETWEEN 0 AND 30),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The query the importer used to hard-code: IT roles across Kazakhstan,
+-- salaried vacancies only.
+INSERT INTO hh_search_profiles (name, areas, professional_roles, only_with_salary)
+VALUES (
+    'kz-it',
+    '{40}',
+    '{156,160,10,12,150,25,165,34,36,73,155,96,164,104,157,107,112,113,148,114,116,121,124,125,126}',
+    TRUE
+)
+ON CONFLICT (name) DO NOTHING;
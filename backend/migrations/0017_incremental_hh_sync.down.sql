@@ -0,0 +1,3 @@
+ALTER TABLE sync_runs DROP COLUMN IF EXISTS full_resync;
+
+ALTER TABLE hh_search_profiles DROP COLUMN IF EXISTS synced_until;
@@ -0,0 +1,3 @@
+ALTER TABLE hh_search_profiles ADD COLUMN IF NOT EXISTS synced_until TIMESTAMP;
+
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS full_resync BOOLEAN NOT NULL DEFAULT FALSE;
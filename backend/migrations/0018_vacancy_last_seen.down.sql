@@ -0,0 +1,6 @@
+ALTER TABLE sync_runs DROP COLUMN IF EXISTS closed;
+
+DROP INDEX IF EXISTS idx_vacancies_last_seen;
+
+ALTER TABLE vacancies DROP COLUMN IF EXISTS last_checked_at;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS last_seen_at;
@@ -0,0 +1,13 @@
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS last_checked_at TIMESTAMP;
+
+-- Only imported vacancies have last_seen_at; treat every HH vacancy imported
+-- so far as seen when it last changed.
+UPDATE vacancies
+SET last_seen_at = COALESCE(updated_at, created_at)
+WHERE last_seen_at IS NULL AND vacancy_url ~ '^https?://([a-z0-9-]+\.)*hh\.(ru|kz)/vacancy/[0-9]+';
+
+CREATE INDEX IF NOT EXISTS idx_vacancies_last_seen ON vacancies (last_seen_at)
+    WHERE last_seen_at IS NOT NULL AND status IN ('PUBLISHED', 'PAUSED') AND deleted_at IS NULL;
+
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS closed INT NOT NULL DEFAULT 0;
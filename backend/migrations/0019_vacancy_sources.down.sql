@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS imported_employers;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vacancy_url_not_null
+    ON vacancies(vacancy_url)
+    WHERE vacancy_url IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_vacancies_source_external_id;
+
+ALTER TABLE vacancies DROP CONSTRAINT IF EXISTS vacancies_source_external_id_check;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS external_id;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS source;
@@ -0,0 +1,43 @@
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS source VARCHAR(40);
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS external_id VARCHAR(100);
+
+-- Every vacancy imported so far came from HH, which puts its id in the URL.
+-- hh.ru and hh.kz URLs of the same vacancy keep the latest row.
+UPDATE vacancies v
+SET source = 'hh', external_id = hh.external_id
+FROM (
+    SELECT DISTINCT ON (substring(vacancy_url from '/vacancy/([0-9]+)'))
+           id, substring(vacancy_url from '/vacancy/([0-9]+)') AS external_id
+    FROM vacancies
+    WHERE source IS NULL AND vacancy_url ~ '^https?://([a-z0-9-]+\.)*hh\.(ru|kz)/vacancy/[0-9]+'
+    ORDER BY substring(vacancy_url from '/vacancy/([0-9]+)'), id DESC
+) hh
+WHERE v.id = hh.id;
+
+ALTER TABLE vacancies ADD CONSTRAINT vacancies_source_external_id_check
+    CHECK ((source IS NULL) = (external_id IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vacancies_source_external_id
+    ON vacancies (source, external_id)
+    WHERE source IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_vacancy_url_not_null;
+
+-- Maps a source's employer ids to the employer accounts created for them.
+CREATE TABLE IF NOT EXISTS imported_employers (
+    source VARCHAR(40) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, external_id)
+);
+
+-- HH employers were created with their HH id as user id.
+INSERT INTO imported_employers (source, external_id, user_id)
+SELECT DISTINCT 'hh', employer_id::text, employer_id
+FROM vacancies
+WHERE source = 'hh'
+ON CONFLICT DO NOTHING;
+
+-- Those ids bypassed the sequence; move it past them.
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST((SELECT MAX(id) FROM users), 1));
@@ -0,0 +1,39 @@
+-- Imported companies go back to placeholder employer accounts.
+CREATE TABLE IF NOT EXISTS imported_employers (
+    source VARCHAR(40) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, external_id)
+);
+
+WITH created AS (
+    INSERT INTO users (email, password, user_type, created_at, updated_at)
+    SELECT c.source || '-' || md5(c.source || '/' || c.external_id) || '@imported.example.com',
+           'dummy', 'EMPLOYER', c.created_at, c.updated_at
+    FROM companies c
+    WHERE c.source IS NOT NULL AND c.owner_id IS NULL
+    RETURNING id, email
+)
+INSERT INTO imported_employers (source, external_id, user_id)
+SELECT c.source, c.external_id, created.id
+FROM companies c
+JOIN created ON created.email = c.source || '-' || md5(c.source || '/' || c.external_id) || '@imported.example.com';
+
+INSERT INTO employer_profiles (user_id, company_name, company_description, contact_info)
+SELECT ie.user_id, c.name, c.description, ''
+FROM imported_employers ie
+JOIN companies c ON c.source = ie.source AND c.external_id = ie.external_id;
+
+UPDATE vacancies v
+SET employer_id = COALESCE(c.owner_id, ie.user_id)
+FROM companies c
+LEFT JOIN imported_employers ie ON ie.source = c.source AND ie.external_id = c.external_id
+WHERE v.company_id = c.id AND v.employer_id IS NULL;
+
+ALTER TABLE vacancies DROP CONSTRAINT IF EXISTS vacancies_owner_check;
+DROP INDEX IF EXISTS idx_vacancies_company;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS company_id;
+ALTER TABLE vacancies ALTER COLUMN employer_id SET NOT NULL;
+
+DROP TABLE IF EXISTS companies;
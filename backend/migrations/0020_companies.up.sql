@@ -0,0 +1,69 @@
+-- A company is the public identity vacancies are posted under. Imported
+-- companies are keyed by their source's id; a registered employer's own
+-- company has no source. owner_id is the employer account that manages the
+-- company, if any.
+CREATE TABLE IF NOT EXISTS companies (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(40),
+    external_id VARCHAR(100),
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((source IS NULL) = (external_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_source_external_id
+    ON companies (source, external_id)
+    WHERE source IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_employer
+    ON companies (owner_id)
+    WHERE source IS NULL;
+CREATE INDEX IF NOT EXISTS idx_companies_owner ON companies (owner_id);
+
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS company_id INT REFERENCES companies(id);
+ALTER TABLE vacancies ALTER COLUMN employer_id DROP NOT NULL;
+
+-- Imported employers were placeholder accounts; they become companies
+-- without an owner and their vacancies lose the account.
+INSERT INTO companies (source, external_id, name, description)
+SELECT ie.source, ie.external_id,
+       COALESCE(NULLIF(ep.company_name, ''), 'Unknown company'), COALESCE(ep.company_description, '')
+FROM imported_employers ie
+LEFT JOIN employer_profiles ep ON ep.user_id = ie.user_id
+ON CONFLICT DO NOTHING;
+
+UPDATE vacancies v
+SET company_id = c.id, employer_id = NULL
+FROM imported_employers ie
+JOIN companies c ON c.source = ie.source AND c.external_id = ie.external_id
+WHERE v.source = ie.source AND v.employer_id = ie.user_id;
+
+-- A registered employer whose id happened to collide with an HH employer
+-- keeps the account; only the placeholders, which never had a real
+-- password, are deleted.
+DELETE FROM users u
+USING imported_employers ie
+WHERE u.id = ie.user_id AND u.password = 'dummy'
+  AND NOT EXISTS (SELECT 1 FROM vacancies v WHERE v.employer_id = u.id);
+
+DROP TABLE IF EXISTS imported_employers;
+
+INSERT INTO companies (name, description, owner_id)
+SELECT COALESCE(NULLIF(ep.company_name, ''), u.email), COALESCE(ep.company_description, ''), u.id
+FROM users u
+LEFT JOIN employer_profiles ep ON ep.user_id = u.id
+WHERE u.user_type = 'EMPLOYER'
+ON CONFLICT DO NOTHING;
+
+UPDATE vacancies v
+SET company_id = c.id
+FROM companies c
+WHERE v.company_id IS NULL AND c.owner_id = v.employer_id AND c.source IS NULL;
+
+ALTER TABLE vacancies ALTER COLUMN company_id SET NOT NULL;
+ALTER TABLE vacancies ADD CONSTRAINT vacancies_owner_check
+    CHECK (employer_id IS NOT NULL OR source IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_vacancies_company ON vacancies (company_id);
@@ -0,0 +1,27 @@
+-- An employer's request to take over an imported company. A claim is
+-- PENDING until the employer confirms the token mailed to the company
+-- address, then VERIFIED until an admin approves or denies it.
+CREATE TABLE IF NOT EXISTS company_claims (
+    id SERIAL PRIMARY KEY,
+    company_id INT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    employer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'VERIFIED', 'APPROVED', 'DENIED')),
+    token_hash VARCHAR(64),
+    token_expires_at TIMESTAMP,
+    verified_at TIMESTAMP,
+    reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_claims_open
+    ON company_claims (company_id, employer_id)
+    WHERE status IN ('PENDING', 'VERIFIED');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_claims_token
+    ON company_claims (token_hash)
+    WHERE token_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_company_claims_status ON company_claims (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_company_claims_employer ON company_claims (employer_id, created_at DESC);
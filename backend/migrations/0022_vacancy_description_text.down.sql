@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_vacancies_description_unsanitized;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS description_markdown;
+ALTER TABLE vacancies DROP COLUMN IF EXISTS description_text;
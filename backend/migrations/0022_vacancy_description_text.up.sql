@@ -0,0 +1,9 @@
+-- vacancies.description now holds sanitized HTML. description_text is its
+-- plain-text rendering for previews and search snippets; it is NULL for rows
+-- written before sanitization, which the server backfills on startup.
+-- description_markdown keeps the source of descriptions written in Markdown.
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS description_text TEXT;
+ALTER TABLE vacancies ADD COLUMN IF NOT EXISTS description_markdown TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_vacancies_description_unsanitized
+    ON vacancies (id) WHERE description_text IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_skills_name_lower;
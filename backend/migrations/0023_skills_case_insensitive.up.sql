@@ -0,0 +1,26 @@
+-- Skill names are unique regardless of case. Rows that differ only in case
+-- are merged into the oldest one first, keeping every vacancy and student
+-- link they had.
+INSERT INTO vacancy_skills (vacancy_id, skill_id, created_at, updated_at)
+SELECT vs.vacancy_id, k.keep_id, vs.created_at, vs.updated_at
+FROM vacancy_skills vs
+JOIN skills s ON s.id = vs.skill_id
+JOIN (SELECT lower(name) AS lname, MIN(id) AS keep_id FROM skills GROUP BY lower(name)) k
+  ON k.lname = lower(s.name)
+WHERE s.id <> k.keep_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO student_skills (student_id, skill_id, created_at, updated_at, proficiency_level, years_experience)
+SELECT ss.student_id, k.keep_id, ss.created_at, ss.updated_at, ss.proficiency_level, ss.years_experience
+FROM student_skills ss
+JOIN skills s ON s.id = ss.skill_id
+JOIN (SELECT lower(name) AS lname, MIN(id) AS keep_id FROM skills GROUP BY lower(name)) k
+  ON k.lname = lower(s.name)
+WHERE s.id <> k.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM skills s
+USING (SELECT lower(name) AS lname, MIN(id) AS keep_id FROM skills GROUP BY lower(name)) k
+WHERE lower(s.name) = k.lname AND s.id <> k.keep_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skills_name_lower ON skills (lower(name));
@@ -0,0 +1,3 @@
+-- website is the company's own site as its source reports it. Claims to the
+-- company must use an email address at the site's domain.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS website TEXT NOT NULL DEFAULT '';
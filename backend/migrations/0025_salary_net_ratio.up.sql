@@ -0,0 +1,10 @@
+-- The net ratio the stored salary_*_kzt columns were computed with. 0010
+-- filled them with 0.81; the application renormalizes on startup whenever
+-- SALARY_NET_RATIO differs from the ratio recorded here.
+CREATE TABLE IF NOT EXISTS salary_normalization (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    net_ratio NUMERIC NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO salary_normalization (net_ratio) VALUES (0.81) ON CONFLICT (id) DO NOTHING;
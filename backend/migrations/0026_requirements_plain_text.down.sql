@@ -0,0 +1,2 @@
+-- Requirements rewritten as plain text cannot be restored.
+SELECT 1;
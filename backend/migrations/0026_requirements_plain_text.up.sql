@@ -0,0 +1,6 @@
+-- Requirements are now stored as plain text. Rows whose requirements may
+-- hold markup or entities, such as HH's <highlighttext>, are queued for the
+-- startup description backfill again, which rewrites both fields.
+UPDATE vacancies
+SET description_text = NULL
+WHERE requirements LIKE '%<%' OR requirements LIKE '%&%';
@@ -0,0 +1 @@
+ALTER TABLE vacancies ALTER COLUMN posted_date DROP NOT NULL;
@@ -0,0 +1,4 @@
+-- Cursor pages compare (posted_date, id), which no NULL posted_date
+-- satisfies, so such vacancies would drop out of every page.
+UPDATE vacancies SET posted_date = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE posted_date IS NULL;
+ALTER TABLE vacancies ALTER COLUMN posted_date SET NOT NULL;